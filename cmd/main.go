@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/logging"
 	tui "github.com/kontrolplane/kue/pkg/tui"
 )
 
@@ -15,18 +18,72 @@ var (
 )
 
 func Execute() {
-	f, err := tea.LogToFile("debug.log", "debug")
+	configFile := flag.String("config", config.Path(), "path of the configuration file")
+	endpoint := flag.String("endpoint", "", "custom SQS endpoint, e.g. http://localhost:4566 for LocalStack")
+	profile := flag.String("profile", "", "AWS profile used on start")
+	region := flag.String("region", "", "AWS region used on start")
+	refresh := flag.Duration("refresh", 0, "interval between refreshes, e.g. 10s")
+	fetchCount := flag.Int("fetch-count", 0, "messages received per refresh, at most 10")
+	logFile := flag.String("log-file", "", "log file, empty to disable")
+	logLevel := flag.String("log-level", "", "log level: trace, debug, info, warn or error")
+	logFormat := flag.String("log-format", "", "log format: text or json")
+	theme := flag.String("theme", "", "color theme: dark, light, high-contrast or monochrome")
+	readOnly := flag.Bool("read-only", false, "disable creating, deleting, purging, redriving and sending")
+	flag.Parse()
+
+	cfg, err := config.LoadFile(*configFile)
+	if err != nil {
+		fmt.Println("Error loading config:", err)
+		os.Exit(1)
+	}
+
+	// Flags that are set take precedence over the configuration file
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "endpoint":
+			cfg.AWS.EndpointOverride = *endpoint
+		case "profile":
+			cfg.AWS.Profile = *profile
+		case "region":
+			cfg.AWS.Region = *region
+		case "refresh":
+			cfg.RefreshInterval = *refresh
+		case "fetch-count":
+			cfg.Messages.FetchCount = int32(*fetchCount)
+		case "log-file":
+			cfg.Log.File = *logFile
+		case "log-level":
+			cfg.Log.Level = *logLevel
+		case "log-format":
+			cfg.Log.Format = *logFormat
+		case "theme":
+			cfg.Theme.Name = *theme
+		case "read-only":
+			cfg.ReadOnly = *readOnly
+		}
+	})
+	if err := cfg.Validate(); err != nil {
+		fmt.Println("Invalid configuration:", err)
+		os.Exit(1)
+	}
+
+	logger, f, err := logging.Open(cfg.Log.File, cfg.Log.Level, cfg.Log.Format)
 	if err != nil {
 		fmt.Println("Couldn't open a file for logging:", err)
 		os.Exit(1)
 	}
 	defer f.Close()
+	// Entries of the standard log package end up in the same log
+	slog.SetDefault(logger)
 
-	log.SetOutput(f)
+	slog.Info("starting", "config", *configFile, "log_level", cfg.Log.Level)
 
-	log.Println("Debug logging initialized")
+	uiState, err := config.LoadState()
+	if err != nil {
+		slog.Warn("failed to load the view state, using defaults", "err", err)
+	}
 
-	model, err := tui.NewModel(projectName, programName)
+	model, err := tui.NewModel(projectName, programName, cfg, uiState)
 	if err != nil || model == nil {
 		fmt.Println("Error creating model:", err)
 		os.Exit(1)
@@ -0,0 +1,316 @@
+// Package alert evaluates threshold rules against queue metrics.
+package alert
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+// Severity is the importance of an alert.
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Critical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	}
+	return "critical"
+}
+
+// ParseSeverity parses a severity name, defaulting to critical.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "info":
+		return Info, nil
+	case "warning", "warn":
+		return Warning, nil
+	case "critical", "":
+		return Critical, nil
+	}
+	return Critical, fmt.Errorf("unknown severity %q", s)
+}
+
+// Metrics are the values of a queue that rules are evaluated against.
+// Unknown values are negative.
+type Metrics struct {
+	QueueUrl     string
+	QueueName    string
+	QueueContext string // named context the queue was loaded from
+	Visible      int
+	InFlight     int
+	Delayed      int
+	DLQ          int           // available messages in the queue's dead-letter queue
+	Oldest       time.Duration // age of the oldest message
+}
+
+// Alert is a rule matching a queue.
+type Alert struct {
+	Rule         string
+	Severity     Severity
+	QueueUrl     string
+	QueueName    string
+	QueueContext string
+	Message      string // e.g. "dlq 12 > 0"
+}
+
+// condition is a parsed rule condition such as "visible > 10000".
+type condition struct {
+	metric string
+	op     string
+	value  float64
+}
+
+type rule struct {
+	name      string
+	pattern   string
+	severity  Severity
+	condition condition
+}
+
+// Rules are compiled alert rules. The zero value has no rules.
+type Rules struct {
+	rules  []rule
+	active map[string]bool // rule and queue pairs that matched last evaluation
+	primed bool
+}
+
+// metrics that can be used in conditions.
+var metrics = map[string]bool{
+	"visible":  true,
+	"inflight": true,
+	"delayed":  true,
+	"dlq":      true,
+	"oldest":   true,
+}
+
+// Compile validates and compiles the configured rules.
+func Compile(settings []config.AlertRule) (*Rules, error) {
+	r := &Rules{active: make(map[string]bool)}
+	for i, s := range settings {
+		name := s.Name
+		if name == "" {
+			name = s.Condition
+		}
+
+		cond, err := parseCondition(s.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("alert rule %d (%s): %w", i+1, name, err)
+		}
+		severity, err := ParseSeverity(s.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("alert rule %d (%s): %w", i+1, name, err)
+		}
+		pattern := s.Pattern
+		if pattern == "" {
+			pattern = "*"
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("alert rule %d (%s): invalid pattern %q", i+1, name, pattern)
+		}
+
+		r.rules = append(r.rules, rule{name: name, pattern: pattern, severity: severity, condition: cond})
+	}
+	return r, nil
+}
+
+// parseCondition parses "<metric> <op> <value>", e.g. "oldest > 15m" or
+// "visible >= 10,000".
+func parseCondition(s string) (condition, error) {
+	s = strings.TrimSpace(s)
+	for _, op := range []string{">=", "<=", "==", "!=", ">", "<", "="} {
+		idx := strings.Index(s, op)
+		if idx < 0 {
+			continue
+		}
+
+		metric := strings.ToLower(strings.TrimSpace(s[:idx]))
+		metric = strings.NewReplacer("-", "", "_", "").Replace(metric)
+		if !metrics[metric] {
+			return condition{}, fmt.Errorf("unknown metric %q", metric)
+		}
+
+		raw := strings.ReplaceAll(strings.TrimSpace(s[idx+len(op):]), ",", "")
+		value, err := parseValue(metric, raw)
+		if err != nil {
+			return condition{}, err
+		}
+		if op == "=" {
+			op = "=="
+		}
+		return condition{metric: metric, op: op, value: value}, nil
+	}
+	return condition{}, fmt.Errorf("invalid condition %q, expected e.g. \"visible > 100\"", s)
+}
+
+// parseValue parses a threshold, durations are converted to seconds.
+func parseValue(metric, raw string) (float64, error) {
+	if metric == "oldest" {
+		if n, err := strconv.ParseFloat(raw, 64); err == nil {
+			return n, nil
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", raw)
+		}
+		return d.Seconds(), nil
+	}
+	n, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", raw)
+	}
+	return n, nil
+}
+
+// valueOf returns the metric of m, and false if it is unknown.
+func (c condition) valueOf(m Metrics) (float64, bool) {
+	var v float64
+	switch c.metric {
+	case "visible":
+		v = float64(m.Visible)
+	case "inflight":
+		v = float64(m.InFlight)
+	case "delayed":
+		v = float64(m.Delayed)
+	case "dlq":
+		v = float64(m.DLQ)
+	case "oldest":
+		v = m.Oldest.Seconds()
+	}
+	return v, v >= 0
+}
+
+func (c condition) match(m Metrics) (string, bool) {
+	v, ok := c.valueOf(m)
+	if !ok {
+		return "", false
+	}
+
+	var matched bool
+	switch c.op {
+	case ">":
+		matched = v > c.value
+	case ">=":
+		matched = v >= c.value
+	case "<":
+		matched = v < c.value
+	case "<=":
+		matched = v <= c.value
+	case "!=":
+		matched = v != c.value
+	default:
+		matched = v == c.value
+	}
+	if !matched {
+		return "", false
+	}
+
+	if c.metric == "oldest" {
+		return fmt.Sprintf("oldest %s %s %s", time.Duration(v*float64(time.Second)).Round(time.Second), c.op, time.Duration(c.value*float64(time.Second))), true
+	}
+	return fmt.Sprintf("%s %g %s %g", c.metric, v, c.op, c.value), true
+}
+
+// Empty reports whether there are no rules.
+func (r *Rules) Empty() bool {
+	return r == nil || len(r.rules) == 0
+}
+
+// UsesMetric reports whether any rule depends on the metric, e.g. oldest.
+func (r *Rules) UsesMetric(metric string) bool {
+	if r == nil {
+		return false
+	}
+	for _, rule := range r.rules {
+		if rule.condition.metric == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsMetric reports whether a rule using the metric applies to the queue.
+func (r *Rules) NeedsMetric(metric, queueName string) bool {
+	if r == nil {
+		return false
+	}
+	for _, rule := range r.rules {
+		if ok, _ := path.Match(rule.pattern, queueName); ok && rule.condition.metric == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset forgets the active alerts, the next evaluation establishes a new
+// baseline.
+func (r *Rules) Reset() {
+	if r == nil {
+		return
+	}
+	r.active = make(map[string]bool)
+	r.primed = false
+}
+
+// Evaluate returns the alerts for the queues, most severe first, and the
+// alerts that were not active at the previous evaluation. The first
+// evaluation establishes the baseline and reports no new alerts.
+func (r *Rules) Evaluate(queues []Metrics) (alerts []Alert, started []Alert) {
+	if r.Empty() {
+		return nil, nil
+	}
+
+	active := make(map[string]bool)
+	for _, q := range queues {
+		for _, rule := range r.rules {
+			if ok, _ := path.Match(rule.pattern, q.QueueName); !ok {
+				continue
+			}
+			message, ok := rule.condition.match(q)
+			if !ok {
+				continue
+			}
+
+			a := Alert{Rule: rule.name, Severity: rule.severity, QueueUrl: q.QueueUrl, QueueName: q.QueueName, QueueContext: q.QueueContext, Message: message}
+			alerts = append(alerts, a)
+
+			key := rule.name + "\x00" + q.QueueContext + "\x00" + q.QueueUrl
+			active[key] = true
+			if r.primed && !r.active[key] {
+				started = append(started, a)
+			}
+		}
+	}
+
+	r.active = active
+	r.primed = true
+
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].Severity > alerts[j].Severity })
+	return alerts, started
+}
+
+// BySeverity returns the highest severity per queue.
+func BySeverity(alerts []Alert) map[kue.QueueKey]Severity {
+	severities := make(map[kue.QueueKey]Severity)
+	for _, a := range alerts {
+		key := kue.QueueKey{Context: a.QueueContext, Url: a.QueueUrl}
+		if s, ok := severities[key]; !ok || a.Severity > s {
+			severities[key] = a.Severity
+		}
+	}
+	return severities
+}
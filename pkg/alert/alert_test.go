@@ -0,0 +1,146 @@
+package alert
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+func testRules(t *testing.T) *Rules {
+	t.Helper()
+	rules, err := Compile([]config.AlertRule{
+		{Name: "dlq not empty", Condition: "dlq > 0"},
+		{Name: "backlog", Pattern: "orders-*", Condition: "visible > 10,000", Severity: "warning"},
+		{Name: "stuck", Condition: "oldest > 15m", Severity: "info"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to compile rules: %v", err)
+	}
+	return rules
+}
+
+func TestEvaluate(t *testing.T) {
+	rules := testRules(t)
+
+	alerts, started := rules.Evaluate([]Metrics{
+		{QueueUrl: "u/orders-eu", QueueName: "orders-eu", Visible: 20000, DLQ: 0, Oldest: 20 * time.Minute},
+		{QueueUrl: "u/billing", QueueName: "billing", Visible: 20000, DLQ: 3, Oldest: -1},
+		{QueueUrl: "u/unknown", QueueName: "unknown", Visible: -1, DLQ: -1, Oldest: -1},
+	})
+
+	if len(started) != 0 {
+		t.Errorf("Expected the first evaluation to report no new alerts, got %v", started)
+	}
+	if len(alerts) != 3 {
+		t.Fatalf("Expected 3 alerts, got %v", alerts)
+	}
+	if alerts[0].Rule != "dlq not empty" || alerts[0].QueueName != "billing" || alerts[0].Message != "dlq 3 > 0" {
+		t.Errorf("Expected the critical alert first, got %+v", alerts[0])
+	}
+	if alerts[2].Message != "oldest 20m0s > 15m0s" {
+		t.Errorf("Unexpected oldest message %q", alerts[2].Message)
+	}
+
+	severities := BySeverity(alerts)
+	if severities[kue.QueueKey{Url: "u/orders-eu"}] != Warning || severities[kue.QueueKey{Url: "u/billing"}] != Critical {
+		t.Errorf("Unexpected severities %v", severities)
+	}
+}
+
+func TestEvaluateReportsStartedAlerts(t *testing.T) {
+	rules := testRules(t)
+	healthy := Metrics{QueueUrl: "u/billing", QueueName: "billing", DLQ: 0, Oldest: -1}
+	failing := Metrics{QueueUrl: "u/billing", QueueName: "billing", DLQ: 5, Oldest: -1}
+
+	rules.Evaluate([]Metrics{healthy})
+
+	if _, started := rules.Evaluate([]Metrics{failing}); len(started) != 1 {
+		t.Errorf("Expected one new alert, got %v", started)
+	}
+	if _, started := rules.Evaluate([]Metrics{failing}); len(started) != 0 {
+		t.Errorf("Expected an ongoing alert not to be reported again, got %v", started)
+	}
+	rules.Evaluate([]Metrics{healthy})
+	if _, started := rules.Evaluate([]Metrics{failing}); len(started) != 1 {
+		t.Errorf("Expected a resolved alert to be reported again, got %v", started)
+	}
+
+	rules.Reset()
+	if _, started := rules.Evaluate([]Metrics{failing}); len(started) != 0 {
+		t.Errorf("Expected the first evaluation after a reset to be the baseline, got %v", started)
+	}
+}
+
+func TestEvaluateKeepsContextsApart(t *testing.T) {
+	rules := testRules(t)
+	prod := Metrics{QueueUrl: "u/billing", QueueName: "billing", QueueContext: "prod", DLQ: 5, Oldest: -1}
+	staging := Metrics{QueueUrl: "u/billing", QueueName: "billing", QueueContext: "staging", DLQ: 0, Oldest: -1}
+
+	rules.Evaluate([]Metrics{prod, staging})
+	staging.DLQ = 5
+	alerts, started := rules.Evaluate([]Metrics{prod, staging})
+	if len(started) != 1 || started[0].QueueContext != "staging" {
+		t.Errorf("Expected the alert of the staging queue to start, got %v", started)
+	}
+	if severities := BySeverity(alerts); len(severities) != 2 {
+		t.Errorf("Expected a severity per context, got %v", severities)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []config.AlertRule{
+		{Condition: "size > 3"},
+		{Condition: "visible"},
+		{Condition: "visible > lots"},
+		{Condition: "oldest > soon"},
+		{Condition: "dlq > 0", Severity: "panic"},
+		{Condition: "dlq > 0", Pattern: "["},
+	}
+
+	for _, rule := range tests {
+		if _, err := Compile([]config.AlertRule{rule}); err == nil {
+			t.Errorf("Expected an error for %+v", rule)
+		}
+	}
+}
+
+func TestUsesMetric(t *testing.T) {
+	rules := testRules(t)
+	if !rules.UsesMetric("oldest") || rules.UsesMetric("delayed") {
+		t.Error("Unexpected UsesMetric result")
+	}
+
+	if !rules.NeedsMetric("visible", "orders-eu") || rules.NeedsMetric("visible", "billing") {
+		t.Error("Expected the visible rule to apply only to orders queues")
+	}
+
+	var empty *Rules
+	if !empty.Empty() || empty.UsesMetric("oldest") {
+		t.Error("Expected nil rules to be empty")
+	}
+}
+
+func TestNotify(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "alerts")
+	settings := config.AlertSettings{
+		Command: `echo "$KUE_ALERT_SEVERITY $KUE_ALERT_QUEUE $KUE_ALERT_MESSAGE" >> ` + out,
+	}
+	alerts := []Alert{{Rule: "dlq", Severity: Critical, QueueName: "billing", Message: "dlq 3 > 0"}}
+
+	if err := Notify(settings, alerts); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Expected the command to run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "critical billing dlq 3 > 0" {
+		t.Errorf("Unexpected command output %q", got)
+	}
+}
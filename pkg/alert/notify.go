@@ -0,0 +1,32 @@
+package alert
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/kontrolplane/kue/pkg/config"
+)
+
+// Notify announces alerts that started matching by running the configured
+// command once per alert. The command receives the alert in the KUE_ALERT_*
+// environment variables. The terminal bell is rung by the TUI.
+func Notify(settings config.AlertSettings, alerts []Alert) error {
+	if settings.Command == "" {
+		return nil
+	}
+	for _, a := range alerts {
+		cmd := exec.Command("sh", "-c", settings.Command)
+		cmd.Env = append(os.Environ(),
+			"KUE_ALERT_RULE="+a.Rule,
+			"KUE_ALERT_SEVERITY="+a.Severity.String(),
+			"KUE_ALERT_QUEUE="+a.QueueName,
+			"KUE_ALERT_QUEUE_URL="+a.QueueUrl,
+			"KUE_ALERT_MESSAGE="+a.Message,
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to run alert command: %w: %s", err, output)
+		}
+	}
+	return nil
+}
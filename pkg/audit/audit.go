@@ -0,0 +1,199 @@
+// Package audit records mutating actions in an append-only JSON Lines file.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Outcomes of an action.
+const (
+	Success = "success"
+	Failure = "failure"
+)
+
+// Actor identifies who made a change, and with which AWS identity.
+type Actor struct {
+	User    string `json:"user"`
+	Account string `json:"account,omitempty"`
+	Profile string `json:"profile,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Context string `json:"context,omitempty"` // configured context of the queue, if any
+}
+
+// Entry is a line of the audit log.
+type Entry struct {
+	Time time.Time `json:"time"`
+	Actor
+	Action     string            `json:"action"` // SQS operation, e.g. PurgeQueue
+	Target     string            `json:"target"` // ARN of the queue
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Outcome    string            `json:"outcome"` // success or failure
+	Error      string            `json:"error,omitempty"`
+}
+
+// Log appends entries to the audit file. A nil log records nothing.
+type Log struct {
+	mu   sync.Mutex
+	file string
+}
+
+// Open returns the log of a file, or nil when no file is configured. The file
+// is created by the first entry.
+func Open(file string) *Log {
+	if file == "" {
+		return nil
+	}
+	return &Log{file: file}
+}
+
+// File returns the path of the audit file.
+func (l *Log) File() string {
+	if l == nil {
+		return ""
+	}
+	return l.file
+}
+
+// Append writes an entry to the end of the file.
+func (l *Log) Append(e Entry) error {
+	if l == nil {
+		return nil
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.file), 0o755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	// A single write keeps entries of concurrent processes on separate lines
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return f.Close()
+}
+
+// Entries reads all entries of the file in the order they were written. A
+// missing file has no entries.
+func (l *Log) Entries() ([]Entry, error) {
+	if l == nil {
+		return nil, nil
+	}
+	f, err := os.Open(l.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return entries, fmt.Errorf("failed to parse audit log line %d: %w", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// Recorder records the actions of an actor.
+type Recorder struct {
+	Log   *Log
+	Actor Actor
+}
+
+// Record appends the outcome of an action on a target. Failures to write the
+// audit log are logged, they do not fail the action that already happened.
+func (r Recorder) Record(action, target string, params map[string]string, err error) {
+	e := Entry{
+		Time:       time.Now().UTC(),
+		Actor:      r.Actor,
+		Action:     action,
+		Target:     target,
+		Parameters: params,
+		Outcome:    Success,
+	}
+	if err != nil {
+		e.Outcome = Failure
+		e.Error = err.Error()
+	}
+	// The account of the queue is known even before the account of the
+	// credentials has been resolved
+	if account := AccountOf(target); account != "" {
+		e.Account = account
+	}
+	if err := r.Log.Append(e); err != nil {
+		slog.Error("failed to record audit entry", "action", action, "target", target, "err", err)
+	}
+}
+
+// QueueArn returns the ARN of a queue in the region of the actor.
+func (r Recorder) QueueArn(queueUrl string) string {
+	return QueueArn(queueUrl, r.Actor.Region)
+}
+
+// CurrentUser returns the name of the OS user running kue.
+func CurrentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// QueueArn returns the ARN of a queue from its URL, whose path holds the
+// account ID and the queue name.
+func QueueArn(queueUrl, region string) string {
+	parts := strings.Split(strings.TrimSuffix(queueUrl, "/"), "/")
+	if len(parts) < 2 {
+		return queueUrl
+	}
+	account, name := parts[len(parts)-2], parts[len(parts)-1]
+
+	partition := "aws"
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		partition = "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		partition = "aws-us-gov"
+	}
+	return fmt.Sprintf("arn:%s:sqs:%s:%s:%s", partition, region, account, name)
+}
+
+// AccountOf returns the account ID of a queue ARN.
+func AccountOf(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 6 {
+		return ""
+	}
+	return parts[4]
+}
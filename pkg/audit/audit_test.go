@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAppendsEntries(t *testing.T) {
+	log := Open(filepath.Join(t.TempDir(), "state", "audit.jsonl"))
+	rec := Recorder{Log: log, Actor: Actor{User: "alice", Profile: "prod", Region: "eu-west-1"}}
+
+	target := rec.QueueArn("https://sqs.eu-west-1.amazonaws.com/123456789012/orders")
+	rec.Record("PurgeQueue", target, nil, nil)
+	rec.Record("DeleteMessage", target, map[string]string{"message_id": "m-1"}, errors.New("access denied"))
+
+	entries, err := log.Entries()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	first := entries[0]
+	if first.Action != "PurgeQueue" || first.Outcome != Success || first.Error != "" {
+		t.Errorf("Unexpected first entry: %+v", first)
+	}
+	if first.Target != "arn:aws:sqs:eu-west-1:123456789012:orders" {
+		t.Errorf("Unexpected target %q", first.Target)
+	}
+	if first.User != "alice" || first.Profile != "prod" || first.Account != "123456789012" {
+		t.Errorf("Expected the actor with the account of the queue, got %+v", first.Actor)
+	}
+
+	second := entries[1]
+	if second.Outcome != Failure || second.Error != "access denied" {
+		t.Errorf("Expected a failure with its error, got %+v", second)
+	}
+	if second.Parameters["message_id"] != "m-1" {
+		t.Errorf("Expected the parameters to be kept, got %v", second.Parameters)
+	}
+
+	info, err := os.Stat(log.File())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected a private audit file, got %v", info.Mode().Perm())
+	}
+}
+
+func TestEntriesOfMissingFile(t *testing.T) {
+	entries, err := Open(filepath.Join(t.TempDir(), "audit.jsonl")).Entries()
+	if err != nil || entries != nil {
+		t.Errorf("Expected no entries and no error, got %v, %v", entries, err)
+	}
+}
+
+func TestEntriesReportsCorruptLine(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "audit.jsonl")
+	content := `{"time":"2024-01-15T12:00:00Z","user":"alice","action":"PurgeQueue","target":"arn","outcome":"success"}` + "\n\nnot json\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Open(file).Entries()
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected an error naming line 3, got %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected the entries before the corrupt line, got %d", len(entries))
+	}
+}
+
+func TestNilLogRecordsNothing(t *testing.T) {
+	var log *Log
+	if err := log.Append(Entry{Action: "PurgeQueue"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if entries, err := log.Entries(); entries != nil || err != nil {
+		t.Errorf("Expected no entries, got %v, %v", entries, err)
+	}
+}
+
+func TestQueueArn(t *testing.T) {
+	tests := []struct {
+		url    string
+		region string
+		want   string
+	}{
+		{"https://sqs.us-east-1.amazonaws.com/123456789012/orders", "us-east-1", "arn:aws:sqs:us-east-1:123456789012:orders"},
+		{"https://sqs.cn-north-1.amazonaws.com.cn/123456789012/orders.fifo", "cn-north-1", "arn:aws-cn:sqs:cn-north-1:123456789012:orders.fifo"},
+		{"https://sqs.us-gov-west-1.amazonaws.com/123456789012/orders/", "us-gov-west-1", "arn:aws-us-gov:sqs:us-gov-west-1:123456789012:orders"},
+		{"orders", "us-east-1", "orders"},
+	}
+	for _, tt := range tests {
+		if got := QueueArn(tt.url, tt.region); got != tt.want {
+			t.Errorf("QueueArn(%q, %q) = %q, want %q", tt.url, tt.region, got, tt.want)
+		}
+	}
+	if got := AccountOf("orders"); got != "" {
+		t.Errorf("Expected no account for a name, got %q", got)
+	}
+}
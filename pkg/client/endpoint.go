@@ -0,0 +1,41 @@
+package client
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// localHosts are host names of emulators commonly run next to kue, including
+// the host names LocalStack resolves to the loopback address.
+var localHosts = []string{"localhost", "localstack", "elasticmq", "host.docker.internal", "localhost.localstack.cloud"}
+
+// IsLocalEndpoint reports whether an endpoint points at a local emulator such
+// as LocalStack or ElasticMQ rather than AWS. Private addresses are not local,
+// they may as well be VPC endpoints of a production account.
+func IsLocalEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+
+	for _, local := range localHosts {
+		if host == local || strings.HasSuffix(host, "."+local) {
+			return true
+		}
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsLoopback()
+	}
+	return false
+}
+
+// EndpointHost returns the host of an endpoint for display.
+func EndpointHost(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint
+	}
+	return u.Host
+}
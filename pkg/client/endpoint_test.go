@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestIsLocalEndpoint(t *testing.T) {
+	tests := map[string]bool{
+		"http://localhost:4566":                              true,
+		"http://127.0.0.1:9324":                              true,
+		"http://localstack:4566":                             true,
+		"http://[::1]:4566":                                  true,
+		"http://localhost.localstack.cloud:4566":             true,
+		"http://sqs.eu-west-1.localhost.localstack.cloud":    true,
+		"http://192.168.1.20:4566":                           false,
+		"https://10.0.1.5":                                   false,
+		"https://sqs.eu-west-1.amazonaws.com":                false,
+		"https://vpce-0123.sqs.eu-west-1.vpce.amazonaws.com": false,
+		"": false,
+	}
+	for endpoint, want := range tests {
+		if got := IsLocalEndpoint(endpoint); got != want {
+			t.Errorf("IsLocalEndpoint(%q) = %v, want %v", endpoint, got, want)
+		}
+	}
+}
+
+func TestEndpointHost(t *testing.T) {
+	if got := EndpointHost("https://vpce-0123.sqs.eu-west-1.vpce.amazonaws.com/"); got != "vpce-0123.sqs.eu-west-1.vpce.amazonaws.com" {
+		t.Errorf("Unexpected host %q", got)
+	}
+}
+
+func TestEndpointOnlyAppliesToSQS(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_ENDPOINT_URL", "")
+	t.Setenv("AWS_ENDPOINT_URL_SQS", "")
+
+	session, err := NewSession(context.Background(), Options{Region: "us-east-1", Endpoint: "http://localhost:4566"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if session.Info.Endpoint != "http://localhost:4566" {
+		t.Errorf("Expected the endpoint to be shown, got %q", session.Info.Endpoint)
+	}
+	if got := aws.ToString(session.SQS().Options().BaseEndpoint); got != "http://localhost:4566" {
+		t.Errorf("Expected SQS to use the endpoint, got %q", got)
+	}
+	if got := session.CloudWatch().Options().BaseEndpoint; got != nil {
+		t.Errorf("Expected CloudWatch to keep the AWS endpoint, got %q", *got)
+	}
+	if got := session.cfg.BaseEndpoint; got != nil {
+		t.Errorf("Expected STS to keep the AWS endpoint, got %q", *got)
+	}
+}
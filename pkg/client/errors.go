@@ -0,0 +1,67 @@
+package client
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/smithy-go"
+)
+
+// CredentialsProblem classifies errors caused by the credentials rather than
+// by the request.
+type CredentialsProblem int
+
+const (
+	CredentialsValid CredentialsProblem = iota
+	CredentialsExpired
+	SSOLoginRequired
+)
+
+// expiredTokenCodes are API error codes returned for expired credentials.
+var expiredTokenCodes = map[string]bool{
+	"ExpiredToken":          true,
+	"ExpiredTokenException": true,
+	"TokenRefreshRequired":  true,
+}
+
+// ClassifyCredentialsError reports whether err was caused by expired
+// credentials or an expired SSO session.
+func ClassifyCredentialsError(err error) CredentialsProblem {
+	if err == nil {
+		return CredentialsValid
+	}
+
+	var tokenErr *ssocreds.InvalidTokenError
+	if errors.As(err, &tokenErr) {
+		return SSOLoginRequired
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.ErrorCode() == "UnauthorizedException" && strings.Contains(strings.ToLower(err.Error()), "sso") {
+			return SSOLoginRequired
+		}
+		if expiredTokenCodes[apiErr.ErrorCode()] {
+			return CredentialsExpired
+		}
+	}
+
+	// Token providers wrap their errors as plain strings
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "sso token") && (strings.Contains(msg, "expired") || strings.Contains(msg, "refresh")):
+		return SSOLoginRequired
+	case strings.Contains(msg, "security token included in the request is expired"):
+		return CredentialsExpired
+	}
+	return CredentialsValid
+}
+
+// SSOLoginCommand returns the command that renews the SSO session of a
+// profile.
+func SSOLoginCommand(profile string) string {
+	if profile == "" || profile == "default" {
+		return "aws sso login"
+	}
+	return "aws sso login --profile " + profile
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/smithy-go"
+)
+
+func TestClassifyCredentialsError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want CredentialsProblem
+	}{
+		{nil, CredentialsValid},
+		{errors.New("queue does not exist"), CredentialsValid},
+		{fmt.Errorf("failed to list queues: %w", &smithy.GenericAPIError{Code: "ExpiredToken", Message: "expired"}), CredentialsExpired},
+		{&smithy.GenericAPIError{Code: "AccessDenied"}, CredentialsValid},
+		{fmt.Errorf("failed to refresh cached credentials, %w", &ssocreds.InvalidTokenError{}), SSOLoginRequired},
+		{errors.New("refresh cached SSO token failed, unable to refresh SSO token"), SSOLoginRequired},
+		{errors.New("The security token included in the request is expired"), CredentialsExpired},
+	}
+	for _, tt := range tests {
+		if got := ClassifyCredentialsError(tt.err); got != tt.want {
+			t.Errorf("ClassifyCredentialsError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestSSOLoginCommand(t *testing.T) {
+	if got := SSOLoginCommand("prod"); got != "aws sso login --profile prod" {
+		t.Errorf("Unexpected command %q", got)
+	}
+	if got := SSOLoginCommand("default"); got != "aws sso login" {
+		t.Errorf("Unexpected command %q", got)
+	}
+}
@@ -2,48 +2,201 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
+
+	kueconfig "github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/logging"
 )
 
 // AWSInfo holds AWS configuration information for display.
 type AWSInfo struct {
-	Profile string
-	Region  string
+	Profile  string
+	Region   string
+	Account  string // resolved asynchronously, empty until known
+	Endpoint string // custom endpoint, empty for the AWS endpoints
+}
+
+// Options select the AWS profile, region and endpoint clients are created
+// for. Empty values fall back to the environment and the shared config files.
+type Options struct {
+	Profile  string
+	Region   string
+	Endpoint string                 // e.g. http://localhost:4566 for LocalStack
+	Roles    []kueconfig.AssumeRole // roles assumed in order on top of the profile credentials
+	MFA      *MFAPrompt             // asks for MFA codes of assumed roles, including roles of the profile
+	Timeouts Timeouts
+	Retry    RetryOptions
+	Retries  *RetryMonitor // shows the calls being retried, may be nil
+	ReadOnly bool          // refuses calls that change queues or messages
 }
 
-// fetchContext loads the default AWS configuration using the AWS SDK for Go.
+// fetchContext loads the AWS configuration for the options using the AWS SDK for Go.
 // It returns the loaded aws.Config and an error if the configuration could not be loaded.
-func fetchContext(ctx context.Context) (aws.Config, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+func fetchContext(ctx context.Context, opts Options) (aws.Config, error) {
+	var loadOptions []func(*config.LoadOptions) error
+	if opts.Profile != "" {
+		loadOptions = append(loadOptions, config.WithSharedConfigProfile(opts.Profile))
+	}
+	if opts.Region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(opts.Region))
+	}
+	// The clients of a session share the retryer and its rate limits
+	retryer := newRetryer(opts)
+	loadOptions = append(loadOptions, config.WithRetryer(func() aws.Retryer { return retryer }))
+	loadOptions = append(loadOptions, config.WithAPIOptions([]func(*middleware.Stack) error{
+		opts.Timeouts.addMiddleware,
+		retryer.addMiddleware,
+	}))
+	if opts.ReadOnly {
+		loadOptions = append(loadOptions, config.WithAPIOptions([]func(*middleware.Stack) error{addReadOnlyMiddleware}))
+	}
+	// Roles of the profile that require MFA prompt for the code as well
+	loadOptions = append(loadOptions, config.WithAssumeRoleCredentialOptions(func(o *stscreds.AssumeRoleOptions) {
+		if o.SerialNumber != nil {
+			o.TokenProvider = opts.MFA.tokenProvider(aws.ToString(o.SerialNumber))
+		}
+	}))
+
+	// Requests and responses are only dumped when they are logged
+	if slog.Default().Enabled(ctx, logging.LevelTrace) {
+		loadOptions = append(loadOptions,
+			config.WithLogger(logging.SDKLogger(slog.Default())),
+			config.WithClientLogMode(aws.LogRequestWithBody|aws.LogResponseWithBody|aws.LogRetries),
+		)
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
 	if err != nil {
 		return aws.Config{}, err
 	}
 
+	// Each role is assumed with the credentials of the previous one
+	for _, role := range opts.Roles {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), role.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+			if role.ExternalID != "" {
+				o.ExternalID = aws.String(role.ExternalID)
+			}
+			if role.MFASerial != "" {
+				o.SerialNumber = aws.String(role.MFASerial)
+				o.TokenProvider = opts.MFA.tokenProvider(role.MFASerial)
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
 	return cfg, nil
 }
 
-// CreateSqsClient creates and returns a new Amazon SQS client using the provided context.
-// It also returns AWS configuration info (profile, region) for display purposes.
-func CreateSqsClient(ctx context.Context) (*sqs.Client, AWSInfo, error) {
-	cfg, err := fetchContext(ctx)
-	if err != nil {
-		return nil, AWSInfo{}, err
+// DefaultProfile returns the profile used when none is picked.
+func DefaultProfile() string {
+	// Get profile from environment (AWS SDK doesn't expose it directly)
+	if profile := os.Getenv("AWS_PROFILE"); profile != "" {
+		return profile
 	}
+	return "default"
+}
 
-	// Get profile from environment (AWS SDK doesn't expose it directly)
-	profile := os.Getenv("AWS_PROFILE")
-	if profile == "" {
-		profile = "default"
+// profileName returns the profile the options resolve to.
+func profileName(opts Options) string {
+	if opts.Profile != "" {
+		return opts.Profile
+	}
+	return DefaultProfile()
+}
+
+// endpoint returns the custom SQS endpoint of the options, or the endpoint
+// set through AWS_ENDPOINT_URL. Like the SDK, AWS_ENDPOINT_URL_SQS takes
+// precedence over all other endpoints.
+func endpoint(opts Options) string {
+	if endpoint := os.Getenv("AWS_ENDPOINT_URL_SQS"); endpoint != "" {
+		return endpoint
+	}
+	if opts.Endpoint != "" {
+		return opts.Endpoint
+	}
+	return os.Getenv("AWS_ENDPOINT_URL")
+}
+
+// Session holds the AWS configuration of a profile. Clients created from the
+// same session share its credentials, so MFA codes are asked for only once.
+type Session struct {
+	Options Options
+	Info    AWSInfo
+	cfg     aws.Config
+}
+
+// NewSession loads the AWS configuration for the options. Credentials are
+// resolved on the first request, or by Resolve.
+func NewSession(ctx context.Context, opts Options) (*Session, error) {
+	cfg, err := fetchContext(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	info := AWSInfo{
-		Profile: profile,
-		Region:  cfg.Region,
+		Profile:  profileName(opts),
+		Region:   cfg.Region,
+		Endpoint: endpoint(opts),
+	}
+
+	return &Session{Options: opts, Info: info, cfg: cfg}, nil
+}
+
+// NewSessions creates a session for each of the options, keyed by name.
+func NewSessions(ctx context.Context, opts map[string]Options) (map[string]*Session, error) {
+	sessions := make(map[string]*Session)
+	for name, o := range opts {
+		session, err := NewSession(ctx, o)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load context %s: %w", name, err)
+		}
+		sessions[name] = session
+	}
+	return sessions, nil
+}
+
+// Resolve resolves the credentials of the session right away, so expired
+// credentials and MFA prompts surface before the next request.
+func (s *Session) Resolve(ctx context.Context) error {
+	if _, err := s.cfg.Credentials.Retrieve(ctx); err != nil {
+		return fmt.Errorf("failed to resolve credentials: %w", err)
+	}
+	return nil
+}
+
+// SQS creates and returns a new Amazon SQS client for the session. Only SQS
+// uses the custom endpoint, STS and CloudWatch keep the AWS endpoints.
+func (s *Session) SQS() *sqs.Client {
+	return sqs.NewFromConfig(s.cfg, func(o *sqs.Options) {
+		if endpoint := endpoint(s.Options); endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+}
+
+// CloudWatch creates and returns a new Amazon CloudWatch client used to read
+// queue metrics that SQS does not expose as attributes.
+func (s *Session) CloudWatch() *cloudwatch.Client {
+	return cloudwatch.NewFromConfig(s.cfg)
+}
+
+// AccountID resolves the account ID of the credentials using STS
+// GetCallerIdentity.
+func (s *Session) AccountID(ctx context.Context) (string, error) {
+	output, err := sts.NewFromConfig(s.cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get caller identity: %w", err)
 	}
 
-	return sqs.NewFromConfig(cfg), info, nil
+	return aws.ToString(output.Account), nil
 }
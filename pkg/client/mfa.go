@@ -0,0 +1,54 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMFACancelled is returned when an MFA prompt is cancelled.
+var ErrMFACancelled = errors.New("MFA prompt cancelled")
+
+// MFAPrompt hands MFA code requests from credential resolution, which runs in
+// the background, to the user interface.
+type MFAPrompt struct {
+	requests chan MFARequest
+}
+
+// MFARequest is a pending request for the MFA code of a device.
+type MFARequest struct {
+	Serial string
+	reply  chan string
+}
+
+// NewMFAPrompt creates a prompt without pending requests.
+func NewMFAPrompt() *MFAPrompt {
+	return &MFAPrompt{requests: make(chan MFARequest)}
+}
+
+// Requests returns the channel MFA code requests are sent on.
+func (p *MFAPrompt) Requests() <-chan MFARequest {
+	return p.requests
+}
+
+// Respond answers the request, an empty code cancels it.
+func (r MFARequest) Respond(code string) {
+	r.reply <- code
+}
+
+// tokenProvider returns an STS token provider asking the prompt for the code
+// of the device.
+func (p *MFAPrompt) tokenProvider(serial string) func() (string, error) {
+	return func() (string, error) {
+		if p == nil {
+			return "", fmt.Errorf("an MFA code for %s is required", serial)
+		}
+
+		reply := make(chan string, 1)
+		p.requests <- MFARequest{Serial: serial, reply: reply}
+		code := <-reply
+		if code == "" {
+			return "", ErrMFACancelled
+		}
+		return code, nil
+	}
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMFAPrompt(t *testing.T) {
+	prompt := NewMFAPrompt()
+	provider := prompt.tokenProvider("arn:aws:iam::123456789012:mfa/alice")
+
+	go func() {
+		req := <-prompt.Requests()
+		if req.Serial != "arn:aws:iam::123456789012:mfa/alice" {
+			t.Errorf("Unexpected serial %q", req.Serial)
+		}
+		req.Respond("123456")
+	}()
+	if code, err := provider(); err != nil || code != "123456" {
+		t.Errorf("Expected the entered code, got %q (%v)", code, err)
+	}
+
+	go func() { (<-prompt.Requests()).Respond("") }()
+	if _, err := provider(); !errors.Is(err, ErrMFACancelled) {
+		t.Errorf("Expected the prompt to be cancelled, got %v", err)
+	}
+}
+
+func TestMFAPromptMissing(t *testing.T) {
+	var prompt *MFAPrompt
+	if _, err := prompt.tokenProvider("arn:aws:iam::123456789012:mfa/alice")(); err == nil {
+		t.Error("Expected an error without a prompt")
+	}
+}
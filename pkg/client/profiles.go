@@ -0,0 +1,124 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// Profile is a named profile from the shared AWS config files.
+type Profile struct {
+	Name   string
+	Region string // empty when the profile has no region
+}
+
+// Regions are the commercial AWS regions offered when switching regions.
+var Regions = []string{
+	"us-east-1", "us-east-2", "us-west-1", "us-west-2",
+	"af-south-1",
+	"ap-east-1", "ap-south-1", "ap-south-2", "ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
+	"ap-southeast-1", "ap-southeast-2", "ap-southeast-3", "ap-southeast-4",
+	"ca-central-1", "ca-west-1",
+	"eu-central-1", "eu-central-2", "eu-west-1", "eu-west-2", "eu-west-3",
+	"eu-north-1", "eu-south-1", "eu-south-2",
+	"il-central-1",
+	"me-south-1", "me-central-1",
+	"sa-east-1",
+}
+
+// ListProfiles returns the profiles of the shared config and credentials
+// files, sorted by name. Missing files are ignored.
+func ListProfiles() ([]Profile, error) {
+	configFile := os.Getenv("AWS_CONFIG_FILE")
+	if configFile == "" {
+		configFile = config.DefaultSharedConfigFilename()
+	}
+	credentialsFile := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsFile == "" {
+		credentialsFile = config.DefaultSharedCredentialsFilename()
+	}
+
+	profiles := make(map[string]Profile)
+	for _, file := range []struct {
+		path        string
+		credentials bool
+	}{
+		{configFile, false},
+		{credentialsFile, true},
+	} {
+		f, err := os.Open(file.path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", file.path, err)
+		}
+		parsed, err := parseProfiles(f, file.credentials)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.path, err)
+		}
+		for _, p := range parsed {
+			if existing, ok := profiles[p.Name]; ok && existing.Region != "" {
+				continue
+			}
+			profiles[p.Name] = p
+		}
+	}
+
+	var result []Profile
+	for _, p := range profiles {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// parseProfiles parses the profile sections of a shared config file. In the
+// config file profiles other than default are named "[profile name]", in the
+// credentials file sections are the profile names.
+func parseProfiles(r io.Reader, credentials bool) ([]Profile, error) {
+	var profiles []Profile
+	current := -1
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = -1
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if !credentials {
+				switch {
+				case name == "default":
+				case strings.HasPrefix(name, "profile "):
+					name = strings.TrimSpace(strings.TrimPrefix(name, "profile "))
+				default:
+					// sso-session and services sections are not profiles
+					continue
+				}
+			}
+			if name != "" {
+				profiles = append(profiles, Profile{Name: name})
+				current = len(profiles) - 1
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if ok && current >= 0 && strings.TrimSpace(key) == "region" {
+			profiles[current].Region = strings.TrimSpace(value)
+		}
+	}
+	return profiles, scanner.Err()
+}
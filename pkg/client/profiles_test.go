@@ -0,0 +1,64 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProfiles(t *testing.T) {
+	input := `
+# comment
+[default]
+region = eu-west-1
+
+[profile staging]
+region=eu-central-1
+output = json
+
+[sso-session company]
+sso_region = us-east-1
+
+[profile prod]
+sso_session = company
+`
+	profiles, err := parseProfiles(strings.NewReader(input), false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []Profile{
+		{Name: "default", Region: "eu-west-1"},
+		{Name: "staging", Region: "eu-central-1"},
+		{Name: "prod"},
+	}
+	if !reflect.DeepEqual(profiles, want) {
+		t.Errorf("Expected %+v, got %+v", want, profiles)
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config")
+	credentialsFile := filepath.Join(dir, "credentials")
+	if err := os.WriteFile(configFile, []byte("[profile dev]\nregion = us-west-2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(credentialsFile, []byte("[dev]\naws_access_key_id = x\n[ci]\naws_access_key_id = y\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+
+	profiles, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []Profile{{Name: "ci"}, {Name: "dev", Region: "us-west-2"}}
+	if !reflect.DeepEqual(profiles, want) {
+		t.Errorf("Expected %+v, got %+v", want, profiles)
+	}
+}
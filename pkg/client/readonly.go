@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// ErrReadOnly is returned for mutating calls made by read-only sessions.
+var ErrReadOnly = errors.New("kue is read-only")
+
+// mutatingOperations are the SQS operations that change queues or messages.
+var mutatingOperations = map[string]bool{
+	"AddPermission":                true,
+	"CancelMessageMoveTask":        true,
+	"ChangeMessageVisibility":      true,
+	"ChangeMessageVisibilityBatch": true,
+	"CreateQueue":                  true,
+	"DeleteMessage":                true,
+	"DeleteMessageBatch":           true,
+	"DeleteQueue":                  true,
+	"PurgeQueue":                   true,
+	"RemovePermission":             true,
+	"SendMessage":                  true,
+	"SendMessageBatch":             true,
+	"SetQueueAttributes":           true,
+	"StartMessageMoveTask":         true,
+	"TagQueue":                     true,
+	"UntagQueue":                   true,
+}
+
+// IsMutating reports whether an SQS operation changes queues or messages.
+func IsMutating(operation string) bool {
+	return mutatingOperations[operation]
+}
+
+// addReadOnlyMiddleware refuses mutating SQS calls, so nothing reaching the
+// client can change a queue. It runs at the end of the initialize step, after
+// the operation name is known and before the call is signed and sent.
+func addReadOnlyMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("KueReadOnly", func(
+		ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		if operation := awsmiddleware.GetOperationName(ctx); awsmiddleware.GetServiceID(ctx) == "SQS" && IsMutating(operation) {
+			return middleware.InitializeOutput{}, middleware.Metadata{}, fmt.Errorf("%s refused: %w", operation, ErrReadOnly)
+		}
+		return next.HandleInitialize(ctx, in)
+	}), middleware.After)
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+func TestReadOnlyRefusesMutatingCalls(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Write([]byte(`{"QueueUrls":[]}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	session, err := NewSession(context.Background(), Options{Region: "us-east-1", Endpoint: server.URL, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sqsClient := session.SQS()
+
+	_, err = sqsClient.PurgeQueue(context.Background(), &sqs.PurgeQueueInput{QueueUrl: aws.String(server.URL + "/123456789012/orders")})
+	if !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected the purge to be refused, got %v", err)
+	}
+	if requests.Load() != 0 {
+		t.Errorf("Expected no request for a refused call, got %d", requests.Load())
+	}
+
+	if _, err := sqsClient.ListQueues(context.Background(), &sqs.ListQueuesInput{}); err != nil {
+		t.Errorf("Expected reads to work, got %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected the read to be sent, got %d requests", requests.Load())
+	}
+}
+
+func TestIsMutating(t *testing.T) {
+	for _, op := range []string{"CreateQueue", "DeleteQueue", "DeleteMessage", "PurgeQueue", "SendMessage", "StartMessageMoveTask"} {
+		if !IsMutating(op) {
+			t.Errorf("Expected %s to be mutating", op)
+		}
+	}
+	for _, op := range []string{"ListQueues", "GetQueueAttributes", "ReceiveMessage", "ListMessageMoveTasks"} {
+		if IsMutating(op) {
+			t.Errorf("Expected %s to be read-only", op)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// RetryOptions configure how throttled and failed calls are retried.
+type RetryOptions struct {
+	MaxAttempts int           // attempts of a call including the first one, zero for the SDK default
+	MaxBackoff  time.Duration // longest wait between attempts, zero for the SDK default
+	RateLimit   float64       // requests per second of a session, zero for no limit
+}
+
+// RetryStatus counts the calls that are being retried.
+type RetryStatus struct {
+	Retrying  int // calls waiting for or making another attempt
+	Throttled int // calls of those that were throttled by AWS
+}
+
+// RetryMonitor hands the retries of calls, which run in the background, to
+// the user interface.
+type RetryMonitor struct {
+	mu      sync.Mutex
+	status  RetryStatus
+	changed chan struct{}
+}
+
+// NewRetryMonitor creates a monitor without calls being retried.
+func NewRetryMonitor() *RetryMonitor {
+	return &RetryMonitor{changed: make(chan struct{}, 1)}
+}
+
+// Changes returns a channel that receives when the status changes. Changes
+// made while nobody receives are sent once.
+func (m *RetryMonitor) Changes() <-chan struct{} {
+	return m.changed
+}
+
+// Status returns the calls that are being retried.
+func (m *RetryMonitor) Status() RetryStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// update changes the status and notifies the receiver of Changes.
+func (m *RetryMonitor) update(fn func(*RetryStatus)) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	fn(&m.status)
+	m.mu.Unlock()
+
+	select {
+	case m.changed <- struct{}{}:
+	default:
+	}
+}
+
+// retryCall tracks whether a call has been counted by the monitor.
+type retryCall struct {
+	retrying  bool
+	throttled bool
+}
+
+type retryCallKey struct{}
+
+// throttles classifies the errors returned when AWS throttles a call.
+var throttles = retry.IsErrorThrottles(retry.DefaultThrottles)
+
+// IsTransient reports whether err may go away when the call is made again
+// later, such as throttling, server errors that were retried until the
+// attempts ran out and calls that timed out.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var attemptsErr *retry.MaxAttemptsError
+	return errors.As(err, &attemptsErr) || IsThrottled(err) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsThrottled reports whether err was returned because AWS throttled the call.
+func IsThrottled(err error) bool {
+	return err != nil && throttles.IsErrorThrottle(err) == aws.TrueTernary
+}
+
+// retryer retries calls with jittered exponential backoff, slowing down when
+// AWS throttles them, and reports the calls being retried to the monitor.
+type retryer struct {
+	aws.RetryerV2
+	limiter *rateLimiter
+	monitor *RetryMonitor
+}
+
+// newRetryer creates the retryer shared by the clients of a session, so
+// throttling of one client slows down the others as well.
+func newRetryer(opts Options) *retryer {
+	adaptive := retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+		o.StandardOptions = append(o.StandardOptions, func(so *retry.StandardOptions) {
+			if opts.Retry.MaxAttempts > 0 {
+				so.MaxAttempts = opts.Retry.MaxAttempts
+			}
+			if opts.Retry.MaxBackoff > 0 {
+				so.MaxBackoff = opts.Retry.MaxBackoff
+			}
+		})
+	})
+	return &retryer{
+		RetryerV2: adaptive,
+		limiter:   newRateLimiter(opts.Retry.RateLimit),
+		monitor:   opts.Retries,
+	}
+}
+
+// GetAttemptToken waits for the rate limit before every attempt.
+func (r *retryer) GetAttemptToken(ctx context.Context) (func(error) error, error) {
+	if err := r.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.RetryerV2.GetAttemptToken(ctx)
+}
+
+// GetRetryToken counts the call as retried before it backs off.
+func (r *retryer) GetRetryToken(ctx context.Context, opErr error) (func(error) error, error) {
+	if call, ok := ctx.Value(retryCallKey{}).(*retryCall); ok {
+		throttled := IsThrottled(opErr)
+		r.monitor.update(func(s *RetryStatus) {
+			if !call.retrying {
+				call.retrying = true
+				s.Retrying++
+			}
+			if throttled && !call.throttled {
+				call.throttled = true
+				s.Throttled++
+			}
+		})
+	}
+	return r.RetryerV2.GetRetryToken(ctx, opErr)
+}
+
+// addMiddleware tracks every call around its attempts, so calls that were
+// retried are no longer counted once they completed.
+func (r *retryer) addMiddleware(stack *middleware.Stack) error {
+	if _, ok := stack.Finalize.Get("Retry"); !ok {
+		return nil
+	}
+	return stack.Finalize.Insert(middleware.FinalizeMiddlewareFunc("KueRetryMonitor", func(
+		ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+	) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		call := &retryCall{}
+		out, metadata, err := next.HandleFinalize(context.WithValue(ctx, retryCallKey{}, call), in)
+		if call.retrying {
+			r.monitor.update(func(s *RetryStatus) {
+				s.Retrying--
+				if call.throttled {
+					s.Throttled--
+				}
+			})
+		}
+		return out, metadata, err
+	}), "Retry", middleware.Before)
+}
+
+// rateLimiter spaces out requests to stay below a rate, allowing bursts of up
+// to a second of requests.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // time between requests at the rate
+	burst    time.Duration // how far requests may run ahead of the rate
+	next     time.Time     // when the next request is due at the rate
+}
+
+// newRateLimiter returns a limiter for the requests per second, or nil for no
+// limit.
+func newRateLimiter(rate float64) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	interval := time.Duration(float64(time.Second) / rate)
+	return &rateLimiter{interval: interval, burst: max(0, time.Second-interval)}
+}
+
+// wait blocks until a request may be made or the context is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now) - l.burst
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/smithy-go"
+)
+
+func TestRetriesThrottledCalls(t *testing.T) {
+	monitor := NewRetryMonitor()
+	var requests atomic.Int32
+	var during RetryStatus
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("X-Amzn-ErrorType", "ThrottlingException")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"__type":"ThrottlingException","message":"Rate exceeded"}`)
+			return
+		}
+		during = monitor.Status()
+		fmt.Fprint(w, `{"QueueUrls":["http://localhost/000000000000/orders"]}`)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	session, err := NewSession(context.Background(), Options{
+		Region:   "us-east-1",
+		Endpoint: server.URL,
+		Retry:    RetryOptions{MaxAttempts: 3, MaxBackoff: 10 * time.Millisecond},
+		Retries:  monitor,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	output, err := session.SQS().ListQueues(context.Background(), &sqs.ListQueuesInput{})
+	if err != nil {
+		t.Fatalf("Expected the call to succeed after retrying, got %v", err)
+	}
+	if len(output.QueueUrls) != 1 || requests.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", requests.Load())
+	}
+	if during != (RetryStatus{Retrying: 1, Throttled: 1}) {
+		t.Errorf("Expected a throttled call to be retried during the last attempt, got %+v", during)
+	}
+	if status := monitor.Status(); status != (RetryStatus{}) {
+		t.Errorf("Expected no calls to be retried after the call completed, got %+v", status)
+	}
+	select {
+	case <-monitor.Changes():
+	default:
+		t.Error("Expected the changes of the status to be announced")
+	}
+}
+
+func TestRateLimiterSpacesOutRequests(t *testing.T) {
+	limiter := newRateLimiter(20)
+
+	start := time.Now()
+	for range 20 {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("Expected a burst of a second of requests to pass right away, took %s", elapsed)
+	}
+
+	if err := limiter.wait(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected requests beyond the burst to wait, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected waiting to stop when the context is cancelled, got %v", err)
+	}
+
+	if err := newRateLimiter(0).wait(ctx); err != nil {
+		t.Errorf("Expected no limit for a zero rate, got %v", err)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"throttled", &smithy.GenericAPIError{Code: "RequestThrottled"}, true},
+		{"attempts exhausted", &retry.MaxAttemptsError{Attempt: 5, Err: errors.New("internal error")}, true},
+		{"timed out", fmt.Errorf("failed to list queues: %w", context.DeadlineExceeded), true},
+		{"access denied", &smithy.GenericAPIError{Code: "AccessDenied"}, false},
+		{"no error", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
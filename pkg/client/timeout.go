@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// Timeouts limit how long an API call may take, including its retries.
+type Timeouts struct {
+	Default    time.Duration            // zero for no limit
+	Operations map[string]time.Duration // keyed by API operation name, e.g. ReceiveMessage
+}
+
+// For returns the timeout of an API operation.
+func (t Timeouts) For(operation string) time.Duration {
+	if d, ok := t.Operations[operation]; ok {
+		return d
+	}
+	return t.Default
+}
+
+// addMiddleware cancels calls that exceed the timeout of their operation. It
+// runs at the end of the initialize step, after the operation name is known
+// and before the retries.
+func (t Timeouts) addMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("KueTimeout", func(
+		ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		if d := t.For(awsmiddleware.GetOperationName(ctx)); d > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, d)
+			defer cancel()
+		}
+		return next.HandleInitialize(ctx, in)
+	}), middleware.After)
+}
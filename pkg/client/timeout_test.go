@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+func TestTimeoutsFor(t *testing.T) {
+	timeouts := Timeouts{
+		Default:    30 * time.Second,
+		Operations: map[string]time.Duration{"ReceiveMessage": time.Minute},
+	}
+
+	if got := timeouts.For("ReceiveMessage"); got != time.Minute {
+		t.Errorf("Expected the timeout of the operation, got %s", got)
+	}
+	if got := timeouts.For("ListQueues"); got != 30*time.Second {
+		t.Errorf("Expected the default timeout, got %s", got)
+	}
+}
+
+func TestTimeoutsCancelSlowCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	session, err := NewSession(context.Background(), Options{
+		Region:   "us-east-1",
+		Endpoint: server.URL,
+		Timeouts: Timeouts{
+			Default:    time.Minute,
+			Operations: map[string]time.Duration{"ListQueues": 50 * time.Millisecond},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	_, err = session.SQS().ListQueues(context.Background(), &sqs.ListQueuesInput{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the call to exceed its deadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the call to be cancelled after its timeout, took %s", elapsed)
+	}
+}
@@ -0,0 +1,371 @@
+// Package config loads the user configuration for kue.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kontrolplane/kue/pkg/logging"
+)
+
+// Config holds the user configuration read from the config file.
+type Config struct {
+	RefreshInterval time.Duration       `yaml:"refresh_interval"` // how often the overview and queue details refresh
+	ReadOnly        bool                `yaml:"read_only"`        // disables creating, deleting, purging, redriving and sending
+	Messages        MessageSettings     `yaml:"messages"`
+	Log             LogSettings         `yaml:"log"`
+	Layout          LayoutSettings      `yaml:"layout"`
+	Theme           ThemeSettings       `yaml:"theme"`
+	Keys            map[string][]string `yaml:"keys"` // keys of a binding keyed by binding name, e.g. purge: [ctrl+x]
+	Queues          []QueueSettings     `yaml:"queues"`
+	Overview        OverviewSettings    `yaml:"overview"`
+	Alerts          AlertSettings       `yaml:"alerts"`
+	Protection      ProtectionSettings  `yaml:"protection"`
+	Audit           AuditSettings       `yaml:"audit"`
+	Trash           TrashSettings       `yaml:"trash"`
+	AWS             AWSSettings         `yaml:"aws"`
+}
+
+// MessageSettings configures how messages are received in queue details.
+type MessageSettings struct {
+	FetchCount int32 `yaml:"fetch_count"` // messages received per refresh, at most 10
+}
+
+// LogSettings configures the log.
+type LogSettings struct {
+	File   string `yaml:"file"`   // empty to disable the log
+	Level  string `yaml:"level"`  // trace, debug, info, warn or error
+	Format string `yaml:"format"` // text or json
+}
+
+// AuditSettings configures the audit log of mutating actions.
+type AuditSettings struct {
+	File string `yaml:"file"` // JSON Lines file, empty to disable the audit log
+}
+
+// TrashSettings configures where deleted messages are kept and for how long.
+type TrashSettings struct {
+	File      string        `yaml:"file"`      // JSON Lines file, empty to delete messages for good
+	Retention time.Duration `yaml:"retention"` // how long deleted messages can be restored
+}
+
+// LayoutSettings configures the size of the content area in cells.
+type LayoutSettings struct {
+	Width  int `yaml:"width"`
+	Height int `yaml:"height"`
+}
+
+// ThemeSettings selects the color theme.
+type ThemeSettings struct {
+	Name   string            `yaml:"name"`   // dark (default), light, high-contrast or monochrome
+	Colors map[string]string `yaml:"colors"` // colors replaced in the theme keyed by name, e.g. accent: "#628049"
+}
+
+// Limits of the configurable values.
+const (
+	MinRefreshInterval = time.Second
+	MaxFetchCount      = 10 // the most messages SQS returns per receive
+	MinLayoutWidth     = 100
+	MinLayoutHeight    = 20
+	MaxConcurrency     = 50
+	MinTimeout         = 100 * time.Millisecond
+	MaxAttempts        = 10
+	MaxCooldown        = time.Minute
+	MinRetention       = time.Minute
+)
+
+// Default returns the configuration used for settings missing from the
+// config file.
+func Default() Config {
+	return Config{
+		RefreshInterval: 30 * time.Second,
+		Messages:        MessageSettings{FetchCount: 10},
+		Log:             LogSettings{File: statePath("debug.log"), Level: "info", Format: "text"},
+		Audit:           AuditSettings{File: statePath("audit.jsonl")},
+		Trash:           TrashSettings{File: statePath("trash.jsonl"), Retention: 7 * 24 * time.Hour},
+		Layout:          LayoutSettings{Width: 140, Height: 25},
+		Overview:        OverviewSettings{Concurrency: 10, CacheTTL: 2 * time.Minute},
+		AWS: AWSSettings{
+			Timeout: 30 * time.Second,
+			Retry:   RetrySettings{MaxAttempts: 5, MaxBackoff: 20 * time.Second, RateLimit: 20},
+		},
+	}
+}
+
+// AWSSettings configures how kue connects to AWS.
+type AWSSettings struct {
+	Profile          string                   `yaml:"profile"`   // profile used on start, defaults to AWS_PROFILE or default
+	Region           string                   `yaml:"region"`    // region used on start, defaults to the region of the profile
+	Endpoint         string                   `yaml:"endpoint"`  // endpoint for all profiles, e.g. http://localhost:4566
+	Endpoints        map[string]string        `yaml:"endpoints"` // endpoints keyed by profile name
+	Roles            map[string][]AssumeRole  `yaml:"roles"`     // roles assumed in order on top of a profile, keyed by profile name
+	Contexts         []ContextSettings        `yaml:"contexts"`  // contexts whose queues are shown together in the overview
+	Timeout          time.Duration            `yaml:"timeout"`   // how long an API call may take including retries
+	Timeouts         map[string]time.Duration `yaml:"timeouts"`  // timeouts keyed by API operation name, e.g. ReceiveMessage
+	Retry            RetrySettings            `yaml:"retry"`     // backoff and rate limit of calls to AWS
+	EndpointOverride string                   `yaml:"-"`         // set by the --endpoint flag, takes precedence over the file
+}
+
+// RetrySettings configures how throttled and failed calls are retried.
+type RetrySettings struct {
+	MaxAttempts int           `yaml:"max_attempts"` // attempts of a call including the first one
+	MaxBackoff  time.Duration `yaml:"max_backoff"`  // longest wait between attempts
+	RateLimit   float64       `yaml:"rate_limit"`   // requests per second per context, zero for no limit
+}
+
+// ContextSettings names a profile, region and endpoint to load queues from.
+type ContextSettings struct {
+	Name     string `yaml:"name"`
+	Profile  string `yaml:"profile"`   // defaults to the default profile
+	Region   string `yaml:"region"`    // defaults to the region of the profile
+	Endpoint string `yaml:"endpoint"`  // defaults to the endpoint of the profile
+	ReadOnly bool   `yaml:"read_only"` // read-only even when kue is not
+}
+
+// AssumeRole is a role assumed with the credentials of the previous step.
+type AssumeRole struct {
+	RoleArn    string `yaml:"role_arn"`
+	MFASerial  string `yaml:"mfa_serial"` // prompts for an MFA code when set
+	ExternalID string `yaml:"external_id"`
+}
+
+// EndpointFor returns the endpoint to use for a profile, or an empty string
+// for the default AWS endpoints.
+func (a AWSSettings) EndpointFor(profile string) string {
+	if a.EndpointOverride != "" {
+		return a.EndpointOverride
+	}
+	if endpoint, ok := a.Endpoints[profile]; ok {
+		return endpoint
+	}
+	return a.Endpoint
+}
+
+// OverviewSettings configures the queue overview table.
+type OverviewSettings struct {
+	Columns     []string      `yaml:"columns"`     // column ids in display order, e.g. name, available, tag:team
+	Concurrency int           `yaml:"concurrency"` // queues whose attributes load at the same time
+	CacheTTL    time.Duration `yaml:"cache_ttl"`   // how long attributes of queues outside the viewport are reused
+}
+
+// AlertSettings configures queue alert rules and how new alerts are announced.
+type AlertSettings struct {
+	Bell    bool        `yaml:"bell"`    // ring the terminal bell when a rule starts matching
+	Command string      `yaml:"command"` // shell command run when a rule starts matching
+	Rules   []AlertRule `yaml:"rules"`
+}
+
+// AlertRule raises an alert for queues matching Pattern when Condition holds.
+type AlertRule struct {
+	Name      string `yaml:"name"`
+	Pattern   string `yaml:"pattern"`  // shell pattern matched against the queue name, defaults to all queues
+	Condition string `yaml:"when"`     // e.g. "dlq > 0", "visible > 10000" or "oldest > 15m"
+	Severity  string `yaml:"severity"` // info, warning or critical (default)
+}
+
+// ProtectionSettings configures which queues must have their name typed to
+// confirm deleting, purging, deleting several messages or redriving.
+type ProtectionSettings struct {
+	Rules []ProtectionRule `yaml:"rules"`
+}
+
+// ProtectionRule protects the queues matching all of its conditions.
+type ProtectionRule struct {
+	Pattern  string            `yaml:"pattern"`  // shell pattern matched against the queue name
+	Tags     map[string]string `yaml:"tags"`     // tags the queue must have, e.g. env: prod
+	Context  string            `yaml:"context"`  // context the queue was loaded from
+	Cooldown time.Duration     `yaml:"cooldown"` // wait before the typed confirmation can be submitted
+}
+
+// HasTags reports whether the rule depends on the tags of a queue.
+func (r ProtectionRule) HasTags() bool {
+	return len(r.Tags) > 0
+}
+
+// Matches reports whether the rule protects a queue. Tags that were not
+// loaded are passed as nil.
+func (r ProtectionRule) Matches(queueName, context string, tags map[string]string) bool {
+	if r.Pattern != "" {
+		if ok, _ := path.Match(r.Pattern, queueName); !ok {
+			return false
+		}
+	}
+	if r.Context != "" && r.Context != context {
+		return false
+	}
+	for k, v := range r.Tags {
+		if tags[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// QueueSettings holds settings that apply to queues whose name matches Pattern.
+type QueueSettings struct {
+	Pattern  string           `yaml:"pattern"`  // shell pattern matched against the queue name
+	Decoders []string         `yaml:"decoders"` // decoding chain applied to message bodies, e.g. base64, gzip, json
+	Protobuf ProtobufSettings `yaml:"protobuf,omitempty"`
+}
+
+// ProtobufSettings configures the protobuf decoder for a queue.
+type ProtobufSettings struct {
+	DescriptorSet string `yaml:"descriptor_set"` // path to a FileDescriptorSet (protoc --descriptor_set_out)
+	MessageType   string `yaml:"message_type"`   // fully-qualified message name, e.g. acme.orders.v1.Order
+}
+
+// Path returns the location of the config file, following the XDG base
+// directory specification.
+func Path() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "kue", "config.yaml")
+}
+
+// Load reads the config file from Path. A missing file is not an error and
+// results in the default configuration.
+func Load() (Config, error) {
+	return LoadFile(Path())
+}
+
+// LoadFile reads the config file at the given path. Settings missing from the
+// file keep their default, unknown settings are an error. The values are not
+// validated, call Validate once command line flags have been applied.
+func LoadFile(file string) (Config, error) {
+	cfg := Default()
+	if file == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return cfg, fmt.Errorf("failed to parse config file %s: %w", file, err)
+	}
+
+	return cfg, nil
+}
+
+// Validate checks the values of the configuration, including values set by
+// command line flags.
+func (c Config) Validate() error {
+	var errs []error
+	if c.RefreshInterval < MinRefreshInterval {
+		errs = append(errs, fmt.Errorf("refresh_interval must be at least %s", MinRefreshInterval))
+	}
+	if c.Messages.FetchCount < 1 || c.Messages.FetchCount > MaxFetchCount {
+		errs = append(errs, fmt.Errorf("messages.fetch_count must be between 1 and %d", MaxFetchCount))
+	}
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: %w", err))
+	}
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		errs = append(errs, fmt.Errorf("log.format must be text or json"))
+	}
+	if c.Trash.Retention < MinRetention {
+		errs = append(errs, fmt.Errorf("trash.retention must be at least %s", MinRetention))
+	}
+	if c.Layout.Width < MinLayoutWidth {
+		errs = append(errs, fmt.Errorf("layout.width must be at least %d", MinLayoutWidth))
+	}
+	if c.Layout.Height < MinLayoutHeight {
+		errs = append(errs, fmt.Errorf("layout.height must be at least %d", MinLayoutHeight))
+	}
+	if c.Overview.Concurrency < 1 || c.Overview.Concurrency > MaxConcurrency {
+		errs = append(errs, fmt.Errorf("overview.concurrency must be between 1 and %d", MaxConcurrency))
+	}
+	if c.Overview.CacheTTL < MinRefreshInterval {
+		errs = append(errs, fmt.Errorf("overview.cache_ttl must be at least %s", MinRefreshInterval))
+	}
+	for _, q := range c.Queues {
+		if _, err := path.Match(q.Pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("queues: invalid pattern %q", q.Pattern))
+		}
+	}
+	if err := c.AWS.validateContexts(); err != nil {
+		errs = append(errs, err)
+	}
+	for i, r := range c.Protection.Rules {
+		if r.Pattern == "" && r.Context == "" && !r.HasTags() {
+			errs = append(errs, fmt.Errorf("protection.rules: rule %d has no pattern, tags or context", i+1))
+		}
+		if _, err := path.Match(r.Pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("protection.rules: invalid pattern %q", r.Pattern))
+		}
+		if r.Context != "" && !slices.ContainsFunc(c.AWS.Contexts, func(ctx ContextSettings) bool { return ctx.Name == r.Context }) {
+			errs = append(errs, fmt.Errorf("protection.rules: unknown context %q", r.Context))
+		}
+		if r.Cooldown < 0 || r.Cooldown > MaxCooldown {
+			errs = append(errs, fmt.Errorf("protection.rules: cooldown of rule %d must be between 0s and %s", i+1, MaxCooldown))
+		}
+	}
+	if c.AWS.Timeout < MinTimeout {
+		errs = append(errs, fmt.Errorf("aws.timeout must be at least %s", MinTimeout))
+	}
+	for _, operation := range slices.Sorted(maps.Keys(c.AWS.Timeouts)) {
+		if c.AWS.Timeouts[operation] < MinTimeout {
+			errs = append(errs, fmt.Errorf("aws.timeouts: %s must be at least %s", operation, MinTimeout))
+		}
+	}
+	if c.AWS.Retry.MaxAttempts < 1 || c.AWS.Retry.MaxAttempts > MaxAttempts {
+		errs = append(errs, fmt.Errorf("aws.retry.max_attempts must be between 1 and %d", MaxAttempts))
+	}
+	if c.AWS.Retry.MaxBackoff < MinTimeout {
+		errs = append(errs, fmt.Errorf("aws.retry.max_backoff must be at least %s", MinTimeout))
+	}
+	if c.AWS.Retry.RateLimit < 0 {
+		errs = append(errs, fmt.Errorf("aws.retry.rate_limit must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// validateContexts checks that every context has a unique name, the name is
+// what the queues of a context are labelled and filtered by.
+func (a AWSSettings) validateContexts() error {
+	seen := make(map[string]bool)
+	for i, c := range a.Contexts {
+		if c.Name == "" {
+			return fmt.Errorf("aws.contexts: context %d has no name", i+1)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("aws.contexts: duplicate context %q", c.Name)
+		}
+		seen[c.Name] = true
+	}
+	return nil
+}
+
+// QueueSettings returns the settings of the first entry whose pattern matches
+// the queue name, or empty settings if none match.
+func (c Config) QueueSettings(queueName string) QueueSettings {
+	for _, q := range c.Queues {
+		if ok, _ := path.Match(q.Pattern, queueName); ok {
+			return q
+		}
+	}
+	return QueueSettings{}
+}
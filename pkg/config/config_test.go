@@ -0,0 +1,257 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return file
+}
+
+func TestLoadFileMissing(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Expected no error for missing file, got %v", err)
+	}
+	if len(cfg.Queues) != 0 {
+		t.Errorf("Expected empty config, got %+v", cfg)
+	}
+}
+
+func TestLoadFileInvalid(t *testing.T) {
+	file := writeConfig(t, "queues: [")
+
+	if _, err := LoadFile(file); err == nil {
+		t.Error("Expected an error for invalid YAML")
+	}
+}
+
+func TestQueueSettingsMatchesPattern(t *testing.T) {
+	file := writeConfig(t, `
+queues:
+  - pattern: "orders-*"
+    decoders: [base64, gzip, json]
+  - pattern: "events"
+    decoders: [protobuf]
+    protobuf:
+      descriptor_set: /tmp/events.pb
+      message_type: acme.events.v1.Event
+`)
+
+	cfg, err := LoadFile(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	orders := cfg.QueueSettings("orders-dlq")
+	if len(orders.Decoders) != 3 || orders.Decoders[1] != "gzip" {
+		t.Errorf("Expected orders decoders, got %v", orders.Decoders)
+	}
+
+	events := cfg.QueueSettings("events")
+	if events.Protobuf.MessageType != "acme.events.v1.Event" {
+		t.Errorf("Expected protobuf message type, got %q", events.Protobuf.MessageType)
+	}
+
+	if other := cfg.QueueSettings("payments"); len(other.Decoders) != 0 {
+		t.Errorf("Expected no settings for unmatched queue, got %v", other.Decoders)
+	}
+}
+
+func TestPathUsesXDGConfigHome(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+
+	if got := Path(); got != filepath.Join("/xdg", "kue", "config.yaml") {
+		t.Errorf("Expected XDG path, got %q", got)
+	}
+}
+
+func TestEndpointFor(t *testing.T) {
+	file := writeConfig(t, `
+aws:
+  endpoint: http://localhost:9324
+  endpoints:
+    localstack: http://localhost:4566
+    prod: ""
+`)
+
+	cfg, err := LoadFile(file)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := map[string]string{
+		"localstack": "http://localhost:4566",
+		"prod":       "",
+		"dev":        "http://localhost:9324",
+	}
+	for profile, want := range tests {
+		if got := cfg.AWS.EndpointFor(profile); got != want {
+			t.Errorf("EndpointFor(%q) = %q, want %q", profile, got, want)
+		}
+	}
+
+	cfg.AWS.EndpointOverride = "https://sqs.eu-west-1.vpce.amazonaws.com"
+	if got := cfg.AWS.EndpointFor("localstack"); got != cfg.AWS.EndpointOverride {
+		t.Errorf("Expected the flag to take precedence, got %q", got)
+	}
+}
+
+func TestContexts(t *testing.T) {
+	file := writeConfig(t, `
+aws:
+  contexts:
+    - name: prod-eu
+      profile: prod
+      region: eu-west-1
+    - name: local
+      endpoint: http://localhost:4566
+      read_only: true
+`)
+
+	cfg, err := LoadFile(file)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cfg.AWS.Contexts) != 2 {
+		t.Fatalf("Expected 2 contexts, got %+v", cfg.AWS.Contexts)
+	}
+	if c := cfg.AWS.Contexts[0]; c.Profile != "prod" || c.Region != "eu-west-1" {
+		t.Errorf("Unexpected first context %+v", c)
+	}
+	if cfg.AWS.Contexts[0].ReadOnly || !cfg.AWS.Contexts[1].ReadOnly {
+		t.Errorf("Expected only the second context to be read-only, got %+v", cfg.AWS.Contexts)
+	}
+
+	for _, invalid := range []string{
+		"aws:\n  contexts:\n    - profile: prod\n",
+		"aws:\n  contexts:\n    - name: a\n    - name: a\n",
+	} {
+		cfg, err := LoadFile(writeConfig(t, invalid))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestLoadFileLeavesValidationToFlags(t *testing.T) {
+	file := writeConfig(t, "refresh_interval: 10ms\n")
+
+	cfg, err := LoadFile(file)
+	if err != nil {
+		t.Fatalf("Expected invalid values to load for flags to override, got %v", err)
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected the refresh interval from the file to be invalid")
+	}
+
+	cfg.RefreshInterval = 10 * time.Second
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected the overridden refresh interval to be valid, got %v", err)
+	}
+}
+
+func TestLoadFileDefaults(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/xdg-state")
+	file := writeConfig(t, `
+refresh_interval: 1m30s
+layout:
+  width: 180
+`)
+
+	cfg, err := LoadFile(file)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.RefreshInterval != 90*time.Second {
+		t.Errorf("Expected the configured refresh interval, got %s", cfg.RefreshInterval)
+	}
+	if cfg.Layout.Width != 180 || cfg.Layout.Height != 25 {
+		t.Errorf("Expected the configured width and the default height, got %+v", cfg.Layout)
+	}
+	if cfg.Messages.FetchCount != 10 {
+		t.Errorf("Expected the default fetch count, got %d", cfg.Messages.FetchCount)
+	}
+	if cfg.Log.File != filepath.Join("/xdg-state", "kue", "debug.log") {
+		t.Errorf("Expected the debug log in the state directory, got %q", cfg.Log.File)
+	}
+	if cfg.Audit.File != filepath.Join("/xdg-state", "kue", "audit.jsonl") {
+		t.Errorf("Expected the audit log in the state directory, got %q", cfg.Audit.File)
+	}
+	if cfg.Trash.File != filepath.Join("/xdg-state", "kue", "trash.jsonl") || cfg.Trash.Retention != 7*24*time.Hour {
+		t.Errorf("Expected the trash in the state directory for a week, got %+v", cfg.Trash)
+	}
+}
+
+func TestLoadFileUnknownField(t *testing.T) {
+	file := writeConfig(t, "refresh: 10s\n")
+
+	_, err := LoadFile(file)
+	if err == nil || !strings.Contains(err.Error(), "refresh") {
+		t.Errorf("Expected an error naming the unknown field, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]func(*Config){
+		"refresh_interval": func(c *Config) { c.RefreshInterval = 100 * time.Millisecond },
+		"fetch_count":      func(c *Config) { c.Messages.FetchCount = 11 },
+		"log.level":        func(c *Config) { c.Log.Level = "verbose" },
+		"log.format":       func(c *Config) { c.Log.Format = "xml" },
+		"layout.width":     func(c *Config) { c.Layout.Width = 40 },
+		"layout.height":    func(c *Config) { c.Layout.Height = 0 },
+		"concurrency":      func(c *Config) { c.Overview.Concurrency = 0 },
+		"cache_ttl":        func(c *Config) { c.Overview.CacheTTL = 0 },
+		"aws.timeout":      func(c *Config) { c.AWS.Timeout = 0 },
+		"ReceiveMessage":   func(c *Config) { c.AWS.Timeouts = map[string]time.Duration{"ReceiveMessage": time.Millisecond} },
+		"max_attempts":     func(c *Config) { c.AWS.Retry.MaxAttempts = 0 },
+		"max_backoff":      func(c *Config) { c.AWS.Retry.MaxBackoff = 0 },
+		"rate_limit":       func(c *Config) { c.AWS.Retry.RateLimit = -1 },
+		"invalid pattern":  func(c *Config) { c.Queues = []QueueSettings{{Pattern: "["}} },
+		"no pattern":       func(c *Config) { c.Protection.Rules = []ProtectionRule{{Cooldown: time.Second}} },
+		"unknown context":  func(c *Config) { c.Protection.Rules = []ProtectionRule{{Context: "prod"}} },
+		"cooldown":         func(c *Config) { c.Protection.Rules = []ProtectionRule{{Pattern: "*", Cooldown: time.Hour}} },
+		"trash.retention":  func(c *Config) { c.Trash.Retention = 0 },
+	}
+	for want, modify := range tests {
+		cfg := Default()
+		modify(&cfg)
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected an error mentioning %q, got %v", want, err)
+		}
+	}
+
+	if err := Default().Validate(); err != nil {
+		t.Errorf("Expected the defaults to be valid, got %v", err)
+	}
+}
+
+func TestProtectionRuleMatches(t *testing.T) {
+	rule := ProtectionRule{Pattern: "orders-*", Tags: map[string]string{"env": "prod"}}
+	if !rule.Matches("orders-eu", "", map[string]string{"env": "prod", "team": "a"}) {
+		t.Error("Expected a prod orders queue to be protected")
+	}
+	if rule.Matches("orders-eu", "", map[string]string{"env": "dev"}) {
+		t.Error("Expected a dev queue not to be protected")
+	}
+	if rule.Matches("payments", "", map[string]string{"env": "prod"}) {
+		t.Error("Expected a queue not matching the pattern not to be protected")
+	}
+
+	byContext := ProtectionRule{Context: "prod"}
+	if !byContext.Matches("orders", "prod", nil) || byContext.Matches("orders", "staging", nil) {
+		t.Error("Expected only the queues of the prod context to be protected")
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// State holds UI choices that persist between sessions. Unlike Config it is
+// written by kue itself.
+type State struct {
+	Overview OverviewState `yaml:"overview"`
+}
+
+// OverviewState holds the persisted state of the queue overview.
+type OverviewState struct {
+	SortColumn     string `yaml:"sort_column,omitempty"`
+	SortDescending bool   `yaml:"sort_descending,omitempty"`
+}
+
+// StatePath returns the location of the state file, following the XDG base
+// directory specification.
+func StatePath() string {
+	return statePath("state.yaml")
+}
+
+// statePath returns the location of a file in the kue state directory, or an
+// empty string when the home directory is unknown.
+func statePath(name string) string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "kue", name)
+}
+
+// LoadState reads the state file from StatePath. A missing file results in an
+// empty state.
+func LoadState() (State, error) {
+	return LoadStateFile(StatePath())
+}
+
+// LoadStateFile reads the state file at the given path.
+func LoadStateFile(file string) (State, error) {
+	var state State
+	if file == "" {
+		return state, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse state file %s: %w", file, err)
+	}
+
+	return state, nil
+}
+
+// Save writes the state to StatePath.
+func (s State) Save() error {
+	return s.SaveFile(StatePath())
+}
+
+// SaveFile writes the state to the given path, creating its directory.
+func (s State) SaveFile(file string) error {
+	if file == "" {
+		return errors.New("failed to save state: no state directory")
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "kue", "state.yaml")
+
+	want := State{Overview: OverviewState{SortColumn: "available", SortDescending: true}}
+	if err := want.SaveFile(file); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	got, err := LoadStateFile(file)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestLoadStateFileMissing(t *testing.T) {
+	state, err := LoadStateFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Expected no error for missing file, got %v", err)
+	}
+	if state != (State{}) {
+		t.Errorf("Expected empty state, got %+v", state)
+	}
+}
+
+func TestStatePathUsesXDGStateHome(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/xdg-state")
+
+	if got, want := StatePath(), filepath.Join("/tmp/xdg-state", "kue", "state.yaml"); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,31 @@
+package decode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+)
+
+// Base64 decodes standard or URL-safe base64, with or without padding.
+func Base64(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty input")
+	}
+
+	encodings := []*base64.Encoding{
+		base64.StdEncoding,
+		base64.RawStdEncoding,
+		base64.URLEncoding,
+		base64.RawURLEncoding,
+	}
+	for _, enc := range encodings {
+		out := make([]byte, enc.DecodedLen(len(data)))
+		n, err := enc.Decode(out, data)
+		if err == nil {
+			return out[:n], nil
+		}
+	}
+
+	return nil, fmt.Errorf("input is not valid base64")
+}
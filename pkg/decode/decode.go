@@ -0,0 +1,189 @@
+// Package decode turns encoded message bodies (base64, compressed, binary
+// serialisation formats) into a human readable representation.
+package decode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxDetectDepth limits how many layers auto-detection peels off a body.
+const maxDetectDepth = 4
+
+// Decoder transforms one encoding layer of a message body.
+type Decoder interface {
+	Decode(data []byte) ([]byte, error)
+}
+
+// DecoderFunc adapts an ordinary function to the Decoder interface.
+type DecoderFunc func(data []byte) ([]byte, error)
+
+// Decode calls f(data).
+func (f DecoderFunc) Decode(data []byte) ([]byte, error) {
+	return f(data)
+}
+
+// Options holds settings needed by decoders that cannot work on the body alone.
+type Options struct {
+	DescriptorSet string // path to a FileDescriptorSet used by the protobuf decoder
+	MessageType   string // fully-qualified protobuf message name
+}
+
+// Result is the outcome of decoding a message body.
+type Result struct {
+	Body  string   // decoded body, or the original body if nothing applied
+	Steps []string // names of the decoders that were applied, in order
+}
+
+// Registry holds the named decoders that chains can be built from.
+type Registry struct {
+	decoders map[string]Decoder
+	protobuf map[Options]protobufEntry // loaded descriptor sets, keyed by their options
+}
+
+// protobufEntry is a protobuf decoder, or the error creating it, so a broken
+// descriptor set is not read again for every message.
+type protobufEntry struct {
+	decoder Decoder
+	err     error
+}
+
+// NewRegistry returns a registry with the built-in decoders registered.
+func NewRegistry() *Registry {
+	r := &Registry{
+		decoders: make(map[string]Decoder),
+		protobuf: make(map[Options]protobufEntry),
+	}
+	r.Register("base64", DecoderFunc(Base64))
+	r.Register("gzip", DecoderFunc(Gzip))
+	r.Register("zstd", DecoderFunc(Zstd))
+	r.Register("msgpack", DecoderFunc(Msgpack))
+	r.Register("json", DecoderFunc(JSON))
+	return r
+}
+
+// Register adds or replaces a named decoder.
+func (r *Registry) Register(name string, d Decoder) {
+	r.decoders[name] = d
+}
+
+// Names returns the names of all registered decoders, including protobuf.
+func (r *Registry) Names() []string {
+	names := []string{"protobuf"}
+	for name := range r.decoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// step is a single named link in a chain.
+type step struct {
+	name    string
+	decoder Decoder
+}
+
+// Chain is an ordered list of decoders applied one after another.
+type Chain []step
+
+// Chain builds a decoding chain from decoder names. The protobuf decoder, or
+// the error creating it, is created from opts and cached for subsequent
+// chains.
+func (r *Registry) Chain(names []string, opts Options) (Chain, error) {
+	chain := make(Chain, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "protobuf" {
+			d, err := r.protobufDecoder(opts)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, step{name: name, decoder: d})
+			continue
+		}
+		d, ok := r.decoders[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown decoder %q", name)
+		}
+		chain = append(chain, step{name: name, decoder: d})
+	}
+	return chain, nil
+}
+
+func (r *Registry) protobufDecoder(opts Options) (Decoder, error) {
+	e, ok := r.protobuf[opts]
+	if !ok {
+		e.decoder, e.err = NewProtobuf(opts.DescriptorSet, opts.MessageType)
+		r.protobuf[opts] = e
+	}
+	return e.decoder, e.err
+}
+
+// Decode applies every decoder in the chain to body. It stops at the first
+// failing step and returns the error together with the steps that succeeded.
+func (c Chain) Decode(body string) (Result, error) {
+	data := []byte(body)
+	var steps []string
+	for _, s := range c {
+		out, err := s.decoder.Decode(data)
+		if err != nil {
+			return Result{Body: body, Steps: steps}, fmt.Errorf("%s: %w", s.name, err)
+		}
+		data = out
+		steps = append(steps, s.name)
+	}
+	return Result{Body: string(data), Steps: steps}, nil
+}
+
+// Detect peels off base64, gzip and zstd layers it can recognise and pretty
+// prints JSON. Binary formats such as protobuf and msgpack are ambiguous and
+// must be configured explicitly. When no readable text results, the original
+// body is returned unchanged.
+func Detect(body string) Result {
+	data := []byte(body)
+	var steps []string
+
+	for i := 0; i < maxDetectDepth; i++ {
+		name, out, ok := detectLayer(data)
+		if !ok {
+			break
+		}
+		data = out
+		steps = append(steps, name)
+	}
+
+	if pretty, err := JSON(data); err == nil {
+		data = pretty
+		steps = append(steps, "json")
+	}
+
+	if !utf8.Valid(data) {
+		return Result{Body: body}
+	}
+	return Result{Body: string(data), Steps: steps}
+}
+
+// detectLayer recognises a single encoding layer and decodes it.
+func detectLayer(data []byte) (string, []byte, bool) {
+	switch {
+	case isGzip(data):
+		out, err := Gzip(data)
+		return "gzip", out, err == nil
+	case isZstd(data):
+		out, err := Zstd(data)
+		return "zstd", out, err == nil
+	}
+
+	// Plain words can be valid base64 too, so only accept the layer when it
+	// reveals something recognisable underneath.
+	out, err := Base64(data)
+	if err != nil {
+		return "", nil, false
+	}
+	if isGzip(out) || isZstd(out) || isJSON(out) {
+		return "base64", out, true
+	}
+	return "", nil, false
+}
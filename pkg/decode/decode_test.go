@@ -0,0 +1,152 @@
+package decode
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func gzipString(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatalf("gzip write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("gzip close failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetectPlainText(t *testing.T) {
+	result := Detect("hello world")
+
+	if result.Body != "hello world" {
+		t.Errorf("Expected body to be unchanged, got %q", result.Body)
+	}
+	if len(result.Steps) != 0 {
+		t.Errorf("Expected no steps, got %v", result.Steps)
+	}
+}
+
+func TestDetectBase64WordIsNotDecoded(t *testing.T) {
+	// "test" is valid base64 but decodes to garbage
+	result := Detect("test")
+
+	if result.Body != "test" {
+		t.Errorf("Expected body to be unchanged, got %q", result.Body)
+	}
+}
+
+func TestDetectJSON(t *testing.T) {
+	result := Detect(`{"key":"value"}`)
+
+	if !strings.Contains(result.Body, "\n  \"key\": \"value\"") {
+		t.Errorf("Expected pretty printed JSON, got %q", result.Body)
+	}
+	if strings.Join(result.Steps, ",") != "json" {
+		t.Errorf("Expected steps [json], got %v", result.Steps)
+	}
+}
+
+func TestDetectBase64Gzip(t *testing.T) {
+	body := base64.StdEncoding.EncodeToString(gzipString(t, `{"order":{"status":"FAILED"}}`))
+
+	result := Detect(body)
+
+	if strings.Join(result.Steps, ",") != "base64,gzip,json" {
+		t.Errorf("Expected steps [base64 gzip json], got %v", result.Steps)
+	}
+	if !strings.Contains(result.Body, `"status": "FAILED"`) {
+		t.Errorf("Expected decoded JSON, got %q", result.Body)
+	}
+}
+
+func TestDetectBase64Zstd(t *testing.T) {
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatalf("failed to create zstd encoder: %v", err)
+	}
+	compressed := enc.EncodeAll([]byte(`["a","b"]`), nil)
+	enc.Close()
+
+	result := Detect(base64.StdEncoding.EncodeToString(compressed))
+
+	if strings.Join(result.Steps, ",") != "base64,zstd,json" {
+		t.Errorf("Expected steps [base64 zstd json], got %v", result.Steps)
+	}
+}
+
+func TestChainDecode(t *testing.T) {
+	r := NewRegistry()
+	chain, err := r.Chain([]string{"base64", "gzip"}, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := base64.StdEncoding.EncodeToString(gzipString(t, "plain payload"))
+	result, err := chain.Decode(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Body != "plain payload" {
+		t.Errorf("Expected 'plain payload', got %q", result.Body)
+	}
+}
+
+func TestChainDecodeFailureKeepsOriginalBody(t *testing.T) {
+	r := NewRegistry()
+	chain, err := r.Chain([]string{"base64", "gzip"}, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := base64.StdEncoding.EncodeToString([]byte("not gzip"))
+	result, err := chain.Decode(body)
+	if err == nil {
+		t.Fatal("Expected an error for invalid gzip data")
+	}
+	if result.Body != body {
+		t.Errorf("Expected original body, got %q", result.Body)
+	}
+	if strings.Join(result.Steps, ",") != "base64" {
+		t.Errorf("Expected successful steps [base64], got %v", result.Steps)
+	}
+}
+
+func TestChainUnknownDecoder(t *testing.T) {
+	r := NewRegistry()
+	if _, err := r.Chain([]string{"rot13"}, Options{}); err == nil {
+		t.Error("Expected an error for an unknown decoder")
+	}
+}
+
+func TestChainProtobufRequiresDescriptor(t *testing.T) {
+	r := NewRegistry()
+	if _, err := r.Chain([]string{"protobuf"}, Options{}); err == nil {
+		t.Error("Expected an error when no descriptor set is configured")
+	}
+}
+
+func TestMsgpack(t *testing.T) {
+	data, err := msgpack.Marshal(map[string]interface{}{"tenant": "acme"})
+	if err != nil {
+		t.Fatalf("msgpack marshal failed: %v", err)
+	}
+
+	out, err := Msgpack(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(string(out), `"tenant": "acme"`) {
+		t.Errorf("Expected JSON output, got %q", out)
+	}
+}
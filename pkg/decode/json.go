@@ -0,0 +1,29 @@
+package decode
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+func isJSON(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || (data[0] != '{' && data[0] != '[') {
+		return false
+	}
+	return json.Valid(data)
+}
+
+// JSON pretty-prints a JSON document with two space indentation.
+func JSON(data []byte) ([]byte, error) {
+	if !isJSON(data) {
+		return nil, fmt.Errorf("input is not a JSON object or array")
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, bytes.TrimSpace(data), "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to indent JSON: %w", err)
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,23 @@
+package decode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Msgpack decodes a MessagePack document and renders it as indented JSON.
+func Msgpack(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := msgpack.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode msgpack: %w", err)
+	}
+
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render msgpack as JSON: %w", err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,69 @@
+package decode
+
+import (
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// protobufDecoder decodes binary protobuf messages of a single type.
+type protobufDecoder struct {
+	descriptor protoreflect.MessageDescriptor
+}
+
+// NewProtobuf loads a FileDescriptorSet (as produced by
+// protoc --include_imports --descriptor_set_out) and returns a decoder that
+// renders messages of messageType as JSON.
+func NewProtobuf(descriptorSet string, messageType string) (Decoder, error) {
+	if descriptorSet == "" || messageType == "" {
+		return nil, fmt.Errorf("protobuf decoder requires a descriptor set and message type")
+	}
+
+	data, err := os.ReadFile(descriptorSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read descriptor set: %w", err)
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse descriptor set: %w", err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load descriptor set: %w", err)
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(messageType))
+	if err != nil {
+		return nil, fmt.Errorf("message type %s not found: %w", messageType, err)
+	}
+
+	msgDesc, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message type", messageType)
+	}
+
+	return protobufDecoder{descriptor: msgDesc}, nil
+}
+
+// Decode unmarshals data into a dynamic message and renders it as JSON.
+func (p protobufDecoder) Decode(data []byte) ([]byte, error) {
+	msg := dynamicpb.NewMessage(p.descriptor)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("failed to decode protobuf: %w", err)
+	}
+
+	out, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render protobuf as JSON: %w", err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,104 @@
+package decode
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// writeDescriptorSet writes a descriptor set containing acme.Order{string id = 1;}.
+func writeDescriptorSet(t *testing.T) (string, *descriptorpb.FileDescriptorProto) {
+	t.Helper()
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("order.proto"),
+		Package: proto.String("acme"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Order"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("id"),
+				JsonName: proto.String("id"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			}},
+		}},
+	}
+
+	data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}})
+	if err != nil {
+		t.Fatalf("failed to marshal descriptor set: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "order.pb")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write descriptor set: %v", err)
+	}
+	return path, file
+}
+
+func TestProtobufDecode(t *testing.T) {
+	path, fileProto := writeDescriptorSet(t)
+
+	fd, err := protodesc.NewFile(fileProto, nil)
+	if err != nil {
+		t.Fatalf("failed to build file descriptor: %v", err)
+	}
+	msg := dynamicpb.NewMessage(fd.Messages().ByName("Order"))
+	msg.Set(fd.Messages().ByName("Order").Fields().ByName("id"), protoreflect.ValueOfString("order-42"))
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("failed to marshal message: %v", err)
+	}
+
+	d, err := NewProtobuf(path, "acme.Order")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := d.Decode(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(out), "order-42") {
+		t.Errorf("Expected decoded output to contain the order id, got %q", out)
+	}
+}
+
+func TestChainCachesProtobufErrors(t *testing.T) {
+	path, _ := writeDescriptorSet(t)
+	missing := filepath.Join(t.TempDir(), "order.pb")
+	opts := Options{DescriptorSet: missing, MessageType: "acme.Order"}
+
+	r := NewRegistry()
+	if _, err := r.Chain([]string{"protobuf"}, opts); err == nil {
+		t.Fatal("Expected an error for a missing descriptor set")
+	}
+
+	// The descriptor set is not read again for the same options
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read descriptor set: %v", err)
+	}
+	if err := os.WriteFile(missing, data, 0o600); err != nil {
+		t.Fatalf("failed to write descriptor set: %v", err)
+	}
+	if _, err := r.Chain([]string{"protobuf"}, opts); err == nil {
+		t.Error("Expected the cached error")
+	}
+}
+
+func TestProtobufUnknownMessageType(t *testing.T) {
+	path, _ := writeDescriptorSet(t)
+
+	if _, err := NewProtobuf(path, "acme.Missing"); err == nil {
+		t.Error("Expected an error for an unknown message type")
+	}
+}
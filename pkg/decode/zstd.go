@@ -0,0 +1,31 @@
+package decode
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdMagic is the header every zstd frame starts with.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+func isZstd(data []byte) bool {
+	return bytes.HasPrefix(data, zstdMagic)
+}
+
+// Zstd decompresses a zstd stream.
+func Zstd(data []byte) ([]byte, error) {
+	d, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+	}
+	defer d.Close()
+
+	out, err := d.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress zstd stream: %w", err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,95 @@
+// Package envelope recognises the notification envelopes AWS services wrap
+// around SQS message payloads (SNS, EventBridge and S3 event notifications).
+package envelope
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// maxDepth limits how many nested envelopes are unwrapped, e.g. S3 → SNS → SQS.
+const maxDepth = 4
+
+// Field is a single piece of envelope metadata.
+type Field struct {
+	Label string
+	Value string
+}
+
+// Layer describes one envelope that was removed from the payload.
+type Layer struct {
+	Kind   string // "SNS", "EventBridge" or "S3"
+	Fields []Field
+}
+
+// Envelope is the result of unwrapping a message body.
+type Envelope struct {
+	Layers  []Layer // outermost envelope first
+	Payload string  // inner payload, or the original body if nothing was unwrapped
+}
+
+// Wrapped reports whether any envelope was recognised.
+func (e Envelope) Wrapped() bool {
+	return len(e.Layers) > 0
+}
+
+// Kinds returns the envelope kinds from outermost to innermost.
+func (e Envelope) Kinds() []string {
+	kinds := make([]string, 0, len(e.Layers))
+	for _, l := range e.Layers {
+		kinds = append(kinds, l.Kind)
+	}
+	return kinds
+}
+
+// unwrapper recognises a single envelope shape. It returns the layer metadata,
+// the inner payload and whether the payload may contain another envelope.
+type unwrapper func(body []byte) (layer Layer, payload string, nested bool, ok bool)
+
+var unwrappers = []unwrapper{
+	unwrapSNS,
+	unwrapEventBridge,
+	unwrapS3,
+}
+
+// Unwrap removes any recognised envelopes from body.
+func Unwrap(body string) Envelope {
+	env := Envelope{Payload: body}
+
+	for depth := 0; depth < maxDepth; depth++ {
+		data := bytes.TrimSpace([]byte(env.Payload))
+		if len(data) == 0 || data[0] != '{' {
+			break
+		}
+
+		matched, nested := false, false
+		for _, unwrap := range unwrappers {
+			layer, payload, more, ok := unwrap(data)
+			if !ok {
+				continue
+			}
+			env.Layers = append(env.Layers, layer)
+			env.Payload = payload
+			matched, nested = true, more
+			break
+		}
+		if !matched || !nested {
+			break
+		}
+	}
+
+	return env
+}
+
+// appendField adds a field only when it has a value.
+func appendField(fields []Field, label string, value string) []Field {
+	if value == "" {
+		return fields
+	}
+	return append(fields, Field{Label: label, Value: value})
+}
+
+// unmarshal decodes data into v and reports success.
+func unmarshal(data []byte, v interface{}) bool {
+	return json.Unmarshal(data, v) == nil
+}
@@ -0,0 +1,114 @@
+package envelope
+
+import (
+	"strings"
+	"testing"
+)
+
+func fieldValue(layer Layer, label string) string {
+	for _, f := range layer.Fields {
+		if f.Label == label {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+func TestUnwrapPlainBody(t *testing.T) {
+	env := Unwrap(`{"order":"1"}`)
+
+	if env.Wrapped() {
+		t.Errorf("Expected no envelope, got %v", env.Kinds())
+	}
+	if env.Payload != `{"order":"1"}` {
+		t.Errorf("Expected payload to be unchanged, got %q", env.Payload)
+	}
+}
+
+func TestUnwrapSNS(t *testing.T) {
+	body := `{
+		"Type": "Notification",
+		"MessageId": "sns-1",
+		"TopicArn": "arn:aws:sns:us-east-1:000000000000:orders",
+		"Subject": "order created",
+		"Message": "{\"order\":\"1\"}",
+		"Timestamp": "2024-01-15T10:30:00.000Z",
+		"MessageAttributes": {"tenant": {"Type": "String", "Value": "acme"}}
+	}`
+
+	env := Unwrap(body)
+
+	if strings.Join(env.Kinds(), ",") != "SNS" {
+		t.Fatalf("Expected SNS envelope, got %v", env.Kinds())
+	}
+	if env.Payload != `{"order":"1"}` {
+		t.Errorf("Expected inner message, got %q", env.Payload)
+	}
+	layer := env.Layers[0]
+	if fieldValue(layer, "Topic ARN") != "arn:aws:sns:us-east-1:000000000000:orders" {
+		t.Error("Expected topic ARN field")
+	}
+	if fieldValue(layer, "Subject") != "order created" {
+		t.Error("Expected subject field")
+	}
+	if fieldValue(layer, "tenant") != "acme" {
+		t.Error("Expected SNS message attribute field")
+	}
+}
+
+func TestUnwrapEventBridge(t *testing.T) {
+	body := `{
+		"id": "evt-1",
+		"detail-type": "Object Created",
+		"source": "aws.s3",
+		"account": "000000000000",
+		"region": "us-east-1",
+		"time": "2024-01-15T10:30:00Z",
+		"detail": {"bucket": {"name": "uploads"}, "object": {"key": "a.txt"}}
+	}`
+
+	env := Unwrap(body)
+
+	if strings.Join(env.Kinds(), ",") != "EventBridge" {
+		t.Fatalf("Expected EventBridge envelope, got %v", env.Kinds())
+	}
+	layer := env.Layers[0]
+	if fieldValue(layer, "Detail Type") != "Object Created" {
+		t.Error("Expected detail type field")
+	}
+	if fieldValue(layer, "Bucket") != "uploads" || fieldValue(layer, "Key") != "a.txt" {
+		t.Error("Expected bucket and key fields")
+	}
+	if !strings.Contains(env.Payload, `"bucket"`) {
+		t.Errorf("Expected detail as payload, got %q", env.Payload)
+	}
+}
+
+func TestUnwrapS3ThroughSNS(t *testing.T) {
+	body := `{
+		"Type": "Notification",
+		"TopicArn": "arn:aws:sns:us-east-1:000000000000:uploads",
+		"Message": "{\"Records\":[{\"eventSource\":\"aws:s3\",\"eventName\":\"ObjectCreated:Put\",\"s3\":{\"bucket\":{\"name\":\"uploads\"},\"object\":{\"key\":\"my+file.txt\",\"size\":12}}}]}"
+	}`
+
+	env := Unwrap(body)
+
+	if strings.Join(env.Kinds(), ",") != "SNS,S3" {
+		t.Fatalf("Expected SNS and S3 envelopes, got %v", env.Kinds())
+	}
+	s3 := env.Layers[1]
+	if fieldValue(s3, "Key") != "my file.txt" {
+		t.Errorf("Expected unescaped key, got %q", fieldValue(s3, "Key"))
+	}
+	if fieldValue(s3, "Event") != "ObjectCreated:Put" {
+		t.Error("Expected event name field")
+	}
+}
+
+func TestUnwrapIgnoresNonNotificationJSON(t *testing.T) {
+	env := Unwrap(`{"Type": "SubscriptionConfirmation", "TopicArn": "arn"}`)
+
+	if env.Wrapped() {
+		t.Errorf("Expected no envelope, got %v", env.Kinds())
+	}
+}
@@ -0,0 +1,49 @@
+package envelope
+
+import "encoding/json"
+
+// eventBridgeEvent is the JSON document EventBridge delivers to SQS targets.
+type eventBridgeEvent struct {
+	ID         string          `json:"id"`
+	Source     string          `json:"source"`
+	DetailType string          `json:"detail-type"`
+	Account    string          `json:"account"`
+	Region     string          `json:"region"`
+	Time       string          `json:"time"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+// s3EventDetail holds the fields of S3 events delivered through EventBridge.
+type s3EventDetail struct {
+	Bucket struct {
+		Name string `json:"name"`
+	} `json:"bucket"`
+	Object struct {
+		Key string `json:"key"`
+	} `json:"object"`
+}
+
+func unwrapEventBridge(body []byte) (Layer, string, bool, bool) {
+	var e eventBridgeEvent
+	if !unmarshal(body, &e) || e.Source == "" || e.DetailType == "" || e.Detail == nil {
+		return Layer{}, "", false, false
+	}
+
+	var fields []Field
+	fields = appendField(fields, "Source", e.Source)
+	fields = appendField(fields, "Detail Type", e.DetailType)
+	fields = appendField(fields, "Event ID", e.ID)
+	fields = appendField(fields, "Account", e.Account)
+	fields = appendField(fields, "Region", e.Region)
+	fields = appendField(fields, "Time", e.Time)
+
+	if e.Source == "aws.s3" {
+		var detail s3EventDetail
+		if unmarshal(e.Detail, &detail) {
+			fields = appendField(fields, "Bucket", detail.Bucket.Name)
+			fields = appendField(fields, "Key", detail.Object.Key)
+		}
+	}
+
+	return Layer{Kind: "EventBridge", Fields: fields}, string(e.Detail), false, true
+}
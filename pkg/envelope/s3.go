@@ -0,0 +1,68 @@
+package envelope
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// s3Notification is the JSON document S3 event notifications deliver.
+type s3Notification struct {
+	Records []struct {
+		EventSource string `json:"eventSource"`
+		EventName   string `json:"eventName"`
+		EventTime   string `json:"eventTime"`
+		S3          struct {
+			Bucket struct {
+				Name string `json:"name"`
+			} `json:"bucket"`
+			Object struct {
+				Key  string `json:"key"`
+				Size int64  `json:"size"`
+			} `json:"object"`
+		} `json:"s3"`
+	} `json:"Records"`
+
+	// Sent once when a notification configuration is created.
+	Event  string `json:"Event"`
+	Bucket string `json:"Bucket"`
+}
+
+// unwrapS3 extracts bucket and key metadata. S3 notifications carry no inner
+// payload, so the notification itself remains the payload.
+func unwrapS3(body []byte) (Layer, string, bool, bool) {
+	var n s3Notification
+	if !unmarshal(body, &n) {
+		return Layer{}, "", false, false
+	}
+
+	if n.Event == "s3:TestEvent" {
+		var fields []Field
+		fields = appendField(fields, "Event", n.Event)
+		fields = appendField(fields, "Bucket", n.Bucket)
+		return Layer{Kind: "S3", Fields: fields}, string(body), false, true
+	}
+
+	if len(n.Records) == 0 || n.Records[0].EventSource != "aws:s3" {
+		return Layer{}, "", false, false
+	}
+
+	record := n.Records[0]
+	key := record.S3.Object.Key
+	if unescaped, err := url.QueryUnescape(key); err == nil {
+		key = unescaped
+	}
+
+	var fields []Field
+	fields = appendField(fields, "Event", record.EventName)
+	fields = appendField(fields, "Bucket", record.S3.Bucket.Name)
+	fields = appendField(fields, "Key", key)
+	if record.S3.Object.Size > 0 {
+		fields = appendField(fields, "Size", strconv.FormatInt(record.S3.Object.Size, 10)+" bytes")
+	}
+	fields = appendField(fields, "Time", record.EventTime)
+	if len(n.Records) > 1 {
+		fields = appendField(fields, "Records", strconv.Itoa(len(n.Records)))
+	}
+
+	return Layer{Kind: "S3", Fields: fields}, string(body), false, true
+}
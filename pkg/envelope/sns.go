@@ -0,0 +1,42 @@
+package envelope
+
+import "sort"
+
+// snsNotification is the JSON document SNS delivers to SQS subscriptions
+// without raw message delivery.
+type snsNotification struct {
+	Type              string `json:"Type"`
+	MessageID         string `json:"MessageId"`
+	TopicArn          string `json:"TopicArn"`
+	Subject           string `json:"Subject"`
+	Message           string `json:"Message"`
+	Timestamp         string `json:"Timestamp"`
+	MessageAttributes map[string]struct {
+		Type  string `json:"Type"`
+		Value string `json:"Value"`
+	} `json:"MessageAttributes"`
+}
+
+func unwrapSNS(body []byte) (Layer, string, bool, bool) {
+	var n snsNotification
+	if !unmarshal(body, &n) || n.Type != "Notification" || n.TopicArn == "" {
+		return Layer{}, "", false, false
+	}
+
+	var fields []Field
+	fields = appendField(fields, "Topic ARN", n.TopicArn)
+	fields = appendField(fields, "Subject", n.Subject)
+	fields = appendField(fields, "SNS Message ID", n.MessageID)
+	fields = appendField(fields, "Published", n.Timestamp)
+
+	names := make([]string, 0, len(n.MessageAttributes))
+	for name := range n.MessageAttributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fields = appendField(fields, name, n.MessageAttributes[name].Value)
+	}
+
+	return Layer{Kind: "SNS", Fields: fields}, n.Message, true, true
+}
@@ -0,0 +1,199 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// node is an expression tree node.
+type node interface {
+	eval(s Subject) interface{}
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(s Subject) interface{} {
+	return truthy(n.left.eval(s)) || truthy(n.right.eval(s))
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(s Subject) interface{} {
+	return truthy(n.left.eval(s)) && truthy(n.right.eval(s))
+}
+
+type notNode struct{ operand node }
+
+func (n notNode) eval(s Subject) interface{} {
+	return !truthy(n.operand.eval(s))
+}
+
+type literalNode struct{ value interface{} }
+
+func (n literalNode) eval(Subject) interface{} {
+	return n.value
+}
+
+// missing marks a reference that does not resolve on the subject.
+type missing struct{}
+
+type refNode struct{ ref Ref }
+
+func (n refNode) eval(s Subject) interface{} {
+	v, ok := s.Lookup(n.ref)
+	if !ok {
+		return missing{}
+	}
+	return v
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+	regex       *regexp.Regexp // set for =~
+}
+
+func (n compareNode) eval(s Subject) interface{} {
+	l, r := n.left.eval(s), n.right.eval(s)
+	if _, ok := l.(missing); ok {
+		return n.op == "!="
+	}
+
+	switch n.op {
+	case "=~":
+		return n.regex.MatchString(toString(l))
+	case "contains":
+		return strings.Contains(strings.ToLower(toString(l)), strings.ToLower(toString(r)))
+	}
+
+	c, ok := compare(l, r)
+	if !ok {
+		return n.op == "!="
+	}
+	switch n.op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return false
+}
+
+// compare orders two values, converting between numbers, numeric strings and
+// durations where needed. It reports false when the values are incomparable.
+func compare(l, r interface{}) (int, bool) {
+	if ld, ok := l.(time.Duration); ok {
+		if rd, ok := toDuration(r); ok {
+			return cmp(float64(ld), float64(rd)), true
+		}
+	}
+	if rd, ok := r.(time.Duration); ok {
+		if ld, ok := toDuration(l); ok {
+			return cmp(float64(ld), float64(rd)), true
+		}
+	}
+
+	if lb, ok := l.(bool); ok {
+		rb, ok := r.(bool)
+		if !ok {
+			return 0, false
+		}
+		if lb == rb {
+			return 0, true
+		}
+		return 1, true
+	}
+
+	if l == nil || r == nil {
+		if l == r {
+			return 0, true
+		}
+		return 1, true
+	}
+
+	ln, lok := toNumber(l)
+	rn, rok := toNumber(r)
+	if lok && rok {
+		return cmp(ln, rn), true
+	}
+
+	return strings.Compare(toString(l), toString(r)), true
+}
+
+func cmp(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// toDuration converts durations and plain numbers (seconds) to a duration.
+func toDuration(v interface{}) (time.Duration, bool) {
+	if d, ok := v.(time.Duration); ok {
+		return d, true
+	}
+	if n, ok := toNumber(v); ok {
+		return time.Duration(n * float64(time.Second)), true
+	}
+	if s, ok := v.(string); ok {
+		d, err := parseDuration(s)
+		return d, err == nil
+	}
+	return 0, false
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case nil, missing:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// truthy reports whether a value counts as true when used on its own.
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case missing, nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case float64:
+		return v != 0
+	}
+	return true
+}
@@ -0,0 +1,198 @@
+// Package filter implements the expression language used to filter messages,
+// e.g. `.order.status == "FAILED" and receiveCount > 3`, and the query syntax
+// used to filter queues, e.g. `tag:team=payments dlq:nonempty`.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SyntaxError describes an invalid expression.
+type SyntaxError struct {
+	Pos int // byte offset in the expression
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("col %d: %s", e.Pos+1, e.Msg)
+}
+
+// RefKind identifies what a reference addresses.
+type RefKind int
+
+const (
+	BodyPath    RefKind = iota // .order.items[0].id
+	MessageAttr                // attr.tenant
+	SystemAttr                 // sys.SenderId
+	Field                      // receiveCount, age, id, ...
+)
+
+// Ref is a reference to a value of the subject being filtered.
+type Ref struct {
+	Kind RefKind
+	Name string        // attribute or field name
+	Path []interface{} // body path steps: string keys and int indexes
+}
+
+// Subject is something an expression can be evaluated against.
+type Subject interface {
+	Lookup(ref Ref) (interface{}, bool)
+}
+
+// Expr is a compiled filter expression.
+type Expr struct {
+	root node
+}
+
+// Match reports whether the subject satisfies the expression.
+func (e *Expr) Match(s Subject) bool {
+	return truthy(e.root.eval(s))
+}
+
+// fields are the named fields a message subject provides.
+var fields = map[string]bool{
+	"id":           true,
+	"body":         true,
+	"size":         true,
+	"age":          true,
+	"receiveCount": true,
+	"group":        true,
+	"dedup":        true,
+}
+
+// Compile parses an expression.
+func Compile(input string) (*Expr, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.typ != tokEOF {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.val)}
+	}
+	return &Expr{root: root}, nil
+}
+
+// CompileFilter compiles filter text that is an expression, or returns a nil
+// expression for a plain substring search. Text that only contains an
+// operator, such as `<order>` or `a->b`, is searched for as is when it does
+// not parse as an expression.
+func CompileFilter(input string) (*Expr, error) {
+	switch {
+	case startsLikeExpression(input):
+		return Compile(input)
+	case containsOperator(input):
+		if expr, err := Compile(input); err == nil {
+			return expr, nil
+		}
+	}
+	return nil, nil
+}
+
+// startsLikeExpression reports whether the text starts with a reference, a
+// parenthesis or a negation.
+func startsLikeExpression(input string) bool {
+	trimmed := strings.TrimSpace(input)
+	if strings.HasPrefix(trimmed, ".") || strings.HasPrefix(trimmed, "(") ||
+		strings.HasPrefix(trimmed, "attr.") || strings.HasPrefix(trimmed, "sys.") ||
+		strings.HasPrefix(trimmed, "not ") || strings.HasPrefix(trimmed, "!") {
+		return true
+	}
+	first := strings.FieldsFunc(trimmed, func(r rune) bool { return r == ' ' })
+	return len(first) > 1 && fields[first[0]]
+}
+
+// containsOperator reports whether the text contains a comparison or logical
+// operator.
+func containsOperator(input string) bool {
+	for _, op := range []string{"==", "!=", ">", "<", "=~", "&&", "||"} {
+		if strings.Contains(input, op) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRef turns an identifier into a reference.
+func parseRef(t token) (Ref, error) {
+	switch {
+	case strings.HasPrefix(t.val, "."):
+		path, err := parsePath(t.val, t.pos)
+		if err != nil {
+			return Ref{}, err
+		}
+		return Ref{Kind: BodyPath, Path: path}, nil
+	case strings.HasPrefix(t.val, "attr."):
+		return Ref{Kind: MessageAttr, Name: strings.TrimPrefix(t.val, "attr.")}, nil
+	case strings.HasPrefix(t.val, "sys."):
+		return Ref{Kind: SystemAttr, Name: strings.TrimPrefix(t.val, "sys.")}, nil
+	case fields[t.val]:
+		return Ref{Kind: Field, Name: t.val}, nil
+	}
+	return Ref{}, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unknown field %q", t.val)}
+}
+
+// parsePath parses a body path such as .order.items[0].id.
+func parsePath(s string, pos int) ([]interface{}, error) {
+	var path []interface{}
+	for _, part := range strings.Split(strings.TrimPrefix(s, "."), ".") {
+		if part == "" {
+			if len(path) == 0 && s == "." {
+				return nil, nil
+			}
+			return nil, &SyntaxError{Pos: pos, Msg: "empty path segment"}
+		}
+		name := part
+		if idx := strings.IndexByte(part, '['); idx >= 0 {
+			name = part[:idx]
+			part = part[idx:]
+		} else {
+			part = ""
+		}
+		if name != "" {
+			path = append(path, name)
+		}
+		for part != "" {
+			end := strings.IndexByte(part, ']')
+			if !strings.HasPrefix(part, "[") || end < 0 {
+				return nil, &SyntaxError{Pos: pos, Msg: "invalid index in " + s}
+			}
+			n, err := strconv.Atoi(part[1:end])
+			if err != nil {
+				return nil, &SyntaxError{Pos: pos, Msg: "invalid index in " + s}
+			}
+			path = append(path, n)
+			part = part[end+1:]
+		}
+	}
+	return path, nil
+}
+
+// parseDuration parses Go durations with an additional day unit, e.g. 2d.
+func parseDuration(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// compileRegex compiles a regular expression literal, reporting errors at pos.
+func compileRegex(pattern string, pos int) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, &SyntaxError{Pos: pos, Msg: "invalid regular expression"}
+	}
+	return re, nil
+}
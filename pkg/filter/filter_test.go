@@ -0,0 +1,127 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+var testNow = time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+func testSubject() *MessageSubject {
+	msg := kue.Message{
+		MessageID:    "msg-1",
+		Body:         `{"order":{"status":"FAILED","items":[{"sku":"A-1"}],"total":42.5}}`,
+		ReceiveCount: "5",
+		Attributes: map[string]string{
+			"SentTimestamp": "1705312800000", // 2024-01-15T10:00:00Z
+			"SenderId":      "AIDA123",
+		},
+		MessageAttributes: map[string]string{
+			"tenant": "acme",
+		},
+	}
+	return NewMessageSubject(msg, msg.Body, testNow)
+}
+
+func TestMatch(t *testing.T) {
+	tests := map[string]bool{
+		`.order.status == "FAILED"`:                      true,
+		`.order.status != "FAILED"`:                      false,
+		`.order.items[0].sku == "A-1"`:                   true,
+		`.order.items[1].sku == "A-1"`:                   false,
+		`.order.total > 40`:                              true,
+		`.order.total <= 40`:                             false,
+		`attr.tenant == "acme"`:                          true,
+		`attr.tenant == 'other'`:                         false,
+		`attr.missing == "x"`:                            false,
+		`attr.missing != "x"`:                            true,
+		`sys.SenderId =~ /^AIDA/`:                        true,
+		`receiveCount > 3`:                               true,
+		`receiveCount >= 6`:                              false,
+		`age > 1h`:                                       true,
+		`age > 1h30m`:                                    true,
+		`age > 3h`:                                       false,
+		`age < 1d`:                                       true,
+		`id contains "MSG"`:                              true,
+		`body contains "sku"`:                            true,
+		`.order.status == "FAILED" and receiveCount > 9`: false,
+		`.order.status == "FAILED" or receiveCount > 9`:  true,
+		`not (.order.status == "OK") && attr.tenant`:     true,
+		`!.order.missing`:                                true,
+		`.order`:                                         true,
+	}
+
+	for input, want := range tests {
+		expr, err := Compile(input)
+		if err != nil {
+			t.Errorf("Compile(%q) returned error: %v", input, err)
+			continue
+		}
+		if got := expr.Match(testSubject()); got != want {
+			t.Errorf("Match(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestCompileSyntaxErrors(t *testing.T) {
+	tests := []string{
+		`.order.status ==`,
+		`.order.status == "FAILED`,
+		`(receiveCount > 3`,
+		`receiveCount > 3 )`,
+		`unknownField == 1`,
+		`age > 1x`,
+		`.items[x] == 1`,
+		`sys.SenderId =~ /[/`,
+		`receiveCount $ 3`,
+	}
+
+	for _, input := range tests {
+		_, err := Compile(input)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Compile(%q) expected a syntax error, got %v", input, err)
+		}
+	}
+}
+
+func TestCompileFilter(t *testing.T) {
+	tests := []struct {
+		input      string
+		expression bool
+		err        bool
+	}{
+		{"FAILED", false, false},
+		{"order 123", false, false},
+		{"receiveCount > 3", true, false},
+		{`attr.tenant == "acme"`, true, false},
+		{`body contains "x"`, true, false},
+		{"(age > 1h)", true, false},
+		{`.order.status ==`, false, true},
+		{"<order>", false, false},
+		{"a->b", false, false},
+		{`"FAILED" == body`, true, false},
+	}
+
+	for _, tt := range tests {
+		expr, err := CompileFilter(tt.input)
+		if (expr != nil) != tt.expression || (err != nil) != tt.err {
+			t.Errorf("CompileFilter(%q) = %v, %v, want expression %v, error %v", tt.input, expr, err, tt.expression, tt.err)
+		}
+	}
+}
+
+func TestMatchNonJSONBody(t *testing.T) {
+	s := NewMessageSubject(kue.Message{Body: "plain"}, "plain", testNow)
+
+	expr, err := Compile(`.order == "x"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expr.Match(s) {
+		t.Error("Expected body path not to match a non-JSON body")
+	}
+}
@@ -0,0 +1,150 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenType int
+
+const (
+	tokEOF tokenType = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokDuration
+	tokRegex
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+// token is a lexical token with its position in the expression.
+type token struct {
+	typ tokenType
+	val string
+	pos int
+}
+
+// operators ordered so that longer operators are matched first.
+var operators = []string{"==", "!=", ">=", "<=", "=~", "&&", "||", ">", "<", "!"}
+
+// lex splits an expression into tokens.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{typ: tokLParen, val: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{typ: tokRParen, val: ")", pos: i})
+			i++
+		case c == '"' || c == '\'':
+			val, end, err := lexString(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{typ: tokString, val: val, pos: i})
+			i = end
+		case c == '/':
+			val, end, err := lexRegex(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{typ: tokRegex, val: val, pos: i})
+			i = end
+		case c >= '0' && c <= '9' || (c == '-' && i+1 < len(input) && isDigit(input[i+1])):
+			start := i
+			i++
+			for i < len(input) && (isDigit(input[i]) || input[i] == '.') {
+				i++
+			}
+			typ := tokNumber
+			for i < len(input) && unicode.IsLetter(rune(input[i])) {
+				typ = tokDuration
+				i++
+			}
+			// Compound durations such as 1h30m
+			for typ == tokDuration && i < len(input) && isDigit(input[i]) {
+				for i < len(input) && (isDigit(input[i]) || unicode.IsLetter(rune(input[i]))) {
+					i++
+				}
+			}
+			tokens = append(tokens, token{typ: typ, val: input[start:i], pos: start})
+		case isIdentStart(c):
+			start := i
+			for i < len(input) && isIdentPart(input[i]) {
+				i++
+			}
+			tokens = append(tokens, token{typ: tokIdent, val: input[start:i], pos: start})
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(input[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, &SyntaxError{Pos: i, Msg: "unexpected character " + string(c)}
+			}
+			tokens = append(tokens, token{typ: tokOp, val: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{typ: tokEOF, pos: len(input)}), nil
+}
+
+// lexString reads a quoted string with backslash escapes.
+func lexString(input string, start int) (string, int, error) {
+	quote := input[start]
+	var b strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case c == '\\' && i+1 < len(input):
+			i++
+			b.WriteByte(input[i])
+		case c == quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, &SyntaxError{Pos: start, Msg: "unterminated string"}
+}
+
+// lexRegex reads a /pattern/ literal.
+func lexRegex(input string, start int) (string, int, error) {
+	var b strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case c == '\\' && i+1 < len(input) && input[i+1] == '/':
+			i++
+			b.WriteByte('/')
+		case c == '/':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, &SyntaxError{Pos: start, Msg: "unterminated regular expression"}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '.' || c == '_' || unicode.IsLetter(rune(c))
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '-' || c == '[' || c == ']'
+}
@@ -0,0 +1,105 @@
+package filter
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+// MessageSubject evaluates expressions against an SQS message.
+type MessageSubject struct {
+	Message kue.Message
+	Body    string    // decoded body used for body paths
+	Now     time.Time // reference time for the age field
+
+	parsed   bool
+	document interface{}
+}
+
+// NewMessageSubject creates a subject for msg with its decoded body.
+func NewMessageSubject(msg kue.Message, body string, now time.Time) *MessageSubject {
+	return &MessageSubject{Message: msg, Body: body, Now: now}
+}
+
+// Lookup resolves a reference against the message.
+func (m *MessageSubject) Lookup(ref Ref) (interface{}, bool) {
+	switch ref.Kind {
+	case BodyPath:
+		return m.lookupBody(ref.Path)
+	case MessageAttr:
+		v, ok := m.Message.MessageAttributes[ref.Name]
+		return v, ok
+	case SystemAttr:
+		v, ok := m.Message.Attributes[ref.Name]
+		return v, ok
+	}
+
+	switch ref.Name {
+	case "id":
+		return m.Message.MessageID, true
+	case "body":
+		return m.Body, true
+	case "size":
+		return float64(len(m.Message.Body)), true
+	case "group":
+		return m.Message.MessageGroupID, m.Message.MessageGroupID != ""
+	case "dedup":
+		return m.Message.MessageDeduplicationID, m.Message.MessageDeduplicationID != ""
+	case "receiveCount":
+		n, err := strconv.ParseFloat(m.Message.ReceiveCount, 64)
+		return n, err == nil
+	case "age":
+		sent, ok := m.sentTime()
+		if !ok {
+			return nil, false
+		}
+		return m.Now.Sub(sent), true
+	}
+	return nil, false
+}
+
+// sentTime returns when the message was sent, preferring the millisecond
+// precision system attribute.
+func (m *MessageSubject) sentTime() (time.Time, bool) {
+	if ms, err := strconv.ParseInt(m.Message.Attributes["SentTimestamp"], 10, 64); err == nil {
+		return time.UnixMilli(ms), true
+	}
+	t, err := time.Parse(time.RFC3339, m.Message.SentTimestamp)
+	return t, err == nil
+}
+
+// lookupBody walks a path through the body parsed as JSON.
+func (m *MessageSubject) lookupBody(path []interface{}) (interface{}, bool) {
+	if !m.parsed {
+		m.parsed = true
+		if err := json.Unmarshal([]byte(m.Body), &m.document); err != nil {
+			m.document = nil
+		}
+	}
+	if m.document == nil {
+		return nil, false
+	}
+
+	current := m.document
+	for _, step := range path {
+		switch step := step.(type) {
+		case string:
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = obj[step]; !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := current.([]interface{})
+			if !ok || step < 0 || step >= len(arr) {
+				return nil, false
+			}
+			current = arr[step]
+		}
+	}
+	return current, true
+}
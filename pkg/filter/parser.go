@@ -0,0 +1,168 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// parser is a recursive descent parser for the grammar:
+//
+//	or      = and { ("or" | "||") and }
+//	and     = unary { ("and" | "&&") unary }
+//	unary   = ("not" | "!") unary | primary
+//	primary = "(" or ")" | operand [ compare operand ]
+//	compare = "==" | "!=" | ">" | ">=" | "<" | "<=" | "=~" | "contains"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// isKeyword reports whether t is one of the given operators or keywords.
+func isKeyword(t token, words ...string) bool {
+	if t.typ != tokOp && t.typ != tokIdent {
+		return false
+	}
+	for _, w := range words {
+		if t.val == w {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "or", "||", "OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "and", "&&", "AND") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if isKeyword(p.peek(), "not", "!", "NOT") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.peek().typ == tokLParen {
+		open := p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().typ != tokRParen {
+			return nil, &SyntaxError{Pos: open.pos, Msg: "missing closing parenthesis"}
+		}
+		p.next()
+		return inner, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.peek()
+	if !isKeyword(op, "==", "!=", ">", ">=", "<", "<=", "=~", "contains") {
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	cmp := compareNode{op: op.val, left: left, right: right}
+	if op.val == "=~" {
+		lit, ok := right.(literalNode)
+		pattern, isString := lit.value.(string)
+		if !ok || !isString {
+			return nil, &SyntaxError{Pos: op.pos, Msg: "=~ requires a string or /regex/ pattern"}
+		}
+		if cmp.regex, err = compileRegex(pattern, op.pos); err != nil {
+			return nil, err
+		}
+	}
+	return cmp, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	t := p.next()
+	switch t.typ {
+	case tokString, tokRegex:
+		return literalNode{value: t.val}, nil
+	case tokNumber:
+		n, err := strconv.ParseFloat(t.val, 64)
+		if err != nil {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("invalid number %q", t.val)}
+		}
+		return literalNode{value: n}, nil
+	case tokDuration:
+		d, err := parseDuration(t.val)
+		if err != nil {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("invalid duration %q", t.val)}
+		}
+		return literalNode{value: d}, nil
+	case tokIdent:
+		switch t.val {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null":
+			return literalNode{value: nil}, nil
+		}
+		ref, err := parseRef(t)
+		if err != nil {
+			return nil, err
+		}
+		return refNode{ref: ref}, nil
+	case tokEOF:
+		return nil, &SyntaxError{Pos: t.pos, Msg: "unexpected end of expression"}
+	}
+	return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.val)}
+}
@@ -0,0 +1,276 @@
+package filter
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+// QueueSubject is a queue with the information a queue query needs beyond
+// its own attributes.
+type QueueSubject struct {
+	Queue           kue.Queue
+	DeadLetterDepth int  // available messages in the queue's DLQ, -1 if unknown
+	IsDeadLetter    bool // another queue uses this queue as its DLQ
+}
+
+// QueueQuery is a compiled queue overview query, e.g.
+// `tag:team=payments dlq:nonempty OR /^billing-/`.
+// Terms separated by whitespace or AND must all match; OR separates
+// alternatives.
+type QueueQuery struct {
+	alternatives [][]queueTerm
+}
+
+type queueTerm struct {
+	negate bool
+	match  func(s QueueSubject) bool
+}
+
+// Match reports whether the queue satisfies the query.
+func (q *QueueQuery) Match(s QueueSubject) bool {
+	for _, terms := range q.alternatives {
+		matched := true
+		for _, t := range terms {
+			if t.match(s) == t.negate {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// queueNumbers are the numeric fields usable in predicates such as visible>100.
+var queueNumbers = map[string]func(s QueueSubject) (int, bool){
+	"visible":  func(s QueueSubject) (int, bool) { return atoi(s.Queue.ApproximateNumberOfMessages) },
+	"inflight": func(s QueueSubject) (int, bool) { return atoi(s.Queue.ApproximateNumberOfMessagesNotVisible) },
+	"delayed":  func(s QueueSubject) (int, bool) { return atoi(s.Queue.ApproximateNumberOfMessagesDelayed) },
+	"dlq":      func(s QueueSubject) (int, bool) { return s.DeadLetterDepth, s.DeadLetterDepth >= 0 },
+}
+
+// predicatePattern matches numeric predicates such as visible>100 or dlq!=0.
+var predicatePattern = regexp.MustCompile(`^([a-z]+)(>=|<=|!=|==|=|>|<)(-?\d+)$`)
+
+// CompileQueueQuery parses a queue overview query.
+func CompileQueueQuery(input string) (*QueueQuery, error) {
+	words, err := splitQueryWords(input)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &QueueQuery{}
+	var terms []queueTerm
+	for _, w := range words {
+		switch strings.ToUpper(w.val) {
+		case "AND", "&&":
+			continue
+		case "OR", "||":
+			if len(terms) == 0 {
+				return nil, &SyntaxError{Pos: w.pos, Msg: "OR needs a term on both sides"}
+			}
+			query.alternatives = append(query.alternatives, terms)
+			terms = nil
+			continue
+		}
+
+		t, err := parseQueueTerm(w)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+	}
+	if len(terms) == 0 && len(query.alternatives) > 0 {
+		return nil, &SyntaxError{Pos: len(input), Msg: "OR needs a term on both sides"}
+	}
+	query.alternatives = append(query.alternatives, terms)
+
+	return query, nil
+}
+
+// splitQueryWords splits the query on whitespace, keeping /regex/ literals
+// that contain spaces together.
+func splitQueryWords(input string) ([]token, error) {
+	var words []token
+	i := 0
+	for i < len(input) {
+		if input[i] == ' ' || input[i] == '\t' {
+			i++
+			continue
+		}
+
+		start := i
+		if input[i] == '/' || strings.HasPrefix(input[i:], "-/") || strings.HasPrefix(input[i:], "!/") {
+			open := strings.IndexByte(input[i:], '/') + i
+			end := strings.IndexByte(input[open+1:], '/')
+			if end < 0 {
+				return nil, &SyntaxError{Pos: start, Msg: "unterminated regular expression"}
+			}
+			i = open + 1 + end + 1
+		} else {
+			for i < len(input) && input[i] != ' ' && input[i] != '\t' {
+				i++
+			}
+		}
+		words = append(words, token{val: input[start:i], pos: start})
+	}
+	return words, nil
+}
+
+// parseQueueTerm parses a single query term.
+func parseQueueTerm(w token) (queueTerm, error) {
+	t := queueTerm{}
+	text := w.val
+	if len(text) > 1 && (text[0] == '-' || text[0] == '!') {
+		t.negate = true
+		text = text[1:]
+	}
+	lower := strings.ToLower(text)
+
+	switch {
+	case strings.HasPrefix(text, "/"):
+		pattern := strings.TrimSuffix(strings.TrimPrefix(text, "/"), "/")
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return t, &SyntaxError{Pos: w.pos, Msg: "invalid regular expression"}
+		}
+		t.match = func(s QueueSubject) bool { return re.MatchString(s.Queue.Name) }
+
+	case lower == "fifo":
+		t.match = func(s QueueSubject) bool { return s.Queue.FifoQueue == "true" }
+	case lower == "standard":
+		t.match = func(s QueueSubject) bool { return s.Queue.FifoQueue != "true" }
+
+	case predicatePattern.MatchString(lower):
+		parts := predicatePattern.FindStringSubmatch(lower)
+		value, ok := queueNumbers[parts[1]]
+		if !ok {
+			return t, &SyntaxError{Pos: w.pos, Msg: fmt.Sprintf("unknown field %q", parts[1])}
+		}
+		op := parts[2]
+		n, _ := strconv.Atoi(parts[3])
+		t.match = func(s QueueSubject) bool {
+			v, ok := value(s)
+			return ok && compareInts(v, op, n)
+		}
+
+	case strings.Contains(text, ":"):
+		key, arg, _ := strings.Cut(text, ":")
+		match, err := parseQualifier(strings.ToLower(key), arg, w.pos)
+		if err != nil {
+			return t, err
+		}
+		t.match = match
+
+	default:
+		t.match = func(s QueueSubject) bool { return strings.Contains(strings.ToLower(s.Queue.Name), lower) }
+	}
+	return t, nil
+}
+
+// parseQualifier parses key:arg terms such as tag:team=payments or has:dlq.
+func parseQualifier(key, arg string, pos int) (func(s QueueSubject) bool, error) {
+	switch key {
+	case "tag":
+		name, pattern, hasValue := strings.Cut(arg, "=")
+		if name == "" {
+			return nil, &SyntaxError{Pos: pos, Msg: "tag needs a key, e.g. tag:team=payments"}
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, &SyntaxError{Pos: pos, Msg: "invalid tag pattern"}
+		}
+		return func(s QueueSubject) bool {
+			value, ok := s.Queue.Tags[name]
+			if !ok || !hasValue {
+				return ok
+			}
+			matched, _ := path.Match(pattern, value)
+			return matched
+		}, nil
+
+	case "has":
+		switch strings.ToLower(arg) {
+		case "dlq":
+			return func(s QueueSubject) bool { return s.Queue.DeadLetterTargetARN != "" }, nil
+		case "tags":
+			return func(s QueueSubject) bool { return len(s.Queue.Tags) > 0 }, nil
+		case "messages":
+			return func(s QueueSubject) bool { n, _ := atoi(s.Queue.ApproximateNumberOfMessages); return n > 0 }, nil
+		}
+
+	case "is":
+		switch strings.ToLower(arg) {
+		case "dlq":
+			return func(s QueueSubject) bool { return s.IsDeadLetter }, nil
+		case "fifo":
+			return func(s QueueSubject) bool { return s.Queue.FifoQueue == "true" }, nil
+		case "empty":
+			return func(s QueueSubject) bool { n, _ := atoi(s.Queue.ApproximateNumberOfMessages); return n == 0 }, nil
+		}
+
+	case "dlq":
+		switch strings.ToLower(arg) {
+		case "nonempty":
+			return func(s QueueSubject) bool { return s.DeadLetterDepth > 0 }, nil
+		case "empty":
+			return func(s QueueSubject) bool { return s.DeadLetterDepth == 0 }, nil
+		}
+
+	case "encrypted":
+		want, err := strconv.ParseBool(arg)
+		if err != nil {
+			return nil, &SyntaxError{Pos: pos, Msg: "encrypted must be true or false"}
+		}
+		return func(s QueueSubject) bool {
+			encrypted := s.Queue.KmsMasterKeyId != "" || s.Queue.SqsManagedSseEnabled == "true"
+			return encrypted == want
+		}, nil
+
+	case "context", "ctx":
+		pattern := strings.ToLower(arg)
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("invalid context pattern %q", arg)}
+		}
+		return func(s QueueSubject) bool {
+			ok, _ := path.Match(pattern, strings.ToLower(s.Queue.Context))
+			return ok
+		}, nil
+
+	case "name":
+		lower := strings.ToLower(arg)
+		return func(s QueueSubject) bool { return strings.Contains(strings.ToLower(s.Queue.Name), lower) }, nil
+
+	default:
+		return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("unknown qualifier %q", key)}
+	}
+
+	return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("unknown value %q for %s", arg, key)}
+}
+
+func compareInts(v int, op string, n int) bool {
+	switch op {
+	case ">":
+		return v > n
+	case ">=":
+		return v >= n
+	case "<":
+		return v < n
+	case "<=":
+		return v <= n
+	case "!=":
+		return v != n
+	}
+	return v == n
+}
+
+func atoi(s string) (int, bool) {
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
@@ -0,0 +1,124 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+func testQueueSubjects() map[string]QueueSubject {
+	return map[string]QueueSubject{
+		"payments-orders": {
+			Queue: kue.Queue{
+				Name:                        "payments-orders",
+				ApproximateNumberOfMessages: "150",
+				DeadLetterTargetARN:         "arn:payments-orders-dlq",
+				Tags:                        map[string]string{"team": "payments"},
+				SqsManagedSseEnabled:        "true",
+				Context:                     "prod-eu",
+			},
+			DeadLetterDepth: 12,
+		},
+		"payments-orders-dlq": {
+			Queue: kue.Queue{
+				Name:                        "payments-orders-dlq",
+				ApproximateNumberOfMessages: "12",
+				Tags:                        map[string]string{"team": "payments"},
+				Context:                     "prod-eu",
+			},
+			DeadLetterDepth: -1,
+			IsDeadLetter:    true,
+		},
+		"billing.fifo": {
+			Queue: kue.Queue{
+				Name:                        "billing.fifo",
+				FifoQueue:                   "true",
+				ApproximateNumberOfMessages: "0",
+				DeadLetterTargetARN:         "arn:billing-dlq.fifo",
+				KmsMasterKeyId:              "alias/aws/sqs",
+				Tags:                        map[string]string{"team": "billing"},
+				Context:                     "staging",
+			},
+			DeadLetterDepth: 0,
+		},
+	}
+}
+
+func matchingQueues(t *testing.T, input string) map[string]bool {
+	t.Helper()
+	query, err := CompileQueueQuery(input)
+	if err != nil {
+		t.Fatalf("CompileQueueQuery(%q) returned error: %v", input, err)
+	}
+	matched := make(map[string]bool)
+	for name, s := range testQueueSubjects() {
+		if query.Match(s) {
+			matched[name] = true
+		}
+	}
+	return matched
+}
+
+func TestQueueQuery(t *testing.T) {
+	tests := map[string][]string{
+		"":                                   {"payments-orders", "payments-orders-dlq", "billing.fifo"},
+		"orders":                             {"payments-orders", "payments-orders-dlq"},
+		"ORDERS -dlq":                        {"payments-orders"},
+		"fifo":                               {"billing.fifo"},
+		"standard":                           {"payments-orders", "payments-orders-dlq"},
+		"tag:team=payments":                  {"payments-orders", "payments-orders-dlq"},
+		"tag:team=bill*":                     {"billing.fifo"},
+		"tag:team":                           {"payments-orders", "payments-orders-dlq", "billing.fifo"},
+		"has:dlq":                            {"payments-orders", "billing.fifo"},
+		"is:dlq":                             {"payments-orders-dlq"},
+		"dlq:nonempty":                       {"payments-orders"},
+		"dlq:empty":                          {"billing.fifo"},
+		"visible>100":                        {"payments-orders"},
+		"visible<=12":                        {"payments-orders-dlq", "billing.fifo"},
+		"dlq>=12":                            {"payments-orders"},
+		"encrypted:false":                    {"payments-orders-dlq"},
+		"/^billing/":                         {"billing.fifo"},
+		"!/orders$/":                         {"payments-orders-dlq", "billing.fifo"},
+		"tag:team=payments AND dlq:nonempty": {"payments-orders"},
+		"fifo OR is:dlq":                     {"payments-orders-dlq", "billing.fifo"},
+		"fifo or visible>100 encrypted:true": {"payments-orders", "billing.fifo"},
+		"context:staging":                    {"billing.fifo"},
+		"ctx:prod-* -is:dlq":                 {"payments-orders"},
+	}
+
+	for input, want := range tests {
+		got := matchingQueues(t, input)
+		if len(got) != len(want) {
+			t.Errorf("Query %q: expected %v, got %v", input, want, got)
+			continue
+		}
+		for _, name := range want {
+			if !got[name] {
+				t.Errorf("Query %q: expected %s to match", input, name)
+			}
+		}
+	}
+}
+
+func TestQueueQuerySyntaxErrors(t *testing.T) {
+	tests := []string{
+		"unknown:x",
+		"has:nothing",
+		"encrypted:maybe",
+		"size>10",
+		"/[/",
+		"/unterminated",
+		"OR fifo",
+		"fifo OR",
+		"tag:=x",
+	}
+
+	for _, input := range tests {
+		_, err := CompileQueueQuery(input)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("CompileQueueQuery(%q) expected a syntax error, got %v", input, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Package history keeps a rolling in-memory time series of queue depths.
+package history
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+// DefaultCapacity is the number of samples kept per queue, an hour at the
+// default refresh interval.
+const DefaultCapacity = 120
+
+// minSampleInterval is the minimum time between samples; a sample recorded
+// sooner replaces the previous one.
+const minSampleInterval = time.Second
+
+// Sample is the depth of a queue at a point in time.
+type Sample struct {
+	Time     time.Time
+	Visible  int
+	InFlight int
+	Delayed  int
+}
+
+// Metric selects a value of a sample.
+type Metric func(s Sample) int
+
+// Metrics of a sample.
+var (
+	Visible  Metric = func(s Sample) int { return s.Visible }
+	InFlight Metric = func(s Sample) int { return s.InFlight }
+	Delayed  Metric = func(s Sample) int { return s.Delayed }
+)
+
+// Store holds a bounded series of samples per queue. It is safe for
+// concurrent use.
+type Store struct {
+	mu       sync.RWMutex
+	capacity int
+	series   map[kue.QueueKey][]Sample
+}
+
+// NewStore creates a store that keeps up to capacity samples per queue.
+func NewStore(capacity int) *Store {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Store{capacity: capacity, series: make(map[kue.QueueKey][]Sample)}
+}
+
+// Record adds a sample for each queue with known depths.
+func (s *Store) Record(t time.Time, queues ...kue.Queue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, q := range queues {
+		sample, ok := sampleOf(t, q)
+		if !ok {
+			continue
+		}
+
+		series := s.series[q.Key()]
+		if n := len(series); n > 0 && sample.Time.Sub(series[n-1].Time) < minSampleInterval {
+			series[n-1] = sample
+			continue
+		}
+
+		series = append(series, sample)
+		if len(series) > s.capacity {
+			series = series[len(series)-s.capacity:]
+		}
+		s.series[q.Key()] = series
+	}
+}
+
+// Samples returns a copy of the samples recorded for a queue, oldest first.
+func (s *Store) Samples(queue kue.QueueKey) []Sample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	series := s.series[queue]
+	samples := make([]Sample, len(series))
+	copy(samples, series)
+	return samples
+}
+
+// sampleOf converts the approximate counts of a queue into a sample.
+func sampleOf(t time.Time, q kue.Queue) (Sample, bool) {
+	visible, err := strconv.Atoi(q.ApproximateNumberOfMessages)
+	if err != nil {
+		return Sample{}, false
+	}
+	inFlight, _ := strconv.Atoi(q.ApproximateNumberOfMessagesNotVisible)
+	delayed, _ := strconv.Atoi(q.ApproximateNumberOfMessagesDelayed)
+	return Sample{Time: t, Visible: visible, InFlight: inFlight, Delayed: delayed}, true
+}
+
+// Values returns the metric of each sample.
+func Values(samples []Sample, metric Metric) []int {
+	values := make([]int, len(samples))
+	for i, s := range samples {
+		values[i] = metric(s)
+	}
+	return values
+}
+
+// Rate returns the change of the metric in messages per minute over the
+// samples within window of the latest sample. It reports false when there
+// are not enough samples.
+func Rate(samples []Sample, metric Metric, window time.Duration) (float64, bool) {
+	if len(samples) < 2 {
+		return 0, false
+	}
+
+	last := samples[len(samples)-1]
+	first := last
+	for i := len(samples) - 2; i >= 0; i-- {
+		if last.Time.Sub(samples[i].Time) > window {
+			break
+		}
+		first = samples[i]
+	}
+
+	elapsed := last.Time.Sub(first.Time).Minutes()
+	if elapsed <= 0 {
+		return 0, false
+	}
+	return float64(metric(last)-metric(first)) / elapsed, true
+}
+
+// TimeToDrain estimates how long it takes until no messages are visible at
+// the current rate. It reports false when the queue is not draining.
+func TimeToDrain(samples []Sample, window time.Duration) (time.Duration, bool) {
+	rate, ok := Rate(samples, Visible, window)
+	if !ok || rate >= 0 {
+		return 0, false
+	}
+	visible := samples[len(samples)-1].Visible
+	minutes := float64(visible) / -rate
+	return time.Duration(minutes * float64(time.Minute)), true
+}
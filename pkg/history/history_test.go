@@ -0,0 +1,102 @@
+package history
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+var start = time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+func queueWithDepth(visible int) kue.Queue {
+	return kue.Queue{
+		Url:                                   "http://test/orders",
+		ApproximateNumberOfMessages:           strconv.Itoa(visible),
+		ApproximateNumberOfMessagesNotVisible: "2",
+		ApproximateNumberOfMessagesDelayed:    "1",
+	}
+}
+
+func TestRecordKeepsCapacity(t *testing.T) {
+	s := NewStore(3)
+	for i := 0; i < 5; i++ {
+		s.Record(start.Add(time.Duration(i)*time.Minute), queueWithDepth(i))
+	}
+
+	samples := s.Samples(kue.QueueKey{Url: "http://test/orders"})
+	if len(samples) != 3 {
+		t.Fatalf("Expected 3 samples, got %d", len(samples))
+	}
+	if got := Values(samples, Visible); got[0] != 2 || got[2] != 4 {
+		t.Errorf("Expected the newest samples to be kept, got %v", got)
+	}
+	if samples[0].InFlight != 2 || samples[0].Delayed != 1 {
+		t.Errorf("Unexpected in-flight or delayed counts: %+v", samples[0])
+	}
+}
+
+func TestRecordReplacesSamplesTooClose(t *testing.T) {
+	s := NewStore(10)
+	s.Record(start, queueWithDepth(1))
+	s.Record(start.Add(100*time.Millisecond), queueWithDepth(5))
+
+	samples := s.Samples(kue.QueueKey{Url: "http://test/orders"})
+	if len(samples) != 1 || samples[0].Visible != 5 {
+		t.Errorf("Expected a single replaced sample, got %+v", samples)
+	}
+}
+
+func TestRecordSkipsUnknownDepth(t *testing.T) {
+	s := NewStore(10)
+	s.Record(start, kue.Queue{Url: "http://test/orders"})
+
+	if samples := s.Samples(kue.QueueKey{Url: "http://test/orders"}); len(samples) != 0 {
+		t.Errorf("Expected no samples, got %+v", samples)
+	}
+}
+
+func TestRecordKeepsContextsApart(t *testing.T) {
+	s := NewStore(10)
+	staging := queueWithDepth(7)
+	staging.Context = "staging"
+	s.Record(start, queueWithDepth(1), staging)
+
+	if samples := s.Samples(kue.QueueKey{Context: "staging", Url: "http://test/orders"}); len(samples) != 1 || samples[0].Visible != 7 {
+		t.Errorf("Expected the samples of the queue in the staging context, got %+v", samples)
+	}
+}
+
+func TestRateAndTimeToDrain(t *testing.T) {
+	samples := []Sample{
+		{Time: start, Visible: 1000},
+		{Time: start.Add(10 * time.Minute), Visible: 900},
+		{Time: start.Add(12 * time.Minute), Visible: 500},
+		{Time: start.Add(14 * time.Minute), Visible: 100},
+	}
+
+	rate, ok := Rate(samples, Visible, 5*time.Minute)
+	if !ok || rate != -200 {
+		t.Errorf("Expected a rate of -200/min, got %v (%v)", rate, ok)
+	}
+
+	drain, ok := TimeToDrain(samples, 5*time.Minute)
+	if !ok || drain != 30*time.Second {
+		t.Errorf("Expected to drain in 30s, got %v (%v)", drain, ok)
+	}
+}
+
+func TestTimeToDrainGrowing(t *testing.T) {
+	samples := []Sample{
+		{Time: start, Visible: 10},
+		{Time: start.Add(time.Minute), Visible: 20},
+	}
+
+	if _, ok := TimeToDrain(samples, 5*time.Minute); ok {
+		t.Error("Expected no drain estimate for a growing queue")
+	}
+	if _, ok := Rate(samples[:1], Visible, 5*time.Minute); ok {
+		t.Error("Expected no rate for a single sample")
+	}
+}
@@ -17,8 +17,21 @@ type KeyMap struct {
 	Delete        key.Binding
 	DeleteMessage   key.Binding
 	CopyToClipboard key.Binding
+	ToggleRaw       key.Binding
+	NextMatch       key.Binding
+	PrevMatch       key.Binding
+	LineNumbers     key.Binding
+	Wrap            key.Binding
+	Collapse        key.Binding
+	Sort            key.Binding
+	SortOrder       key.Binding
+	Trend           key.Binding
+	Topology        key.Binding
+	Profile         key.Binding
 	Purge           key.Binding
 	Redrive         key.Binding
+	AuditLog        key.Binding
+	Trash           key.Binding
 	Quit            key.Binding
 }
 
@@ -47,8 +60,21 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 			k.Delete,
 			k.DeleteMessage,
 			k.CopyToClipboard,
+			k.ToggleRaw,
+			k.NextMatch,
+			k.PrevMatch,
+			k.LineNumbers,
+			k.Wrap,
+			k.Collapse,
+			k.Sort,
+			k.SortOrder,
+			k.Trend,
+			k.Topology,
+			k.Profile,
 			k.Purge,
 			k.Redrive,
+			k.AuditLog,
+			k.Trash,
 			k.Quit,
 		},
 	}
@@ -57,19 +83,19 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 var Keys = KeyMap{
 	Up: key.NewBinding(
 		key.WithKeys("up", "k"),
-		key.WithHelp("↑ | k", "up"),
+		key.WithHelp("↑/k", "move up"),
 	),
 	Down: key.NewBinding(
 		key.WithKeys("down", "j"),
-		key.WithHelp("↓ | j", "down"),
+		key.WithHelp("↓/j", "move down"),
 	),
 	Left: key.NewBinding(
 		key.WithKeys("left", "h"),
-		key.WithHelp("← | h", "left"),
+		key.WithHelp("←/h", "move left"),
 	),
 	Right: key.NewBinding(
 		key.WithKeys("right", "l"),
-		key.WithHelp("→ | l", "right"),
+		key.WithHelp("→/l", "move right"),
 	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
@@ -103,6 +129,50 @@ var Keys = KeyMap{
 		key.WithKeys("c"),
 		key.WithHelp("c", "copy"),
 	),
+	ToggleRaw: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "raw body"),
+	),
+	NextMatch: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next match"),
+	),
+	PrevMatch: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous match"),
+	),
+	LineNumbers: key.NewBinding(
+		key.WithKeys("#"),
+		key.WithHelp("#", "line numbers"),
+	),
+	Wrap: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "wrap"),
+	),
+	Collapse: key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("z", "collapse"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sort"),
+	),
+	SortOrder: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "sort order"),
+	),
+	Trend: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "trend"),
+	),
+	Topology: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "dead-letter topology"),
+	),
+	Profile: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "profile/region"),
+	),
 	Purge: key.NewBinding(
 		key.WithKeys("ctrl+p"),
 		key.WithHelp("ctrl+p", "purge"),
@@ -111,8 +181,25 @@ var Keys = KeyMap{
 		key.WithKeys("ctrl+r"),
 		key.WithHelp("ctrl+r", "redrive"),
 	),
+	AuditLog: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "audit log"),
+	),
+	Trash: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "trash"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "esc", "ctrl+c"),
-		key.WithHelp("q", "quit"),
+		key.WithHelp("q/esc", "back/quit"),
 	),
 }
+
+// ReadOnly returns the keybindings with the bindings that create, delete,
+// purge, redrive or send disabled, which also hides them from help.
+func (k KeyMap) ReadOnly(readOnly bool) KeyMap {
+	for _, b := range []*key.Binding{&k.Create, &k.Delete, &k.DeleteMessage, &k.Purge, &k.Redrive} {
+		b.SetEnabled(!readOnly)
+	}
+	return k
+}
@@ -0,0 +1,165 @@
+package keys
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// Names of the bindings as used in the configuration file.
+var names = []string{
+	"up", "down", "left", "right", "help", "view", "select", "filter",
+	"create", "delete", "delete_message", "copy", "toggle_raw", "next_match",
+	"prev_match", "line_numbers", "wrap", "collapse", "sort", "sort_order",
+	"trend", "topology", "profile", "purge", "redrive", "audit", "trash", "quit",
+}
+
+// Pages lists the bindings each page handles. Bindings of the same page must
+// not share a key, help works on every page.
+var Pages = map[string][]string{
+	"queue overview":  {"help", "up", "down", "select", "filter", "sort", "sort_order", "view", "create", "topology", "profile", "purge", "redrive", "audit", "trash", "delete", "quit"},
+	"queue details":   {"help", "up", "down", "filter", "select", "trend", "view", "delete_message", "copy", "purge", "redrive", "create", "quit"},
+	"message details": {"help", "copy", "toggle_raw", "delete_message", "filter", "next_match", "prev_match", "line_numbers", "wrap", "collapse", "quit"},
+	"confirmation":    {"help", "left", "right", "view", "quit"},
+	"queue topology":  {"help", "up", "down", "left", "right", "view", "quit"},
+	"message create":  {"help", "view", "quit"},
+	"credentials":     {"help", "view", "quit"},
+	"audit log":       {"help", "up", "down", "quit"},
+	"trash":           {"help", "up", "down", "left", "right", "view", "purge", "quit"},
+}
+
+// binding returns the binding with the given configuration name.
+func (k *KeyMap) binding(name string) *key.Binding {
+	switch name {
+	case "up":
+		return &k.Up
+	case "down":
+		return &k.Down
+	case "left":
+		return &k.Left
+	case "right":
+		return &k.Right
+	case "help":
+		return &k.Help
+	case "view":
+		return &k.View
+	case "select":
+		return &k.Select
+	case "filter":
+		return &k.Filter
+	case "create":
+		return &k.Create
+	case "delete":
+		return &k.Delete
+	case "delete_message":
+		return &k.DeleteMessage
+	case "copy":
+		return &k.CopyToClipboard
+	case "toggle_raw":
+		return &k.ToggleRaw
+	case "next_match":
+		return &k.NextMatch
+	case "prev_match":
+		return &k.PrevMatch
+	case "line_numbers":
+		return &k.LineNumbers
+	case "wrap":
+		return &k.Wrap
+	case "collapse":
+		return &k.Collapse
+	case "sort":
+		return &k.Sort
+	case "sort_order":
+		return &k.SortOrder
+	case "trend":
+		return &k.Trend
+	case "topology":
+		return &k.Topology
+	case "profile":
+		return &k.Profile
+	case "purge":
+		return &k.Purge
+	case "redrive":
+		return &k.Redrive
+	case "audit":
+		return &k.AuditLog
+	case "trash":
+		return &k.Trash
+	case "quit":
+		return &k.Quit
+	}
+	return nil
+}
+
+// New returns the default keybindings with the keys of the given bindings
+// replaced, keyed by binding name. It fails for unknown bindings and for
+// keys bound twice on a page.
+func New(remaps map[string][]string) (KeyMap, error) {
+	k := Keys
+
+	// Apply in name order so errors are reported consistently
+	remapped := make([]string, 0, len(remaps))
+	for name := range remaps {
+		remapped = append(remapped, name)
+	}
+	sort.Strings(remapped)
+
+	for _, name := range remapped {
+		b := k.binding(name)
+		if b == nil {
+			return k, fmt.Errorf("unknown binding %q, expected one of %s", name, strings.Join(names, ", "))
+		}
+		remap := remaps[name]
+		if len(remap) == 0 {
+			return k, fmt.Errorf("binding %q has no keys", name)
+		}
+		*b = key.NewBinding(key.WithKeys(remap...), key.WithHelp(HelpKey(remap), b.Help().Desc))
+	}
+
+	return k, k.Validate()
+}
+
+// Validate reports keys bound to more than one binding of the same page.
+func (k KeyMap) Validate() error {
+	pages := make([]string, 0, len(Pages))
+	for page := range Pages {
+		pages = append(pages, page)
+	}
+	sort.Strings(pages)
+
+	for _, page := range pages {
+		bound := make(map[string]string)
+		for _, name := range Pages[page] {
+			for _, keyName := range k.binding(name).Keys() {
+				if other, ok := bound[keyName]; ok {
+					return fmt.Errorf("%s is bound to both %s and %s on the %s page", keyName, other, name, page)
+				}
+				bound[keyName] = name
+			}
+		}
+	}
+	return nil
+}
+
+// keySymbols are shown in help instead of the key names.
+var keySymbols = map[string]string{
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+	" ":     "space",
+}
+
+// HelpKey formats keys for the help, e.g. "↑/k".
+func HelpKey(keys []string) string {
+	shown := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if symbol, ok := keySymbols[k]; ok {
+			k = symbol
+		}
+		shown = append(shown, k)
+	}
+	return strings.Join(shown, "/")
+}
@@ -0,0 +1,83 @@
+package keys
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultKeysAreValid(t *testing.T) {
+	if err := Keys.Validate(); err != nil {
+		t.Fatalf("Expected the default bindings to be valid, got %v", err)
+	}
+	for _, name := range names {
+		if Keys.binding(name) == nil {
+			t.Errorf("Binding %q has no field", name)
+		}
+	}
+}
+
+func TestNewRemapsBindings(t *testing.T) {
+	k, err := New(map[string][]string{
+		"purge":   {"ctrl+x"},
+		"redrive": {"R"},
+		"up":      {"up", "i"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := k.Purge.Keys(); len(got) != 1 || got[0] != "ctrl+x" {
+		t.Errorf("Expected purge on ctrl+x, got %v", got)
+	}
+	if got := k.Up.Help(); got.Key != "↑/i" || got.Desc != "move up" {
+		t.Errorf("Expected the help to show the new keys, got %+v", got)
+	}
+	if got := Keys.Purge.Keys(); got[0] != "ctrl+p" {
+		t.Errorf("Expected the defaults to be unchanged, got %v", got)
+	}
+}
+
+func TestNewRejectsInvalidBindings(t *testing.T) {
+	tests := map[string]map[string][]string{
+		"unknown binding":        {"explode": {"x"}},
+		"has no keys":            {"purge": {}},
+		"bound to both":          {"purge": {"ctrl+d"}},
+		"on the queue details":   {"trend": {"c"}},
+		"on the message details": {"wrap": {"r"}},
+		"on the confirmation":    {"left": {"enter"}},
+	}
+	for want, remaps := range tests {
+		if _, err := New(remaps); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("New(%v): expected an error containing %q, got %v", remaps, want, err)
+		}
+	}
+
+	// Bindings of different pages may share keys
+	if _, err := New(map[string][]string{"delete": {"x"}, "delete_message": {"x"}}); err != nil {
+		t.Errorf("Expected keys to be shared across pages, got %v", err)
+	}
+}
+
+func TestReadOnlyDisablesMutatingBindings(t *testing.T) {
+	k := Keys.ReadOnly(true)
+	for name, b := range map[string]bool{
+		"create":         k.Create.Enabled(),
+		"delete":         k.Delete.Enabled(),
+		"delete_message": k.DeleteMessage.Enabled(),
+		"purge":          k.Purge.Enabled(),
+		"redrive":        k.Redrive.Enabled(),
+	} {
+		if b {
+			t.Errorf("Expected %s to be disabled", name)
+		}
+	}
+	if !k.View.Enabled() || !k.Filter.Enabled() {
+		t.Error("Expected reading bindings to stay enabled")
+	}
+	if !Keys.Purge.Enabled() {
+		t.Error("Expected the defaults to be unchanged")
+	}
+	if !k.ReadOnly(false).Purge.Enabled() {
+		t.Error("Expected the bindings to be enabled again")
+	}
+}
@@ -2,7 +2,7 @@ package kue
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -76,11 +76,11 @@ func CreateQueue(client *sqs.Client, ctx context.Context, config QueueConfig) (*
 
 	result, err := client.CreateQueue(ctx, input)
 	if err != nil {
-		log.Printf("[CreateQueue] Error creating queue %s: %v", queueName, err)
+		slog.Error("failed to create queue", "queue", queueName, "err", err)
 		return nil, err
 	}
 
-	log.Printf("[CreateQueue] Created queue: %s, URL: %s", queueName, *result.QueueUrl)
+	slog.Info("created queue", "queue", queueName, "url", aws.ToString(result.QueueUrl))
 
 	return result.QueueUrl, nil
 }
@@ -2,7 +2,7 @@ package kue
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
@@ -25,7 +25,7 @@ func DeleteQueue(client *sqs.Client, ctx context.Context, queueName string) erro
 		return err
 	}
 
-	log.Printf("[DeleteQueue] Deleted queue: %s", queueName)
+	slog.Info("deleted queue", "queue", queueName)
 
 	return nil
 }
@@ -0,0 +1,47 @@
+package kue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// FetchOldestMessageAge returns the latest ApproximateAgeOfOldestMessage
+// CloudWatch metric of a queue. It reports false when no datapoint was
+// published in the last 15 minutes.
+func FetchOldestMessageAge(client *cloudwatch.Client, ctx context.Context, queueName string) (time.Duration, bool, error) {
+	now := time.Now()
+	output, err := client.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/SQS"),
+		MetricName: aws.String("ApproximateAgeOfOldestMessage"),
+		Dimensions: []types.Dimension{
+			{Name: aws.String("QueueName"), Value: aws.String(queueName)},
+		},
+		StartTime:  aws.Time(now.Add(-15 * time.Minute)),
+		EndTime:    aws.Time(now),
+		Period:     aws.Int32(60),
+		Statistics: []types.Statistic{types.StatisticMaximum},
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get oldest message age: %w", err)
+	}
+
+	var latest *types.Datapoint
+	for i, dp := range output.Datapoints {
+		if dp.Timestamp == nil || dp.Maximum == nil {
+			continue
+		}
+		if latest == nil || dp.Timestamp.After(*latest.Timestamp) {
+			latest = &output.Datapoints[i]
+		}
+	}
+	if latest == nil {
+		return 0, false, nil
+	}
+
+	return time.Duration(*latest.Maximum * float64(time.Second)), true, nil
+}
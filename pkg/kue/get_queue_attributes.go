@@ -40,6 +40,8 @@ func FetchQueueAttributes(client *sqs.Client, ctx context.Context, queueUrl stri
 		types.QueueAttributeNameApproximateNumberOfMessages:           &queue.ApproximateNumberOfMessages,
 		types.QueueAttributeNameApproximateNumberOfMessagesNotVisible: &queue.ApproximateNumberOfMessagesNotVisible,
 		types.QueueAttributeNameApproximateNumberOfMessagesDelayed:    &queue.ApproximateNumberOfMessagesDelayed,
+		types.QueueAttributeNameKmsMasterKeyId:                        &queue.KmsMasterKeyId,
+		types.QueueAttributeNameSqsManagedSseEnabled:                  &queue.SqsManagedSseEnabled,
 	}
 
 	if val, ok := attrsResult.Attributes[string(types.QueueAttributeNameCreatedTimestamp)]; ok {
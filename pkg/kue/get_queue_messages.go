@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
@@ -77,10 +78,16 @@ func FetchQueueMessages(client *sqs.Client, ctx context.Context, queueUrl string
 		// Handle message attributes
 		if msg.MessageAttributes != nil {
 			message.MessageAttributes = make(map[string]string)
+			message.MessageAttributeValues = make(map[string]MessageAttributeValue)
 			for key, attr := range msg.MessageAttributes {
 				if attr.StringValue != nil {
 					message.MessageAttributes[key] = *attr.StringValue
 				}
+				message.MessageAttributeValues[key] = MessageAttributeValue{
+					DataType:    aws.ToString(attr.DataType),
+					StringValue: aws.ToString(attr.StringValue),
+					BinaryValue: attr.BinaryValue,
+				}
 			}
 		}
 
@@ -0,0 +1,27 @@
+package kue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// ListDeadLetterSourceQueues lists the URLs of the queues that use the queue
+// as their dead-letter queue.
+func ListDeadLetterSourceQueues(client *sqs.Client, ctx context.Context, queueUrl string) ([]string, error) {
+	var sourceUrls []string
+
+	paginator := sqs.NewListDeadLetterSourceQueuesPaginator(client, &sqs.ListDeadLetterSourceQueuesInput{
+		QueueUrl: &queueUrl,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list dead-letter source queues: %w", err)
+		}
+		sourceUrls = append(sourceUrls, output.QueueUrls...)
+	}
+
+	return sourceUrls, nil
+}
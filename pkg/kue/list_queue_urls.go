@@ -3,12 +3,13 @@ package kue
 import (
 	"context"
 	"fmt"
-	"log"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 )
 
-// ListQueuesUrls lists the SQS queues
+// ListQueuesUrls lists the SQS queues. An account without queues returns no
+// queues and no error.
 func ListQueuesUrls(client *sqs.Client, ctx context.Context) (queues []Queue, err error) {
 
 	var queueUrls []string
@@ -17,19 +18,15 @@ func ListQueuesUrls(client *sqs.Client, ctx context.Context) (queues []Queue, er
 	for paginator.HasMorePages() {
 		output, err := paginator.NextPage(ctx)
 		if err != nil {
-			log.Printf("Couldn't get queues. Here's why: %v\n", err)
-			break
-		} else {
-			queueUrls = append(queueUrls, output.QueueUrls...)
+			return nil, fmt.Errorf("listing queues: %w", err)
 		}
+		queueUrls = append(queueUrls, output.QueueUrls...)
 	}
 
+	queues = []Queue{}
 	for _, queueUrl := range queueUrls {
-		queues = append(queues, Queue{Url: queueUrl})
-	}
-
-	if len(queues) == 0 {
-		return nil, fmt.Errorf("No queues found")
+		urlParts := strings.Split(queueUrl, "/")
+		queues = append(queues, Queue{Url: queueUrl, Name: urlParts[len(urlParts)-1]})
 	}
 
 	return queues, nil
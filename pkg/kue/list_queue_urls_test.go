@@ -0,0 +1,61 @@
+package kue
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/smithy-go"
+)
+
+// newTestClient returns an SQS client sending its requests to handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *sqs.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return sqs.New(sqs.Options{
+		Region:           "us-east-1",
+		BaseEndpoint:     aws.String(server.URL),
+		Credentials:      aws.AnonymousCredentials{},
+		RetryMaxAttempts: 1,
+	})
+}
+
+func TestListQueuesUrlsReturnsNoQueuesForAnEmptyAccount(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Write([]byte(`{"QueueUrls":[]}`))
+	})
+
+	queues, err := ListQueuesUrls(client, context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error for an empty account, got %v", err)
+	}
+	if queues == nil || len(queues) != 0 {
+		t.Errorf("Expected an empty list of queues, got %v", queues)
+	}
+}
+
+func TestListQueuesUrlsReturnsListErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"com.amazon.coral.service#ExpiredTokenException","message":"The security token included in the request is expired"}`))
+	})
+
+	queues, err := ListQueuesUrls(client, context.Background())
+	if err == nil {
+		t.Fatalf("Expected the list error to be returned, got queues %v", queues)
+	}
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ExpiredTokenException" {
+		t.Errorf("Expected the list error to be wrapped, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "listing queues: ") {
+		t.Errorf("Expected the error to name the listing, got %v", err)
+	}
+}
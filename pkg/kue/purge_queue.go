@@ -2,7 +2,7 @@ package kue
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 )
@@ -16,6 +16,6 @@ func PurgeQueue(client *sqs.Client, ctx context.Context, queueUrl string) error
 		return err
 	}
 
-	log.Printf("[PurgeQueue] Purged queue: %s", queueUrl)
+	slog.Info("purged queue", "url", queueUrl)
 	return nil
 }
@@ -3,7 +3,7 @@ package kue
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 )
@@ -37,7 +37,7 @@ func StartMessageMoveTask(client *sqs.Client, ctx context.Context, sourceArn str
 		taskHandle = *result.TaskHandle
 	}
 
-	log.Printf("[StartMessageMoveTask] Started redrive from %s, task handle: %s", sourceArn, taskHandle)
+	slog.Info("started redrive", "source", sourceArn, "destination", destinationArn, "task_handle", taskHandle)
 	return taskHandle, nil
 }
 
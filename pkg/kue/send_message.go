@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
 
 // SendMessageInput contains the parameters for sending a message.
@@ -14,6 +16,7 @@ type SendMessageInput struct {
 	// For FIFO queues
 	MessageGroupId         string
 	MessageDeduplicationId string
+	MessageAttributes      map[string]MessageAttributeValue
 }
 
 // SendMessage sends a message to an SQS queue.
@@ -30,6 +33,18 @@ func SendMessage(client *sqs.Client, ctx context.Context, input SendMessageInput
 	if input.MessageDeduplicationId != "" {
 		sqsInput.MessageDeduplicationId = &input.MessageDeduplicationId
 	}
+	if len(input.MessageAttributes) > 0 {
+		sqsInput.MessageAttributes = make(map[string]types.MessageAttributeValue)
+		for name, value := range input.MessageAttributes {
+			attr := types.MessageAttributeValue{DataType: aws.String(value.DataType)}
+			if value.BinaryValue != nil {
+				attr.BinaryValue = value.BinaryValue
+			} else {
+				attr.StringValue = aws.String(value.StringValue)
+			}
+			sqsInput.MessageAttributes[name] = attr
+		}
+	}
 
 	_, err := client.SendMessage(ctx, sqsInput)
 	if err != nil {
@@ -21,7 +21,22 @@ type Queue struct {
 	FifoQueue                             string            `json:"fifo_queue"`
 	ContentBasedDeduplication             string            `json:"content_based_deduplication,omitempty"`
 	DeduplicationScope                    string            `json:"deduplication_scope,omitempty"`
+	KmsMasterKeyId                        string            `json:"kms_master_key_id,omitempty"`
+	SqsManagedSseEnabled                  string            `json:"sqs_managed_sse_enabled,omitempty"`
 	Tags                                  map[string]string `json:"tags,omitempty"`
+	Context                               string            `json:"context,omitempty"` // named context the queue was loaded from, empty for a single profile
+}
+
+// QueueKey identifies a queue across contexts, the same URL can be loaded
+// from several contexts.
+type QueueKey struct {
+	Context string
+	Url     string
+}
+
+// Key returns the key identifying the queue.
+func (q Queue) Key() QueueKey {
+	return QueueKey{Context: q.Context, Url: q.Url}
 }
 
 type DeadLetterAttributes struct {
@@ -45,4 +60,14 @@ type Message struct {
 	MessageGroupID         string            `json:"message_group_id,omitempty"`
 	MessageDeduplicationID string            `json:"message_deduplication_id,omitempty"`
 	SequenceNumber         string            `json:"sequence_number,omitempty"`
+	// Message attributes with their data types, to send the message again
+	MessageAttributeValues map[string]MessageAttributeValue `json:"-"`
+}
+
+// MessageAttributeValue is a message attribute as SQS stores it. Binary
+// attributes have a BinaryValue, all others a StringValue.
+type MessageAttributeValue struct {
+	DataType    string `json:"data_type"` // String, Number or Binary, with an optional custom suffix
+	StringValue string `json:"string_value,omitempty"`
+	BinaryValue []byte `json:"binary_value,omitempty"`
 }
@@ -0,0 +1,119 @@
+// Package logging sets up the structured log of kue.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	smithylog "github.com/aws/smithy-go/logging"
+)
+
+// LevelTrace is below debug and logs the requests and responses of the AWS
+// SDK.
+const LevelTrace = slog.Level(-8)
+
+// levels are the log levels by name.
+var levels = map[string]slog.Level{
+	"trace": LevelTrace,
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// ParseLevel returns the log level of a name, e.g. debug.
+func ParseLevel(name string) (slog.Level, error) {
+	level, ok := levels[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown log level %q", name)
+	}
+	return level, nil
+}
+
+// New creates a logger writing entries of at least the level to w, as text
+// or as JSON.
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: replaceLevel}
+	switch format {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q", format)
+}
+
+// Open creates a logger appending to a file, creating its directory when
+// needed. No file discards all entries.
+func Open(file, level, format string) (*slog.Logger, io.Closer, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, nil, err
+	}
+	if file == "" {
+		return slog.New(slog.NewTextHandler(io.Discard, nil)), io.NopCloser(nil), nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	// Trace entries may include request details, so the file is private
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	logger, err := New(f, lvl, format)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return logger, f, nil
+}
+
+// replaceLevel names the trace level, which slog would show as DEBUG-4.
+func replaceLevel(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if level, ok := a.Value.Any().(slog.Level); ok && level == LevelTrace {
+			a.Value = slog.StringValue("TRACE")
+		}
+	}
+	return a
+}
+
+// secrets match credentials in logged requests and responses: signing and
+// session token headers, and credentials returned by STS and SSO.
+var secrets = []struct {
+	pattern *regexp.Regexp
+	replace string
+}{
+	{regexp.MustCompile(`(?im)^((?:authorization|x-amz-security-token|x-amz-sso_bearer_token):)[^\r\n]*`), "$1 [REDACTED]"},
+	{regexp.MustCompile(`(?i)(<(SecretAccessKey|SessionToken)>)[^<]*(</)`), "$1[REDACTED]$3"},
+	{regexp.MustCompile(`(?i)("(?:secretAccessKey|sessionToken|accessToken|refreshToken|clientSecret)"\s*:\s*")[^"]*(")`), "$1[REDACTED]$2"},
+}
+
+// Redact replaces credentials in a logged request or response.
+func Redact(s string) string {
+	for _, secret := range secrets {
+		s = secret.pattern.ReplaceAllString(s, secret.replace)
+	}
+	return s
+}
+
+// SDKLogger returns a logger for the AWS SDK. Requests and responses are
+// logged at trace level with their credentials redacted, warnings of the SDK
+// at warn level.
+func SDKLogger(logger *slog.Logger) smithylog.Logger {
+	return smithylog.LoggerFunc(func(classification smithylog.Classification, format string, v ...interface{}) {
+		level := LevelTrace
+		if classification == smithylog.Warn {
+			level = slog.LevelWarn
+		}
+		logger.Log(context.Background(), level, Redact(fmt.Sprintf(format, v...)), "source", "aws-sdk")
+	})
+}
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	smithylog "github.com/aws/smithy-go/logging"
+)
+
+func TestParseLevel(t *testing.T) {
+	if level, err := ParseLevel("TRACE"); err != nil || level != LevelTrace {
+		t.Errorf("Expected the trace level, got %v (%v)", level, err)
+	}
+	if level, err := ParseLevel("warn"); err != nil || level != slog.LevelWarn {
+		t.Errorf("Expected the warn level, got %v (%v)", level, err)
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
+
+func TestNewJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, LevelTrace, "json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	logger.Log(context.Background(), LevelTrace, "request", "operation", "ListQueues")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON entry, got %q: %v", buf.String(), err)
+	}
+	if entry["level"] != "TRACE" || entry["operation"] != "ListQueues" {
+		t.Errorf("Expected a trace entry with its attributes, got %v", entry)
+	}
+
+	if _, err := New(&buf, slog.LevelInfo, "xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state", "kue", "debug.log")
+	logger, f, err := Open(file, "debug", "text")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	logger.Debug("loaded queues", "count", 3)
+	logger.Log(context.Background(), LevelTrace, "not logged")
+	f.Close()
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Expected the log file to be created: %v", err)
+	}
+	if !strings.Contains(string(data), "level=DEBUG msg=\"loaded queues\" count=3") || strings.Contains(string(data), "not logged") {
+		t.Errorf("Expected the debug entry only, got %q", data)
+	}
+	if info, err := os.Stat(file); err == nil && info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected a private log file, got %s", info.Mode().Perm())
+	}
+}
+
+func TestRedact(t *testing.T) {
+	request := "POST / HTTP/1.1\r\nAuthorization: AWS4-HMAC-SHA256 Credential=AKIA/20240101, Signature=abc\r\n" +
+		"X-Amz-Security-Token: FwoGZXIvYXdzE\r\nContent-Type: application/x-amz-json-1.0\r\n\r\n{\"QueueUrl\":\"http://q\"}"
+	got := Redact(request)
+	if strings.Contains(got, "Signature=abc") || strings.Contains(got, "FwoGZXIvYXdzE") {
+		t.Errorf("Expected the signing headers to be redacted, got %q", got)
+	}
+	if !strings.Contains(got, "Authorization: [REDACTED]") || !strings.Contains(got, `{"QueueUrl":"http://q"}`) {
+		t.Errorf("Expected the rest of the request to be kept, got %q", got)
+	}
+
+	sts := "<Credentials><AccessKeyId>ASIA</AccessKeyId><SecretAccessKey>wJalr</SecretAccessKey><SessionToken>IQoJb</SessionToken></Credentials>"
+	if got := Redact(sts); strings.Contains(got, "wJalr") || strings.Contains(got, "IQoJb") {
+		t.Errorf("Expected the STS credentials to be redacted, got %q", got)
+	}
+
+	sso := `{"roleCredentials":{"accessKeyId":"ASIA","secretAccessKey":"wJalr","sessionToken":"IQoJb"}}`
+	if got := Redact(sso); strings.Contains(got, "wJalr") || strings.Contains(got, "IQoJb") {
+		t.Errorf("Expected the SSO credentials to be redacted, got %q", got)
+	}
+}
+
+func TestSDKLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, LevelTrace, "text")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sdk := SDKLogger(logger)
+	sdk.Logf(smithylog.Debug, "Request\n%s", "X-Amz-Security-Token: secret")
+	sdk.Logf(smithylog.Warn, "response has no checksum")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 entries, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], "level=TRACE") || strings.Contains(lines[0], "secret") {
+		t.Errorf("Expected a redacted trace entry, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "level=WARN") || !strings.Contains(lines[1], "source=aws-sdk") {
+		t.Errorf("Expected a warning of the SDK, got %q", lines[1])
+	}
+}
@@ -0,0 +1,239 @@
+// Package trash keeps deleted messages in a local JSON Lines file, so they can
+// be sent again after a mistaken delete.
+package trash
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+// Entry is a deleted message and the queue it was deleted from.
+type Entry struct {
+	ID        string      `json:"id"`
+	DeletedAt time.Time   `json:"deleted_at"`
+	QueueUrl  string      `json:"queue_url"`
+	QueueName string      `json:"queue_name"`
+	Context   string      `json:"context,omitempty"` // configured context of the queue, if any
+	Message   kue.Message `json:"message"`           // body and FIFO IDs
+	// Message attributes with their data types, sent again unchanged
+	MessageAttributes map[string]kue.MessageAttributeValue `json:"message_attributes,omitempty"`
+}
+
+// NewEntry returns the entry of a message deleted from a queue.
+func NewEntry(queueUrl, context string, msg kue.Message, deletedAt time.Time) Entry {
+	// Receipt handles are useless once the message is deleted
+	msg.ReceiptHandle = ""
+	return Entry{
+		ID:        fmt.Sprintf("%d-%s", deletedAt.UnixNano(), msg.MessageID),
+		DeletedAt: deletedAt.UTC(),
+		QueueUrl:  queueUrl,
+		QueueName: queueUrl[strings.LastIndex(queueUrl, "/")+1:],
+		Context:   context,
+		Message:   msg,
+
+		MessageAttributes: msg.MessageAttributeValues,
+	}
+}
+
+// Bin stores the entries in a file. A nil bin keeps nothing.
+type Bin struct {
+	mu        sync.Mutex
+	file      string
+	retention time.Duration
+}
+
+// Open returns the bin of a file, or nil when no file is configured. Entries
+// older than the retention are dropped.
+func Open(file string, retention time.Duration) *Bin {
+	if file == "" {
+		return nil
+	}
+	return &Bin{file: file, retention: retention}
+}
+
+// File returns the path of the trash file.
+func (b *Bin) File() string {
+	if b == nil {
+		return ""
+	}
+	return b.file
+}
+
+// Retention returns how long entries are kept.
+func (b *Bin) Retention() time.Duration {
+	if b == nil {
+		return 0
+	}
+	return b.retention
+}
+
+// Add appends entries to the file.
+func (b *Bin) Add(entries ...Entry) error {
+	if b == nil || len(entries) == 0 {
+		return nil
+	}
+	var lines []byte
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to encode trash entry: %w", err)
+		}
+		lines = append(append(lines, line...), '\n')
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(b.file), 0o755); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	// Message bodies may hold personal data, so the file is private
+	f, err := os.OpenFile(b.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open trash: %w", err)
+	}
+	if _, err := f.Write(lines); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write trash: %w", err)
+	}
+	return f.Close()
+}
+
+// Entries returns the entries in the order they were deleted, dropping the
+// entries past their retention from the file.
+func (b *Bin) Entries() ([]Entry, error) {
+	if b == nil {
+		return nil, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entries, err := b.read()
+	if err != nil {
+		return nil, err
+	}
+	kept := entries[:0:0]
+	for _, e := range entries {
+		if b.retention <= 0 || time.Since(e.DeletedAt) < b.retention {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) < len(entries) {
+		if err := b.write(kept); err != nil {
+			return kept, err
+		}
+	}
+	return kept, nil
+}
+
+// Remove drops entries from the file, e.g. after they were restored.
+func (b *Bin) Remove(ids ...string) error {
+	if b == nil || len(ids) == 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	removed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		removed[id] = true
+	}
+	entries, err := b.read()
+	if err != nil {
+		return err
+	}
+	var kept []Entry
+	for _, e := range entries {
+		if !removed[e.ID] {
+			kept = append(kept, e)
+		}
+	}
+	return b.write(kept)
+}
+
+// Purge deletes all entries.
+func (b *Bin) Purge() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := os.Remove(b.file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to purge trash: %w", err)
+	}
+	return nil
+}
+
+// read returns all entries of the file. A missing file has no entries.
+func (b *Bin) read() ([]Entry, error) {
+	f, err := os.Open(b.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trash: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	// A message body is at most 256 KiB, more once encoded as JSON
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("failed to parse trash line %d: %w", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+	return entries, nil
+}
+
+// write replaces the file with the entries. The entries are written to a
+// temporary file first, so a failed write keeps the previous entries.
+func (b *Bin) write(entries []Entry) error {
+	if err := os.MkdirAll(filepath.Dir(b.file), 0o755); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(b.file), ".trash-*")
+	if err != nil {
+		return fmt.Errorf("failed to create trash: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write trash: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write trash: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write trash: %w", err)
+	}
+	if err := os.Rename(f.Name(), b.file); err != nil {
+		return fmt.Errorf("failed to replace trash: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+func testMessage(id string) kue.Message {
+	return kue.Message{
+		MessageID:         id,
+		Body:              `{"order":` + id + `}`,
+		ReceiptHandle:     "handle-" + id,
+		MessageAttributes: map[string]string{"source": "web"},
+		MessageAttributeValues: map[string]kue.MessageAttributeValue{
+			"source":    {DataType: "String", StringValue: "web"},
+			"signature": {DataType: "Binary.sha256", BinaryValue: []byte{0x01, 0xff}},
+		},
+		MessageGroupID: "orders",
+	}
+}
+
+func TestAddAndRemoveEntries(t *testing.T) {
+	bin := Open(filepath.Join(t.TempDir(), "state", "trash.jsonl"), time.Hour)
+	now := time.Now()
+	first := NewEntry("https://sqs.eu-west-1.amazonaws.com/123456789012/orders.fifo", "prod", testMessage("1"), now)
+	second := NewEntry("https://sqs.eu-west-1.amazonaws.com/123456789012/orders.fifo", "prod", testMessage("2"), now.Add(time.Millisecond))
+
+	if err := bin.Add(first, second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	entries, err := bin.Entries()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.QueueName != "orders.fifo" || e.Context != "prod" || e.Message.Body != `{"order":1}` {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+	if e.Message.ReceiptHandle != "" {
+		t.Error("Expected the receipt handle not to be kept")
+	}
+	if e.Message.MessageGroupID != "orders" || e.MessageAttributes["source"].StringValue != "web" {
+		t.Errorf("Expected the group ID and attributes to be kept, got %+v", e)
+	}
+	if sig := e.MessageAttributes["signature"]; sig.DataType != "Binary.sha256" || string(sig.BinaryValue) != "\x01\xff" {
+		t.Errorf("Expected the binary attribute with its data type, got %+v", sig)
+	}
+
+	if err := bin.Remove(first.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	entries, _ = bin.Entries()
+	if len(entries) != 1 || entries[0].ID != second.ID {
+		t.Errorf("Expected only the second entry to be left, got %+v", entries)
+	}
+
+	info, err := os.Stat(bin.File())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected a private trash file, got %v", info.Mode().Perm())
+	}
+}
+
+func TestEntriesDropsExpired(t *testing.T) {
+	bin := Open(filepath.Join(t.TempDir(), "trash.jsonl"), time.Hour)
+	old := NewEntry("http://test/orders", "", testMessage("1"), time.Now().Add(-2*time.Hour))
+	recent := NewEntry("http://test/orders", "", testMessage("2"), time.Now())
+	if err := bin.Add(old, recent); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	entries, err := bin.Entries()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != recent.ID {
+		t.Errorf("Expected the expired entry to be dropped, got %+v", entries)
+	}
+
+	// The expired entry is removed from the file as well
+	entries, _ = Open(bin.File(), 0).Entries()
+	if len(entries) != 1 {
+		t.Errorf("Expected the file to keep 1 entry, got %d", len(entries))
+	}
+}
+
+func TestPurge(t *testing.T) {
+	bin := Open(filepath.Join(t.TempDir(), "trash.jsonl"), time.Hour)
+	if err := bin.Purge(); err != nil {
+		t.Errorf("Expected purging an empty trash to succeed, got %v", err)
+	}
+	if err := bin.Add(NewEntry("http://test/orders", "", testMessage("1"), time.Now())); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := bin.Purge(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if entries, err := bin.Entries(); err != nil || len(entries) != 0 {
+		t.Errorf("Expected an empty trash, got %v, %v", entries, err)
+	}
+}
+
+func TestNilBinKeepsNothing(t *testing.T) {
+	var bin *Bin
+	if err := bin.Add(NewEntry("http://test/orders", "", testMessage("1"), time.Now())); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if entries, err := bin.Entries(); entries != nil || err != nil {
+		t.Errorf("Expected no entries, got %v, %v", entries, err)
+	}
+}
@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-runewidth"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/alert"
+	kue "github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/commands"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
+)
+
+// severityStyle returns the style used to highlight alerts of a severity.
+func severityStyle(s alert.Severity) lipgloss.Style {
+	switch s {
+	case alert.Info:
+		return styles.Info()
+	case alert.Warning:
+		return styles.Warning()
+	}
+	return styles.Danger()
+}
+
+// deadLetterDepths returns the available messages of each loaded queue keyed
+// by ARN, used to resolve the depth of a queue's dead-letter queue.
+func (m model) deadLetterDepths() map[string]int {
+	depths := make(map[string]int)
+	for _, q := range m.state.queueOverview.queues {
+		if n, err := strconv.Atoi(q.ApproximateNumberOfMessages); err == nil {
+			depths[q.Arn] = n
+		}
+	}
+	return depths
+}
+
+// queueMetrics collects the metrics alert rules are evaluated against.
+func (m model) queueMetrics() []alert.Metrics {
+	depths := m.deadLetterDepths()
+	count := func(s string) int {
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+		return -1
+	}
+
+	var metrics []alert.Metrics
+	for _, q := range m.state.queueOverview.queues {
+		dlq := -1
+		if n, ok := depths[q.DeadLetterTargetARN]; ok && q.DeadLetterTargetARN != "" {
+			dlq = n
+		}
+		oldest := time.Duration(-1)
+		if age, ok := m.state.queueOverview.oldestAges[q.Key()]; ok {
+			oldest = age
+		}
+		metrics = append(metrics, alert.Metrics{
+			QueueUrl:     q.Url,
+			QueueName:    q.Name,
+			QueueContext: q.Context,
+			Visible:      count(q.ApproximateNumberOfMessages),
+			InFlight:     count(q.ApproximateNumberOfMessagesNotVisible),
+			Delayed:      count(q.ApproximateNumberOfMessagesDelayed),
+			DLQ:          dlq,
+			Oldest:       oldest,
+		})
+	}
+	return metrics
+}
+
+// oldestAgeErrorsStatus summarises the queues whose oldest message age failed
+// to load, like attributeErrorsStatus.
+func (m model) oldestAgeErrorsStatus(errs map[kue.QueueKey]error) string {
+	for _, q := range m.state.queueOverview.queues {
+		if err := errs[q.Key()]; err != nil {
+			if len(errs) == 1 {
+				return fmt.Sprintf("Failed to load the oldest message age of %s: %v", q.Name, err)
+			}
+			return fmt.Sprintf("Failed to load the oldest message age of %d queues, %s: %v", len(errs), q.Name, err)
+		}
+	}
+	return ""
+}
+
+// refreshAlerts loads the metrics that are not part of the queue attributes,
+// or evaluates the alert rules right away when none are needed.
+func (m model) refreshAlerts() (model, tea.Cmd) {
+	if m.alertRules.Empty() {
+		return m, nil
+	}
+
+	if m.metrics != nil && m.alertRules.UsesMetric("oldest") {
+		var queues []kue.Queue
+		for _, q := range m.state.queueOverview.queues {
+			if m.alertRules.NeedsMetric("oldest", q.Name) {
+				queues = append(queues, q)
+			}
+		}
+		return m, commands.LoadOldestMessageAges(m.pageContext, m.cloudWatchClients(), queues, m.config.Overview.Concurrency)
+	}
+
+	return m.evaluateAlerts()
+}
+
+// evaluateAlerts evaluates the alert rules and announces alerts that started
+// matching since the previous refresh.
+func (m model) evaluateAlerts() (model, tea.Cmd) {
+	alerts, started := m.alertRules.Evaluate(m.queueMetrics())
+	m.state.queueOverview.alerts = alerts
+	m.state.queueOverview.alertSeverity = alert.BySeverity(alerts)
+
+	if len(started) == 0 {
+		return m, nil
+	}
+	if !m.config.Alerts.Bell {
+		return m, commands.NotifyAlerts(m.config.Alerts, started)
+	}
+	// The bell is rendered with the view, written to the terminal apart from
+	// the renderer it could land in the middle of an escape sequence
+	m.bell = true
+	return m, tea.Batch(commands.NotifyAlerts(m.config.Alerts, started), commands.StopBellAfter(bellDuration))
+}
+
+// bellDuration is how long frames ring the bell, longer than a frame of the
+// renderer so the bell is flushed to the terminal.
+const bellDuration = 100 * time.Millisecond
+
+// renderAlertBadge renders the number of active alerts for the header.
+func (m model) renderAlertBadge() string {
+	alerts := m.state.queueOverview.alerts
+	if len(alerts) == 0 {
+		return ""
+	}
+
+	noun := "alerts"
+	if len(alerts) == 1 {
+		noun = "alert"
+	}
+	// Alerts are sorted by severity, the first is the most severe
+	return severityStyle(alerts[0].Severity).Bold(true).Render(fmt.Sprintf("▲ %d %s", len(alerts), noun))
+}
+
+// highlightAlertRows colors the rendered rows of queues with active alerts.
+// The table renders rows itself, so rows are recognized by their text.
+func (m model) highlightAlertRows(view string, queues []kue.Queue) string {
+	if len(m.state.queueOverview.alertSeverity) == 0 {
+		return view
+	}
+
+	columns := m.overviewColumns()
+	depths := m.deadLetterDepths()
+	tableColumns := m.state.queueOverview.table.Columns()
+	cursor := m.state.queueOverview.table.Cursor()
+
+	severities := make(map[string]alert.Severity)
+	for i, q := range queues {
+		severity, ok := m.state.queueOverview.alertSeverity[q.Key()]
+		if !ok || i == cursor {
+			continue
+		}
+		row := m.overviewRow(columns, depths, q, m.isQueueSelected(q))
+		severities[renderedRowKey(row, tableColumns)] = severity
+	}
+
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		plain := ansi.Strip(line)
+		if severity, ok := severities[strings.Join(strings.Fields(plain), " ")]; ok {
+			lines[i] = severityStyle(severity).Render(plain)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderedRowKey returns the whitespace-normalized text of a row as the table
+// renders it.
+func renderedRowKey(row table.Row, columns []table.Column) string {
+	var cells []string
+	for i, value := range row {
+		if i < len(columns) {
+			value = runewidth.Truncate(value, columns[i].Width, "…")
+		}
+		cells = append(cells, value)
+	}
+	return strings.Join(strings.Fields(strings.Join(cells, " ")), " ")
+}
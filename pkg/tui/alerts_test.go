@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kontrolplane/kue/pkg/alert"
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
+)
+
+func newTestAlertModel(t *testing.T) model {
+	t.Helper()
+	rules, err := alert.Compile([]config.AlertRule{
+		{Name: "dlq not empty", Condition: "dlq > 0"},
+		{Name: "backlog", Condition: "visible > 100", Severity: "warning"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to compile rules: %v", err)
+	}
+
+	m := newTestModel()
+	m.alertRules = rules
+	m.state.queueOverview.queues = []kue.Queue{
+		{Name: "orders", Url: "http://test/orders", ApproximateNumberOfMessages: "500", DeadLetterTargetARN: "arn:orders-dlq"},
+		{Name: "orders-dlq", Url: "http://test/orders-dlq", Arn: "arn:orders-dlq", ApproximateNumberOfMessages: "0"},
+		{Name: "billing", Url: "http://test/billing", ApproximateNumberOfMessages: "5"},
+	}
+	return m
+}
+
+func TestEvaluateAlerts(t *testing.T) {
+	m := newTestAlertModel(t)
+
+	m, cmd := m.refreshAlerts()
+	if cmd != nil {
+		t.Error("Expected no notification for the initial evaluation")
+	}
+	if got := m.state.queueOverview.alertSeverity[kue.QueueKey{Url: "http://test/orders"}]; got != alert.Warning {
+		t.Errorf("Expected a warning for orders, got %v", got)
+	}
+	if badge := m.renderAlertBadge(); !strings.Contains(badge, "1 alert") {
+		t.Errorf("Expected the badge to count 1 alert, got %q", badge)
+	}
+
+	// The DLQ receives messages during the next refresh
+	m.state.queueOverview.queues[1].ApproximateNumberOfMessages = "3"
+	m, cmd = m.refreshAlerts()
+	if cmd == nil {
+		t.Error("Expected a notification for the new alert")
+	}
+	if got := m.state.queueOverview.alertSeverity[kue.QueueKey{Url: "http://test/orders"}]; got != alert.Critical {
+		t.Errorf("Expected orders to escalate to critical, got %v", got)
+	}
+	if badge := m.renderAlertBadge(); !strings.Contains(badge, "2 alerts") {
+		t.Errorf("Expected the badge to count 2 alerts, got %q", badge)
+	}
+}
+
+func TestAlertBellRingsWithTheFrame(t *testing.T) {
+	m := newTestAlertModel(t)
+	m.config.Alerts.Bell = true
+	m, _ = m.refreshAlerts()
+	if strings.Contains(m.View(), "\a") {
+		t.Error("Expected no bell for the initial evaluation")
+	}
+
+	m.state.queueOverview.queues[1].ApproximateNumberOfMessages = "3"
+	m, _ = m.refreshAlerts()
+	if !strings.HasSuffix(m.View(), "\a") {
+		t.Error("Expected the bell to ring with the rendered frame")
+	}
+
+	result, _ := m.Update(messages.BellStoppedMsg{})
+	m = result.(model)
+	if strings.Contains(m.View(), "\a") {
+		t.Error("Expected the bell to stop")
+	}
+}
+
+func TestNoAlertRules(t *testing.T) {
+	m := newTestModel()
+	m.state.queueOverview.queues = []kue.Queue{{Name: "orders", Url: "http://test/orders"}}
+
+	m, cmd := m.refreshAlerts()
+	if cmd != nil || len(m.state.queueOverview.alerts) != 0 || m.renderAlertBadge() != "" {
+		t.Error("Expected no alerts without rules")
+	}
+}
+
+func TestRenderedRowKeyMatchesTable(t *testing.T) {
+	m := newTestAlertModel(t)
+	m.state.queueOverview.queues[0].Name = strings.Repeat("long-queue-name-", 4)
+	m = m.updateQueueOverviewTableFiltered()
+
+	columns := m.state.queueOverview.table.Columns()
+	rows := m.state.queueOverview.table.Rows()
+	lines := strings.Split(m.state.queueOverview.table.View(), "\n")
+
+	for i, row := range rows {
+		key := renderedRowKey(row, columns)
+		// The first two lines are the header and its border
+		line := strings.Join(strings.Fields(ansi.Strip(lines[i+2])), " ")
+		if line != key {
+			t.Errorf("Row %d: expected key %q to match line %q", i, key, line)
+		}
+	}
+}
+
+func TestOldestMessageAgesKeepQueuesThatLoaded(t *testing.T) {
+	m := newTestAlertModel(t)
+
+	result, _ := m.Update(messages.OldestMessageAgesLoadedMsg{
+		Ages: map[kue.QueueKey]time.Duration{{Url: "http://test/orders"}: 5 * time.Minute},
+		Errs: map[kue.QueueKey]error{{Url: "http://test/billing"}: errors.New("AccessDenied")},
+	})
+	m = result.(model)
+	if m.state.queueOverview.oldestAges[kue.QueueKey{Url: "http://test/orders"}] != 5*time.Minute {
+		t.Error("Expected the ages of the queues that loaded")
+	}
+	if !strings.Contains(m.statusMsg, "oldest message age of billing: AccessDenied") {
+		t.Errorf("Expected the failed queue in the status, got %q", m.statusMsg)
+	}
+}
@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/audit"
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/commands"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
+)
+
+// auditLogState holds the state for the audit log viewer.
+type auditLogState struct {
+	entries  []audit.Entry // newest first
+	selected int
+}
+
+// auditFor returns the recorder of mutating actions on a queue, with the
+// identity of the context the queue was loaded from. Actions that are not
+// about a queue, such as creating one, use an empty key.
+func (m model) auditFor(queue kue.QueueKey) audit.Recorder {
+	return m.auditForContext(m.contextOf(queue))
+}
+
+// auditForContext returns the recorder with the identity of a context, or of
+// the session when c is nil.
+func (m model) auditForContext(c *queueContext) audit.Recorder {
+	actor := audit.Actor{
+		User:    m.user,
+		Account: m.awsInfo.Account,
+		Profile: m.awsInfo.Profile,
+		Region:  m.awsInfo.Region,
+	}
+	if c != nil {
+		actor.Account = ""
+		actor.Profile = c.session.Info.Profile
+		actor.Region = c.session.Info.Region
+		actor.Context = c.name
+	}
+	return audit.Recorder{Log: m.auditLog, Actor: actor}
+}
+
+// auditRecorders returns the recorder of each context keyed by context name,
+// and the recorder of the session under the empty name, like sqsClients.
+func (m model) auditRecorders() map[string]audit.Recorder {
+	recs := map[string]audit.Recorder{"": m.auditForContext(nil)}
+	for i := range m.contexts {
+		recs[m.contexts[i].name] = m.auditForContext(&m.contexts[i])
+	}
+	return recs
+}
+
+func (m model) AuditLogSwitchPage(msg tea.Msg) (model, tea.Cmd) {
+	m.error = ""
+	m = m.SwitchPage(auditLog)
+	m.state.auditLog.selected = 0
+	if m.auditLog == nil {
+		m.state.auditLog.entries = nil
+		return m, nil
+	}
+	m.loading = true
+	m.loadingMsg = "Loading audit log..."
+	return m, commands.LoadAuditLog(m.auditLog)
+}
+
+// auditLogLoaded shows the entries newest first.
+func (m model) auditLogLoaded(msg messages.AuditLogLoadedMsg) model {
+	m.loading = false
+	m.loadingMsg = ""
+	if msg.Err != nil {
+		m.error = fmt.Sprintf("Error reading audit log: %v", msg.Err)
+	}
+	m.state.auditLog.entries = slices.Clone(msg.Entries)
+	slices.Reverse(m.state.auditLog.entries)
+	m.state.auditLog.selected = min(m.state.auditLog.selected, max(0, len(m.state.auditLog.entries)-1))
+	return m
+}
+
+func (m model) AuditLogUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Down):
+			if m.state.auditLog.selected < len(m.state.auditLog.entries)-1 {
+				m.state.auditLog.selected++
+			}
+		case key.Matches(msg, m.keys.Up):
+			if m.state.auditLog.selected > 0 {
+				m.state.auditLog.selected--
+			}
+		case key.Matches(msg, m.keys.Quit):
+			m.error = ""
+			return m.SwitchPage(queueOverview), nil
+		}
+	}
+	return m, nil
+}
+
+func (m model) AuditLogView() string {
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.MediumGray)
+	if m.auditLog == nil {
+		return mutedStyle.Render("The audit log is disabled, set audit.file in the configuration to enable it.")
+	}
+
+	entries := m.state.auditLog.entries
+	summary := mutedStyle.Render(fmt.Sprintf("%d actions recorded in %s", len(entries), m.auditLog.File()))
+	if len(entries) == 0 {
+		return summary + "\n\n" + mutedStyle.Render("No actions recorded yet.")
+	}
+
+	dangerStyle := styles.Danger()
+	selectedStyle := styles.Highlight(styles.AccentColor)
+
+	// The details of the selected entry take the lower part of the page
+	height := max(1, m.contentHeight()-12)
+	start := max(0, min(m.state.auditLog.selected-height/2, len(entries)-height))
+	end := min(len(entries), start+height)
+
+	lines := []string{mutedStyle.Render(fmt.Sprintf("%-19s  %-12s  %-20s  %-40s  %s", "time", "user", "action", "queue", "outcome"))}
+	for i := start; i < end; i++ {
+		e := entries[i]
+		cells := fmt.Sprintf("%-19s  %-12s  %-20s  %-40s",
+			e.Time.Local().Format("2006-01-02 15:04:05"),
+			runewidth.Truncate(e.User, 12, "…"),
+			e.Action,
+			runewidth.Truncate(queueNameFromArn(e.Target), 40, "…"),
+		)
+		if i == m.state.auditLog.selected {
+			cells = selectedStyle.Render(cells)
+		}
+		outcome := e.Outcome
+		if outcome == audit.Failure {
+			outcome = dangerStyle.Render(outcome)
+		}
+		lines = append(lines, cells+"  "+outcome)
+	}
+
+	return summary + "\n\n" + strings.Join(lines, "\n") + "\n\n" + m.renderAuditEntry(entries[m.state.auditLog.selected])
+}
+
+// renderAuditEntry shows the identity, target and parameters of an entry.
+func (m model) renderAuditEntry(e audit.Entry) string {
+	labelStyle := lipgloss.NewStyle().Foreground(styles.MediumGray).Width(12)
+	row := func(label, value string) string {
+		return labelStyle.Render(label) + runewidth.Truncate(value, m.contentWidth()-14, "…")
+	}
+
+	identity := e.Profile + " • " + e.Region
+	if e.Context != "" {
+		identity = e.Context + " • " + identity
+	}
+	if e.Account != "" {
+		identity += " • " + e.Account
+	}
+	rows := []string{
+		row("target", e.Target),
+		row("identity", identity),
+	}
+
+	names := make([]string, 0, len(e.Parameters))
+	for name := range e.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var params []string
+	for _, name := range names {
+		params = append(params, name+"="+e.Parameters[name])
+	}
+	if len(params) > 0 {
+		rows = append(rows, row("parameters", strings.Join(params, " ")))
+	}
+	if e.Error != "" {
+		rows = append(rows, labelStyle.Render("error")+styles.Danger().Render(runewidth.Truncate(e.Error, m.contentWidth()-14, "…")))
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/kontrolplane/kue/pkg/audit"
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
+)
+
+func TestAuditLogShowsNewestFirst(t *testing.T) {
+	m := newTestModel()
+	m.auditLog = audit.Open(filepath.Join(t.TempDir(), "audit.jsonl"))
+	m.user = "alice"
+	m.state.queueOverview.queues = []kue.Queue{{Name: "orders", Url: "https://sqs.eu-west-1.amazonaws.com/123456789012/orders"}}
+	m.awsInfo.Region = "eu-west-1"
+
+	rec := m.auditFor(m.state.queueOverview.queues[0].Key())
+	target := rec.QueueArn("https://sqs.eu-west-1.amazonaws.com/123456789012/orders")
+	rec.Record("SendMessage", target, map[string]string{"body_bytes": "12"}, nil)
+	rec.Record("PurgeQueue", target, nil, nil)
+
+	m, cmd := m.AuditLogSwitchPage(nil)
+	if m.page != auditLog || cmd == nil {
+		t.Fatal("Expected the audit log page to load the entries")
+	}
+	result, _ := m.Update(cmd())
+	m = result.(model)
+
+	entries := m.state.auditLog.entries
+	if len(entries) != 2 || entries[0].Action != "PurgeQueue" {
+		t.Fatalf("Expected the newest entry first, got %+v", entries)
+	}
+	if entries[0].User != "alice" || entries[0].Region != "eu-west-1" {
+		t.Errorf("Expected the actor of the model, got %+v", entries[0].Actor)
+	}
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = result.(model)
+	view := m.AuditLogView()
+	if !strings.Contains(view, "body_bytes=12") {
+		t.Errorf("Expected the parameters of the selected entry, got %q", view)
+	}
+	if !strings.Contains(view, "2 actions recorded") {
+		t.Errorf("Expected the number of actions, got %q", view)
+	}
+}
+
+func TestAuditLogReportsUnreadableEntries(t *testing.T) {
+	m := newTestModel()
+	m.auditLog = audit.Open(filepath.Join(t.TempDir(), "audit.jsonl"))
+	m = m.SwitchPage(auditLog)
+
+	result, _ := m.Update(messages.AuditLogLoadedMsg{Err: errors.New("failed to parse audit log line 3")})
+	if got := result.(model).error; !strings.Contains(got, "Error reading audit log") {
+		t.Errorf("Expected the read error, got %q", got)
+	}
+}
+
+func TestAuditLogDisabled(t *testing.T) {
+	m := newTestModel()
+	m, cmd := m.AuditLogSwitchPage(nil)
+	if cmd != nil {
+		t.Error("Expected nothing to load without an audit file")
+	}
+	if !strings.Contains(m.AuditLogView(), "audit log is disabled") {
+		t.Error("Expected the viewer to explain the audit log is disabled")
+	}
+}
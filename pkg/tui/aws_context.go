@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/tui/commands"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
+)
+
+// allContexts is the profile option that shows the queues of all configured
+// contexts together. Profile names cannot contain spaces.
+const allContexts = "all contexts"
+
+// awsContextInput holds the profile and region picked in the form.
+type awsContextInput struct {
+	profile string
+	region  string // empty for the region of the profile
+}
+
+// awsContextState holds the state for the profile and region picker.
+type awsContextState struct {
+	input *awsContextInput
+	form  *huh.Form
+}
+
+// newAWSContextForm builds the profile and region picker form.
+func newAWSContextForm(input *awsContextInput, profiles []client.Profile, contexts []string) *huh.Form {
+	var profileOptions []huh.Option[string]
+	if len(contexts) > 0 {
+		label := fmt.Sprintf("all configured contexts (%s)", strings.Join(contexts, ", "))
+		profileOptions = append(profileOptions, huh.NewOption(label, allContexts))
+	}
+	for _, p := range profiles {
+		label := p.Name
+		if p.Region != "" {
+			label = fmt.Sprintf("%s (%s)", p.Name, p.Region)
+		}
+		profileOptions = append(profileOptions, huh.NewOption(label, p.Name))
+	}
+
+	regionOptions := []huh.Option[string]{huh.NewOption("region of the profile", "")}
+	known := false
+	for _, r := range client.Regions {
+		regionOptions = append(regionOptions, huh.NewOption(r, r))
+		known = known || r == input.region
+	}
+	if !known && input.region != "" {
+		regionOptions = append(regionOptions, huh.NewOption(input.region, input.region))
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Profile").
+				Description("Profiles from the shared AWS config and credentials files").
+				Options(profileOptions...).
+				Height(10).
+				Value(&input.profile),
+
+			huh.NewSelect[string]().
+				Title("Region").
+				Description("Type / to filter regions, configured contexts use their own region").
+				Options(regionOptions...).
+				Height(8).
+				Value(&input.region),
+		).Title("AWS Profile").
+			Description("Switch the profile and region used for all requests"),
+	).
+		WithTheme(styles.FormTheme()).
+		WithShowHelp(true).
+		WithWidth(formWidth).
+		WithShowErrors(true)
+}
+
+func (m model) AWSContextSwitchPage(msg tea.Msg) (model, tea.Cmd) {
+	m.error = ""
+
+	profiles, err := client.ListProfiles()
+	if err != nil {
+		m.error = fmt.Sprintf("Error reading AWS profiles: %v", err)
+		return m, nil
+	}
+	// The current profile may come from the environment only
+	current := m.awsInfo.Profile
+	found := false
+	for _, p := range profiles {
+		found = found || p.Name == current
+	}
+	if !found {
+		profiles = append([]client.Profile{{Name: current}}, profiles...)
+	}
+
+	m.state.awsContext.input = &awsContextInput{
+		profile: current,
+		region:  m.session.Options.Region,
+	}
+	if m.aggregating() {
+		m.state.awsContext.input = &awsContextInput{profile: allContexts}
+	}
+
+	var contexts []string
+	for _, c := range m.config.AWS.Contexts {
+		contexts = append(contexts, c.Name)
+	}
+	m.state.awsContext.form = newAWSContextForm(m.state.awsContext.input, profiles, contexts)
+	return m.SwitchPage(awsContext), m.state.awsContext.form.Init()
+}
+
+func (m model) AWSContextUpdate(msg tea.Msg) (model, tea.Cmd) {
+	if m.state.awsContext.form == nil {
+		return m, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
+		return m.SwitchPage(queueOverview), nil
+	}
+
+	form, cmd := m.state.awsContext.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.state.awsContext.form = f
+	}
+
+	switch m.state.awsContext.form.State {
+	case huh.StateCompleted:
+		if m.state.awsContext.input.profile == allContexts {
+			m.loading = true
+			m.loadingMsg = "Switching to the configured contexts..."
+			return m, commands.OpenContexts(m.context, contextsOptions(m.config, m.mfa, m.retries))
+		}
+		opts := awsOptions(m.config, m.mfa, m.retries, m.state.awsContext.input.profile, m.state.awsContext.input.region)
+		m.loading = true
+		m.loadingMsg = fmt.Sprintf("Switching to profile %s...", opts.Profile)
+		return m, commands.SwitchAWSContext(m.context, opts)
+	case huh.StateAborted:
+		return m.SwitchPage(queueOverview), nil
+	}
+
+	return m, cmd
+}
+
+func (m model) AWSContextView() string {
+	if m.state.awsContext.form == nil {
+		return "Loading..."
+	}
+	return lipgloss.Place(m.contentWidth(), m.contentHeight(), lipgloss.Center, lipgloss.Top, m.state.awsContext.form.View())
+}
+
+// switchAWSContext replaces the clients after switching profile or region and
+// reloads the queue overview for the new account.
+func (m model) switchAWSContext(msg messages.AWSContextSwitchedMsg) (model, tea.Cmd) {
+	m.contexts = nil
+	m.contextByName = nil
+	m = m.useSession(msg.Session)
+	return m.reloadOverview(msg)
+}
+
+// switchToContexts replaces the clients with the clients of the configured
+// contexts and reloads the queue overview for all of them.
+func (m model) switchToContexts(msg messages.ContextsOpenedMsg) (model, tea.Cmd) {
+	m = m.useContexts(msg.Sessions)
+	return m.reloadOverview(msg)
+}
+
+// reloadOverview clears the queues of the previous clients and reloads the
+// queue overview.
+func (m model) reloadOverview(msg tea.Msg) (model, tea.Cmd) {
+	// Queues, selections and alerts belong to the previous account
+	m.state.queueOverview.queues = nil
+	m.state.queueOverview.selected = 0
+	m.state.queueOverview.alerts = nil
+	m.state.queueOverview.alertSeverity = nil
+	m.state.queueOverview.oldestAges = nil
+	m.state.queueOverview.fetchedAt = nil
+	m.state.queueOverview.pending = nil
+	m.state.queueOverview.attributeErrs = nil
+	m.alertRules.Reset()
+	m = m.updateQueueOverviewTableFiltered()
+
+	// Requests still running for the previous clients are no longer wanted
+	m = m.newPageContext()
+
+	m, cmd := m.QueueOverviewSwitchPage(msg)
+	return m, tea.Batch(cmd, commands.LoadAccountID(m.context, m.session))
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
+)
+
+// newTestSession creates a session for a local endpoint, no requests are made.
+func newTestSession(t *testing.T, profile string) *client.Session {
+	t.Helper()
+	t.Setenv("AWS_CONFIG_FILE", "/nonexistent")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	session, err := client.NewSession(context.Background(), client.Options{Region: "us-east-1", Endpoint: "http://localhost:4566"})
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	session.Options.Profile = profile
+	session.Info.Profile = profile
+	return session
+}
+
+func TestAWSContextSwitched(t *testing.T) {
+	m := newTestModel()
+	m.page = awsContext
+	m.awsInfo = client.AWSInfo{Profile: "dev", Region: "eu-west-1", Account: "111111111111"}
+	m.state.queueOverview.queues = []kue.Queue{{Name: "orders", Url: "http://test/orders"}}
+	m.state.queueOverview.selected = 0
+
+	previous := newTestSession(t, "dev")
+	m = m.useSession(previous)
+	session := newTestSession(t, "prod")
+	updated, cmd := m.Update(messages.AWSContextSwitchedMsg{Options: session.Options, Session: session})
+	m = updated.(model)
+
+	if m.page != queueOverview || !m.loading || cmd == nil {
+		t.Fatalf("Expected the overview to reload after switching")
+	}
+	if len(m.state.queueOverview.queues) != 0 {
+		t.Errorf("Expected the queues of the previous profile to be cleared")
+	}
+	if m.session != session || m.awsInfo.Profile != "prod" || m.awsInfo.Account != "" {
+		t.Errorf("Unexpected AWS context %+v", m.awsInfo)
+	}
+
+	// Accounts resolved for the previous profile are ignored
+	updated, _ = m.Update(messages.AccountIDLoadedMsg{Session: previous, Account: "111111111111"})
+	m = updated.(model)
+	if m.awsInfo.Account != "" {
+		t.Errorf("Expected a stale account ID to be ignored, got %q", m.awsInfo.Account)
+	}
+
+	updated, _ = m.Update(messages.AccountIDLoadedMsg{Session: session, Account: "222222222222"})
+	m = updated.(model)
+	header := formatHeader("test", "kue", "queue overview", m.awsInfo)
+	if !strings.HasSuffix(header, "profile: prod | region: us-east-1 | account: 222222222222") {
+		t.Errorf("Unexpected header %q", header)
+	}
+}
+
+func TestAWSContextSwitchFailed(t *testing.T) {
+	m := newTestModel()
+	m.page = awsContext
+	m.awsInfo = client.AWSInfo{Profile: "dev", Region: "eu-west-1"}
+
+	updated, _ := m.Update(messages.AWSContextSwitchedMsg{
+		Options: client.Options{Profile: "prod"},
+		Err:     errors.New("failed to get shared config profile, prod"),
+	})
+	m = updated.(model)
+
+	if m.page != queueOverview || !strings.Contains(m.error, "prod") {
+		t.Errorf("Expected an error on the overview, got page %d and %q", m.page, m.error)
+	}
+	if m.awsInfo.Profile != "dev" {
+		t.Errorf("Expected to keep the previous profile, got %q", m.awsInfo.Profile)
+	}
+}
+
+func TestRenderEndpointBadge(t *testing.T) {
+	if badge := renderEndpointBadge(client.AWSInfo{}); badge != "" {
+		t.Errorf("Expected no badge for the AWS endpoints, got %q", badge)
+	}
+	if badge := renderEndpointBadge(client.AWSInfo{Endpoint: "http://localhost:4566"}); !strings.Contains(badge, "LOCAL") || !strings.Contains(badge, "localhost:4566") {
+		t.Errorf("Expected a LOCAL badge, got %q", badge)
+	}
+	if badge := renderEndpointBadge(client.AWSInfo{Endpoint: "https://vpce-0123.sqs.eu-west-1.vpce.amazonaws.com"}); !strings.Contains(badge, "ENDPOINT") {
+		t.Errorf("Expected a custom endpoint badge, got %q", badge)
+	}
+}
+
+func TestRenderEndpointBadgesOfContexts(t *testing.T) {
+	m := newTestModel()
+	m.config.AWS.Contexts = []config.ContextSettings{{Name: "prod"}, {Name: "local"}}
+	prod := newTestSession(t, "prod")
+	prod.Info.Endpoint = ""
+	m = m.useContexts(map[string]*client.Session{
+		"prod":  prod,
+		"local": newTestSession(t, "local"),
+	})
+
+	badge := m.renderEndpointBadges()
+	if !strings.Contains(badge, "local") || !strings.Contains(badge, "LOCAL") || !strings.Contains(badge, "localhost:4566") {
+		t.Errorf("Expected the badge of the local context, got %q", badge)
+	}
+	if strings.Contains(badge, "prod") {
+		t.Errorf("Expected no badge for the context using the AWS endpoints, got %q", badge)
+	}
+	if !strings.Contains(m.View(), badge) {
+		t.Error("Expected the header to show the endpoint badge")
+	}
+}
@@ -0,0 +1,329 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	tea "github.com/charmbracelet/bubbletea"
+	keys "github.com/kontrolplane/kue/pkg/keys"
+	"github.com/kontrolplane/kue/pkg/tui/highlight"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
+)
+
+// bodyViewer displays a message body with syntax highlighting, search, line
+// numbers, soft wrapping and collapsible JSON objects.
+type bodyViewer struct {
+	viewport    viewport.Model
+	content     string
+	format      highlight.Format
+	lineNumbers bool
+	wrap        bool
+	collapsed   bool // collapse nested JSON objects and arrays
+	searching   bool
+	searchInput textinput.Model
+	query       string
+	matches     []bodyMatch
+	current     int   // index into matches
+	lineOffsets []int // first display line of every visible source line
+}
+
+// bodyLine is a source line that is visible after collapsing.
+type bodyLine struct {
+	number int // 1-based line number in the content
+	text   string
+}
+
+// bodyMatch is a search match within a visible line.
+type bodyMatch struct {
+	line       int // index into the visible lines
+	start, end int // byte offsets within the line text
+}
+
+func newBodyViewer(width, height int) bodyViewer {
+	ti := textinput.New()
+	ti.Placeholder = "Search body..."
+	ti.CharLimit = 100
+	ti.Width = 30
+
+	return bodyViewer{
+		viewport:    viewport.New(width, height),
+		searchInput: ti,
+		lineNumbers: true,
+	}
+}
+
+// SetContent replaces the body, keeping the display options and search query.
+func (v bodyViewer) SetContent(content string) bodyViewer {
+	v.content = content
+	v.format = highlight.Detect(content)
+	v.current = 0
+	v = v.refresh()
+	v.viewport.GotoTop()
+	return v
+}
+
+// visibleLines returns the source lines left after collapsing.
+func (v bodyViewer) visibleLines() []bodyLine {
+	lines := strings.Split(v.content, "\n")
+	if !v.collapsed || v.format != highlight.JSON {
+		out := make([]bodyLine, len(lines))
+		for i, line := range lines {
+			out[i] = bodyLine{number: i + 1, text: line}
+		}
+		return out
+	}
+	return collapseJSON(lines)
+}
+
+// collapseJSON folds every object and array below the top level of indented
+// JSON onto a single line.
+func collapseJSON(lines []string) []bodyLine {
+	var out []bodyLine
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " ")
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if indent > 0 && (strings.HasSuffix(line, "{") || strings.HasSuffix(line, "[")) {
+			if end, closing := findClosingLine(lines, i, indent); end > i {
+				out = append(out, bodyLine{number: i + 1, text: line + "…" + closing})
+				i = end
+				continue
+			}
+		}
+		out = append(out, bodyLine{number: i + 1, text: lines[i]})
+	}
+	return out
+}
+
+// findClosingLine finds the line closing the block opened on line start.
+func findClosingLine(lines []string, start int, indent int) (int, string) {
+	for j := start + 1; j < len(lines); j++ {
+		trimmed := strings.TrimLeft(lines[j], " ")
+		if len(lines[j])-len(trimmed) != indent {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "}") || strings.HasPrefix(trimmed, "]") {
+			return j, strings.TrimSpace(trimmed)
+		}
+	}
+	return -1, ""
+}
+
+// findMatches returns all case-insensitive occurrences of query.
+func findMatches(lines []bodyLine, query string) []bodyMatch {
+	if query == "" {
+		return nil
+	}
+	re := regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+
+	var matches []bodyMatch
+	for i, line := range lines {
+		for _, loc := range re.FindAllStringIndex(line.text, -1) {
+			matches = append(matches, bodyMatch{line: i, start: loc[0], end: loc[1]})
+		}
+	}
+	return matches
+}
+
+// refresh re-renders the viewport content from the current options.
+func (v bodyViewer) refresh() bodyViewer {
+	lines := v.visibleLines()
+	v.matches = findMatches(lines, v.query)
+	if v.current >= len(v.matches) {
+		v.current = 0
+	}
+
+	gutterWidth := 0
+	if v.lineNumbers {
+		gutterWidth = len(fmt.Sprint(len(strings.Split(v.content, "\n")))) + 1
+	}
+	textWidth := max(1, v.viewport.Width-gutterWidth)
+	gutterStyle := lipgloss.NewStyle().Foreground(styles.DarkGray)
+
+	var display []string
+	v.lineOffsets = make([]int, len(lines))
+	matchIdx := 0
+	for i, line := range lines {
+		v.lineOffsets[i] = len(display)
+
+		var lineMatches []bodyMatch
+		first := matchIdx
+		for matchIdx < len(v.matches) && v.matches[matchIdx].line == i {
+			lineMatches = append(lineMatches, v.matches[matchIdx])
+			matchIdx++
+		}
+
+		rendered := v.renderLine(line.text, lineMatches, first)
+		var parts []string
+		if v.wrap {
+			parts = strings.Split(ansi.Hardwrap(rendered, textWidth, true), "\n")
+		} else {
+			parts = []string{ansi.Truncate(rendered, textWidth, "…")}
+		}
+
+		for j, part := range parts {
+			if v.lineNumbers {
+				number := ""
+				if j == 0 {
+					number = fmt.Sprint(line.number)
+				}
+				part = gutterStyle.Render(fmt.Sprintf("%*s ", gutterWidth-1, number)) + part
+			}
+			display = append(display, part)
+		}
+	}
+
+	v.viewport.SetContent(strings.Join(display, "\n"))
+	return v
+}
+
+// renderLine highlights a line and marks its search matches. first is the
+// index of the line's first match in v.matches.
+func (v bodyViewer) renderLine(text string, matches []bodyMatch, first int) string {
+	tokens := highlight.Tokenize(v.format, text)
+	if len(matches) == 0 {
+		var b strings.Builder
+		for _, t := range tokens {
+			b.WriteString(highlight.Style(t.Kind).Render(t.Text))
+		}
+		return b.String()
+	}
+
+	// styleAt returns the match style covering the byte at pos, if any.
+	styleAt := func(pos int) (lipgloss.Style, bool) {
+		for i, m := range matches {
+			if pos >= m.start && pos < m.end {
+				if first+i == v.current {
+					return styles.SearchCurrentMatch, true
+				}
+				return styles.SearchMatch, true
+			}
+		}
+		return lipgloss.Style{}, false
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, t := range tokens {
+		base := highlight.Style(t.Kind)
+		segStart := 0
+		for segStart < len(t.Text) {
+			style, inMatch := styleAt(pos + segStart)
+			segEnd := segStart + 1
+			for segEnd < len(t.Text) {
+				next, nextInMatch := styleAt(pos + segEnd)
+				if nextInMatch != inMatch || (inMatch && (next.GetBackground() != style.GetBackground() || next.GetReverse() != style.GetReverse())) {
+					break
+				}
+				segEnd++
+			}
+			if !inMatch {
+				style = base
+			}
+			b.WriteString(style.Render(t.Text[segStart:segEnd]))
+			segStart = segEnd
+		}
+		pos += len(t.Text)
+	}
+	return b.String()
+}
+
+// scrollToMatch scrolls the viewport so the current match is visible.
+func (v bodyViewer) scrollToMatch() bodyViewer {
+	if len(v.matches) == 0 {
+		return v
+	}
+	offset := v.lineOffsets[v.matches[v.current].line]
+	if offset < v.viewport.YOffset || offset >= v.viewport.YOffset+v.viewport.Height {
+		v.viewport.SetYOffset(offset - v.viewport.Height/3)
+	}
+	return v
+}
+
+// MatchStatus describes the search position, e.g. "2/5".
+func (v bodyViewer) MatchStatus() string {
+	if len(v.matches) == 0 {
+		return "no matches"
+	}
+	return fmt.Sprintf("%d/%d", v.current+1, len(v.matches))
+}
+
+func (v bodyViewer) Update(msg tea.Msg, keyMap keys.KeyMap) (bodyViewer, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if v.searching {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.Type {
+			case tea.KeyEsc:
+				v.searching = false
+				v.searchInput.Blur()
+				return v, nil
+			case tea.KeyEnter:
+				v.searching = false
+				v.searchInput.Blur()
+				return v.scrollToMatch(), nil
+			}
+		}
+		v.searchInput, cmd = v.searchInput.Update(msg)
+		// Live search as user types
+		v.query = v.searchInput.Value()
+		v.current = 0
+		v = v.refresh().scrollToMatch()
+		return v, cmd
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, keyMap.Filter):
+			v.searching = true
+			v.searchInput.Focus()
+			return v, textinput.Blink
+		case key.Matches(msg, keyMap.NextMatch):
+			if len(v.matches) > 0 {
+				v.current = (v.current + 1) % len(v.matches)
+				v = v.refresh().scrollToMatch()
+			}
+			return v, nil
+		case key.Matches(msg, keyMap.PrevMatch):
+			if len(v.matches) > 0 {
+				v.current = (v.current - 1 + len(v.matches)) % len(v.matches)
+				v = v.refresh().scrollToMatch()
+			}
+			return v, nil
+		case key.Matches(msg, keyMap.LineNumbers):
+			v.lineNumbers = !v.lineNumbers
+			return v.refresh(), nil
+		case key.Matches(msg, keyMap.Wrap):
+			v.wrap = !v.wrap
+			return v.refresh(), nil
+		case key.Matches(msg, keyMap.Collapse):
+			v.collapsed = !v.collapsed
+			v.current = 0
+			v = v.refresh()
+			v.viewport.GotoTop()
+			return v, nil
+		}
+	}
+
+	v.viewport, cmd = v.viewport.Update(msg)
+	return v, cmd
+}
+
+// ClearSearch removes the search query and its highlights.
+func (v bodyViewer) ClearSearch() bodyViewer {
+	v.query = ""
+	v.searchInput.SetValue("")
+	v.current = 0
+	return v.refresh()
+}
+
+func (v bodyViewer) View() string {
+	return v.viewport.View()
+}
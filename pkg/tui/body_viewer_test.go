@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/keys"
+)
+
+const testJSONBody = `{
+  "order": {
+    "id": "1",
+    "status": "FAILED"
+  },
+  "items": [
+    "a",
+    "b"
+  ],
+  "status": "retry"
+}`
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestBodyViewerSearch(t *testing.T) {
+	v := newBodyViewer(60, 5).SetContent(testJSONBody)
+
+	v, _ = v.Update(runes("/"), keys.Keys)
+	if !v.searching {
+		t.Fatal("Expected viewer to be searching")
+	}
+	for _, r := range "status" {
+		v, _ = v.Update(runes(string(r)), keys.Keys)
+	}
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyEnter}, keys.Keys)
+
+	if len(v.matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(v.matches))
+	}
+	if v.MatchStatus() != "1/2" {
+		t.Errorf("Expected match status 1/2, got %s", v.MatchStatus())
+	}
+
+	v, _ = v.Update(runes("n"), keys.Keys)
+	if v.current != 1 {
+		t.Errorf("Expected second match to be current, got %d", v.current)
+	}
+	// The last match is below the initial viewport and must be scrolled into view
+	if v.viewport.YOffset == 0 {
+		t.Error("Expected viewport to scroll to the current match")
+	}
+
+	v, _ = v.Update(runes("n"), keys.Keys)
+	if v.current != 0 {
+		t.Errorf("Expected search to wrap around to the first match, got %d", v.current)
+	}
+}
+
+func TestBodyViewerCollapse(t *testing.T) {
+	v := newBodyViewer(60, 20).SetContent(testJSONBody)
+
+	v, _ = v.Update(runes("z"), keys.Keys)
+
+	view := v.View()
+	if !strings.Contains(view, `"order": {…},`) {
+		t.Errorf("Expected nested object to be collapsed, got:\n%s", view)
+	}
+	if !strings.Contains(view, `"items": […],`) {
+		t.Errorf("Expected nested array to be collapsed, got:\n%s", view)
+	}
+	if strings.Contains(view, "FAILED") {
+		t.Error("Expected collapsed content to be hidden")
+	}
+}
+
+func TestBodyViewerLineNumbersAndWrap(t *testing.T) {
+	v := newBodyViewer(20, 10).SetContent(`{"description": "a long value that does not fit in the viewport"}`)
+
+	if !strings.HasPrefix(v.View(), "1 ") {
+		t.Errorf("Expected line numbers by default, got %q", v.View())
+	}
+	if v.viewport.TotalLineCount() != 1 {
+		t.Errorf("Expected a single truncated line, got %d", v.viewport.TotalLineCount())
+	}
+
+	v, _ = v.Update(runes("w"), keys.Keys)
+	if v.viewport.TotalLineCount() <= 1 {
+		t.Error("Expected long line to wrap onto multiple lines")
+	}
+
+	v, _ = v.Update(runes("#"), keys.Keys)
+	if strings.HasPrefix(v.View(), "1 ") {
+		t.Error("Expected line numbers to be hidden after toggling")
+	}
+}
@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/kontrolplane/kue/pkg/history"
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
+)
+
+const (
+	rateWindow       = 5 * time.Minute // period over which the rate of change is computed
+	trendChartWidth  = 120             // samples shown in the queue details charts
+	trendChartHeight = 3               // rows of each queue details chart
+)
+
+// chartBlocks are the eighth blocks used to draw sparklines and charts.
+var chartBlocks = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// lastValues returns at most n of the newest values.
+func lastValues(values []int, n int) []int {
+	if len(values) > n {
+		return values[len(values)-n:]
+	}
+	return values
+}
+
+// valueRange returns the minimum and maximum of values.
+func valueRange(values []int) (int, int) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	return lo, hi
+}
+
+// sparkline renders the newest values as a single line scaled between their
+// minimum and maximum.
+func sparkline(values []int, width int) string {
+	values = lastValues(values, width)
+	if len(values) == 0 {
+		return ""
+	}
+
+	lo, hi := valueRange(values)
+	var b strings.Builder
+	for _, v := range values {
+		level := 1
+		if hi > lo {
+			level = 1 + (v-lo)*7/(hi-lo)
+		}
+		b.WriteRune(chartBlocks[level])
+	}
+	return b.String()
+}
+
+// renderChart renders the newest values as a bar chart of the given height,
+// scaled from zero to the maximum, with the maximum labelled on the left.
+func renderChart(values []int, width, height int) string {
+	values = lastValues(values, width)
+	_, hi := valueRange(values)
+	label := fmt.Sprintf("%d", hi)
+	labelWidth := max(len(label), 1)
+
+	lines := make([]string, height)
+	for row := 0; row < height; row++ {
+		var b strings.Builder
+		for _, v := range values {
+			level := 0
+			if hi > 0 {
+				level = v*height*8/hi - (height-1-row)*8
+			}
+			b.WriteRune(chartBlocks[max(0, min(8, level))])
+		}
+
+		axis := strings.Repeat(" ", labelWidth)
+		switch row {
+		case 0:
+			axis = fmt.Sprintf("%*s", labelWidth, label)
+		case height - 1:
+			axis = fmt.Sprintf("%*d", labelWidth, 0)
+		}
+		lines[row] = axis + " │" + b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// queueSamples returns the recorded depth history of a queue.
+func (m model) queueSamples(queue kue.QueueKey) []history.Sample {
+	if m.history == nil {
+		return nil
+	}
+	return m.history.Samples(queue)
+}
+
+// formatRate formats a rate of change in messages per minute.
+func formatRate(samples []history.Sample, metric history.Metric) string {
+	rate, ok := history.Rate(samples, metric, rateWindow)
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f/min", rate)
+}
+
+// formatTimeToDrain formats the estimated time until the queue is empty.
+func formatTimeToDrain(samples []history.Sample) string {
+	if len(samples) > 0 && samples[len(samples)-1].Visible == 0 {
+		return "empty"
+	}
+	drain, ok := history.TimeToDrain(samples, rateWindow)
+	if !ok {
+		return "not draining"
+	}
+	return "~" + drain.Round(time.Second).String()
+}
+
+// renderQueueTrend renders the depth history charts of a queue.
+func (m model) renderQueueTrend(queue kue.QueueKey, width, chartHeight int) string {
+	samples := m.queueSamples(queue)
+	if len(samples) < 2 {
+		return lipgloss.NewStyle().
+			Foreground(styles.MediumGray).
+			Render("Collecting queue depth history, the trend is shown after the next refresh...")
+	}
+
+	titleStyle := lipgloss.NewStyle().Foreground(styles.AccentColor)
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.MediumGray)
+
+	series := []struct {
+		title  string
+		metric history.Metric
+	}{
+		{"visible", history.Visible},
+		{"in flight", history.InFlight},
+		{"delayed", history.Delayed},
+	}
+
+	var sections []string
+	for _, s := range series {
+		values := history.Values(samples, s.metric)
+		title := titleStyle.Render(s.title) + mutedStyle.Render(fmt.Sprintf(" %d · %s", values[len(values)-1], formatRate(samples, s.metric)))
+		sections = append(sections, title+"\n"+renderChart(values, width, chartHeight))
+	}
+
+	span := samples[len(samples)-1].Time.Sub(samples[0].Time).Round(time.Second)
+	summary := mutedStyle.Render(fmt.Sprintf("time to drain: %s · %d samples over %s", formatTimeToDrain(samples), len(samples), span))
+
+	return strings.Join(sections, "\n") + "\n\n" + summary
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kontrolplane/kue/pkg/history"
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []int
+		width  int
+		want   string
+	}{
+		{nil, 8, ""},
+		{[]int{5, 5, 5}, 8, "▁▁▁"},
+		{[]int{0, 7}, 8, "▁█"},
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7}, 8, "▁▂▃▄▅▆▇█"},
+		{[]int{100, 0, 7}, 2, "▁█"},
+	}
+
+	for _, tt := range tests {
+		if got := sparkline(tt.values, tt.width); got != tt.want {
+			t.Errorf("sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	chart := renderChart([]int{0, 5, 10}, 10, 2)
+	lines := strings.Split(chart, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	if lines[0] != "10 │  █" || lines[1] != " 0 │ ██" {
+		t.Errorf("Unexpected chart:\n%s", chart)
+	}
+}
+
+func TestQueueTrendColumn(t *testing.T) {
+	m := newTestModel()
+	m.history = history.NewStore(10)
+	queue := kue.Queue{Name: "orders", Url: "http://test/orders"}
+
+	if got := m.queueTrend(queue); got != "-" {
+		t.Errorf("Expected no trend without history, got %q", got)
+	}
+
+	start := time.Now()
+	for i, depth := range []string{"10", "20", "40"} {
+		queue.ApproximateNumberOfMessages = depth
+		m.history.Record(start.Add(time.Duration(i)*time.Minute), queue)
+	}
+
+	if got := m.queueTrend(queue); got != "▁▃█" {
+		t.Errorf("Expected a rising sparkline, got %q", got)
+	}
+	if got := m.queueRate(queue); got != "15" {
+		t.Errorf("Expected a rate of 15/min, got %q", got)
+	}
+}
+
+func TestQueueDetailsTrendToggle(t *testing.T) {
+	m := newTestModel()
+	m.page = queueDetails
+	m.history = history.NewStore(10)
+	m.state.queueDetails.queue = kue.Queue{Name: "orders", Url: "http://test/orders"}
+
+	m.state.queueDetails.showTrend = true
+	if view := m.QueueDetailsView(); !strings.Contains(view, "Collecting queue depth history") {
+		t.Errorf("Expected the collecting message, got:\n%s", view)
+	}
+
+	start := time.Now()
+	for i, depth := range []string{"30", "20", "10"} {
+		q := m.state.queueDetails.queue
+		q.ApproximateNumberOfMessages = depth
+		m.history.Record(start.Add(time.Duration(i)*time.Minute), q)
+	}
+
+	view := m.QueueDetailsView()
+	for _, want := range []string{"visible", "in flight", "delayed", "-10.0/min", "time to drain: ~1m0s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected trend view to contain %q, got:\n%s", want, view)
+		}
+	}
+}
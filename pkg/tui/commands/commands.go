@@ -3,36 +3,180 @@ package commands
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/atotto/clipboard"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/alert"
+	"github.com/kontrolplane/kue/pkg/audit"
+	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/config"
 	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/trash"
 	"github.com/kontrolplane/kue/pkg/tui/messages"
 )
 
-// RefreshInterval is the default interval for auto-refresh.
-const RefreshInterval = 30 * time.Second
+// requestIDKey is the context key of the request ID.
+type requestIDKey struct{}
 
-// LoadQueues creates a command to load all queues with their attributes.
-func LoadQueues(ctx context.Context, client *sqs.Client) tea.Cmd {
+// WithRequestID returns a context whose requests report id on their result
+// messages, so results of requests that are no longer wanted can be told apart.
+func WithRequestID(ctx context.Context, id uint64) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// requestID returns the request ID of a context, or zero if it has none.
+func requestID(ctx context.Context) uint64 {
+	id, _ := ctx.Value(requestIDKey{}).(uint64)
+	return id
+}
+
+// LoadQueues creates a command to list the queues of several contexts in
+// parallel, keyed by context name or by an empty name for a single profile.
+// Queues are labelled with their context and listed by context name. The
+// command only fails if all contexts fail.
+func LoadQueues(ctx context.Context, clients map[string]*sqs.Client) tea.Cmd {
 	return func() tea.Msg {
-		queues, err := kue.ListQueuesUrls(client, ctx)
-		if err != nil {
-			return messages.QueuesLoadedMsg{Queues: nil, Err: err}
+		type result struct {
+			name   string
+			queues []kue.Queue
+			err    error
 		}
 
-		// Fetch attributes for each queue
-		for i, queue := range queues {
-			q, err := kue.FetchQueueAttributes(client, ctx, queue.Url)
-			if err != nil {
-				return messages.QueuesLoadedMsg{Queues: nil, Err: err}
+		results := make(chan result, len(clients))
+		for name, client := range clients {
+			go func() {
+				queues, err := kue.ListQueuesUrls(client, ctx)
+				results <- result{name: name, queues: queues, err: err}
+			}()
+		}
+
+		loaded := make(map[string][]kue.Queue)
+		errs := make(map[string]error)
+		for range clients {
+			r := <-results
+			if r.err != nil {
+				errs[r.name] = r.err
+				continue
 			}
-			queues[i] = q
+			for i := range r.queues {
+				r.queues[i].Context = r.name
+			}
+			loaded[r.name] = r.queues
+		}
+
+		names := make([]string, 0, len(loaded))
+		for name := range loaded {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var queues []kue.Queue
+		for _, name := range names {
+			queues = append(queues, loaded[name]...)
+		}
+
+		if len(errs) > 0 && len(errs) == len(clients) {
+			// All contexts failed, report the error of the first context by name
+			failed := make([]string, 0, len(errs))
+			for name := range errs {
+				failed = append(failed, name)
+			}
+			sort.Strings(failed)
+			return messages.QueuesLoadedMsg{RequestID: requestID(ctx), Err: errs[failed[0]]}
+		}
+		return messages.QueuesLoadedMsg{RequestID: requestID(ctx), Queues: queues, ContextErrors: errs}
+	}
+}
+
+// StreamQueueAttributes creates a command to load the attributes of the
+// queues with at most concurrency requests at a time, using the client of the
+// context each queue was loaded from. The command returns the first results,
+// WaitForQueueAttributes the following ones.
+func StreamQueueAttributes(ctx context.Context, clients map[string]*sqs.Client, queues []kue.Queue, concurrency int) tea.Cmd {
+	return func() tea.Msg {
+		return WaitForQueueAttributes(requestID(ctx), loadQueueAttributes(ctx, clients, queues, concurrency))()
+	}
+}
+
+// clientOf returns the client of the context a queue was loaded from, keyed by
+// context name, or the client under the empty name for queues of a single
+// profile. The clients are resolved before a command starts, the model keeps
+// changing while it runs.
+func clientOf[C any](clients map[string]C, q kue.Queue) C {
+	if c, ok := clients[q.Context]; ok {
+		return c
+	}
+	return clients[""]
+}
+
+// loadQueueAttributes fetches the attributes of the queues with at most
+// concurrency requests in flight. Queues that fail keep their URL, name and
+// context. The returned channel is closed once all attributes have loaded.
+func loadQueueAttributes(ctx context.Context, clients map[string]*sqs.Client, queues []kue.Queue, concurrency int) <-chan messages.QueueAttributesLoadedMsg {
+	results := make(chan messages.QueueAttributesLoadedMsg, len(queues))
+	jobs := make(chan kue.Queue)
+
+	var wg sync.WaitGroup
+	for range max(1, min(concurrency, len(queues))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q := range jobs {
+				loaded, err := kue.FetchQueueAttributes(clientOf(clients, q), ctx, q.Url)
+				if err != nil {
+					loaded = q
+				}
+				loaded.Context = q.Context
+				results <- messages.QueueAttributesLoadedMsg{RequestID: requestID(ctx), Queue: loaded, Err: err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, q := range queues {
+			jobs <- q
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// WaitForQueueAttributes creates a command that waits for attributes streamed
+// by StreamQueueAttributes, returning all results that are ready together.
+func WaitForQueueAttributes(id uint64, attributes <-chan messages.QueueAttributesLoadedMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg := messages.QueueAttributesStreamedMsg{RequestID: id, Attributes: attributes}
+		r, ok := <-attributes
+		if !ok {
+			msg.Done = true
+			return msg
 		}
+		msg.Results = append(msg.Results, r)
 
-		return messages.QueuesLoadedMsg{Queues: queues, Err: nil}
+		for {
+			select {
+			case r, ok := <-attributes:
+				if !ok {
+					msg.Done = true
+					return msg
+				}
+				msg.Results = append(msg.Results, r)
+			default:
+				return msg
+			}
+		}
 	}
 }
 
@@ -40,7 +184,7 @@ func LoadQueues(ctx context.Context, client *sqs.Client) tea.Cmd {
 func LoadQueueAttributes(ctx context.Context, client *sqs.Client, queueUrl string) tea.Cmd {
 	return func() tea.Msg {
 		queue, err := kue.FetchQueueAttributes(client, ctx, queueUrl)
-		return messages.QueueAttributesLoadedMsg{Queue: queue, Err: err}
+		return messages.QueueAttributesLoadedMsg{RequestID: requestID(ctx), Queue: queue, Err: err}
 	}
 }
 
@@ -48,35 +192,65 @@ func LoadQueueAttributes(ctx context.Context, client *sqs.Client, queueUrl strin
 func LoadMessages(ctx context.Context, client *sqs.Client, queueUrl string, maxMessages int32) tea.Cmd {
 	return func() tea.Msg {
 		msgs, err := kue.FetchQueueMessages(client, ctx, queueUrl, maxMessages)
-		return messages.MessagesLoadedMsg{Messages: msgs, Err: err}
+		return messages.MessagesLoadedMsg{RequestID: requestID(ctx), Messages: msgs, Err: err}
 	}
 }
 
 // CreateQueue creates a command to create a new queue.
-func CreateQueue(ctx context.Context, client *sqs.Client, config kue.QueueConfig) tea.Cmd {
+func CreateQueue(ctx context.Context, client *sqs.Client, rec audit.Recorder, config kue.QueueConfig) tea.Cmd {
 	return func() tea.Msg {
 		url, err := kue.CreateQueue(client, ctx, config)
 		queueUrl := ""
 		if url != nil {
 			queueUrl = *url
 		}
+		target := config.Name
+		if queueUrl != "" {
+			target = rec.QueueArn(queueUrl)
+		}
+		rec.Record("CreateQueue", target, queueConfigParameters(config), err)
 		return messages.QueueCreatedMsg{QueueUrl: queueUrl, Err: err}
 	}
 }
 
+// queueConfigParameters returns the settings of a created queue for the
+// audit log.
+func queueConfigParameters(config kue.QueueConfig) map[string]string {
+	params := map[string]string{
+		"name":                     config.Name,
+		"fifo":                     strconv.FormatBool(config.IsFifo),
+		"delay_seconds":            strconv.Itoa(config.DelaySeconds),
+		"maximum_message_size":     strconv.Itoa(config.MaximumMessageSize),
+		"message_retention_period": strconv.Itoa(config.MessageRetentionPeriod),
+		"receive_message_wait":     strconv.Itoa(config.ReceiveMessageWaitTime),
+		"visibility_timeout":       strconv.Itoa(config.VisibilityTimeout),
+	}
+	if config.IsFifo {
+		params["content_based_deduplication"] = strconv.FormatBool(config.ContentBasedDeduplication)
+		params["deduplication_scope"] = config.DeduplicationScope
+		params["fifo_throughput_limit"] = config.FifoThroughputLimit
+	}
+	return params
+}
+
 // DeleteQueue creates a command to delete a queue.
-func DeleteQueue(ctx context.Context, client *sqs.Client, queueName string) tea.Cmd {
+func DeleteQueue(ctx context.Context, client *sqs.Client, rec audit.Recorder, queue kue.Queue) tea.Cmd {
 	return func() tea.Msg {
-		err := kue.DeleteQueue(client, ctx, queueName)
+		err := kue.DeleteQueue(client, ctx, queue.Name)
+		rec.Record("DeleteQueue", queueArn(rec, queue), nil, err)
 		return messages.QueueDeletedMsg{Err: err}
 	}
 }
 
-// DeleteQueues creates a command to delete multiple queues.
-func DeleteQueues(ctx context.Context, client *sqs.Client, queues []kue.Queue) tea.Cmd {
+// DeleteQueues creates a command to delete multiple queues, each with the
+// client and audit recorder of its context.
+func DeleteQueues(ctx context.Context, clients map[string]*sqs.Client, recs map[string]audit.Recorder, queues []kue.Queue) tea.Cmd {
 	return func() tea.Msg {
 		for _, q := range queues {
-			if err := kue.DeleteQueue(client, ctx, q.Name); err != nil {
+			err := kue.DeleteQueue(clientOf(clients, q), ctx, q.Name)
+			rec := clientOf(recs, q)
+			rec.Record("DeleteQueue", queueArn(rec, q), nil, err)
+			if err != nil {
 				return messages.QueueDeletedMsg{Err: err}
 			}
 		}
@@ -84,19 +258,46 @@ func DeleteQueues(ctx context.Context, client *sqs.Client, queues []kue.Queue) t
 	}
 }
 
-// DeleteMessage creates a command to delete a message from a queue.
-func DeleteMessage(ctx context.Context, client *sqs.Client, queueUrl string, receiptHandle string) tea.Cmd {
-	return func() tea.Msg {
-		err := kue.DeleteMessage(client, ctx, queueUrl, receiptHandle)
-		return messages.MessageDeletedMsg{Err: err}
+// queueArn returns the ARN of a queue, derived from its URL when its
+// attributes have not been loaded.
+func queueArn(rec audit.Recorder, queue kue.Queue) string {
+	if queue.Arn != "" {
+		return queue.Arn
 	}
+	return rec.QueueArn(queue.Url)
 }
 
-// DeleteMessages creates a command to delete multiple messages from a queue.
-func DeleteMessages(ctx context.Context, client *sqs.Client, queueUrl string, msgs []kue.Message) tea.Cmd {
+// DeleteMessage creates a command to delete a message from a queue. The
+// message is moved to the trash first, and is not deleted if that fails.
+func DeleteMessage(ctx context.Context, client *sqs.Client, rec audit.Recorder, bin *trash.Bin, queueUrl string, msg kue.Message) tea.Cmd {
+	return DeleteMessages(ctx, client, rec, bin, queueUrl, []kue.Message{msg})
+}
+
+// DeleteMessages creates a command to delete multiple messages from a queue,
+// moving them to the trash first.
+func DeleteMessages(ctx context.Context, client *sqs.Client, rec audit.Recorder, bin *trash.Bin, queueUrl string, msgs []kue.Message) tea.Cmd {
 	return func() tea.Msg {
-		for _, msg := range msgs {
-			if err := kue.DeleteMessage(client, ctx, queueUrl, msg.ReceiptHandle); err != nil {
+		now := time.Now()
+		entries := make([]trash.Entry, len(msgs))
+		for i, msg := range msgs {
+			entries[i] = trash.NewEntry(queueUrl, rec.Actor.Context, msg, now)
+		}
+		if err := bin.Add(entries...); err != nil {
+			return messages.MessageDeletedMsg{Err: fmt.Errorf("failed to move messages to the trash: %w", err)}
+		}
+
+		for i, msg := range msgs {
+			err := kue.DeleteMessage(client, ctx, queueUrl, msg.ReceiptHandle)
+			rec.Record("DeleteMessage", rec.QueueArn(queueUrl), map[string]string{"message_id": msg.MessageID}, err)
+			if err != nil {
+				// The messages not deleted are still in the queue
+				var kept []string
+				for _, e := range entries[i:] {
+					kept = append(kept, e.ID)
+				}
+				if removeErr := bin.Remove(kept...); removeErr != nil {
+					slog.Warn("failed to remove messages not deleted from the trash", "err", removeErr)
+				}
 				return messages.MessageDeletedMsg{Err: err}
 			}
 		}
@@ -104,10 +305,70 @@ func DeleteMessages(ctx context.Context, client *sqs.Client, queueUrl string, ms
 	}
 }
 
-// SendMessage creates a command to send a message to a queue.
-func SendMessage(ctx context.Context, client *sqs.Client, input kue.SendMessageInput) tea.Cmd {
+// RestoreMessage creates a command to send a message of the trash to a
+// queue, the one it was deleted from or another, and to remove it from the
+// trash once sent. The FIFO IDs are only sent to FIFO queues.
+func RestoreMessage(ctx context.Context, client *sqs.Client, rec audit.Recorder, bin *trash.Bin, entry trash.Entry, queueUrl string) tea.Cmd {
 	return func() tea.Msg {
+		input := kue.SendMessageInput{
+			QueueUrl:          queueUrl,
+			MessageBody:       entry.Message.Body,
+			MessageAttributes: entry.MessageAttributes,
+		}
+		if strings.HasSuffix(queueUrl, ".fifo") {
+			input.MessageGroupId = entry.Message.MessageGroupID
+			if input.MessageGroupId == "" {
+				input.MessageGroupId = "kue-restore"
+			}
+			// The original ID may still be within the deduplication interval
+			input.MessageDeduplicationId = entry.ID
+		}
+
 		err := kue.SendMessage(client, ctx, input)
+		params := map[string]string{
+			"body_bytes":    strconv.Itoa(len(input.MessageBody)),
+			"restored_from": entry.QueueUrl,
+			"message_id":    entry.Message.MessageID,
+		}
+		rec.Record("SendMessage", rec.QueueArn(queueUrl), params, err)
+		if err != nil {
+			return messages.MessageRestoredMsg{Err: err}
+		}
+		if err := bin.Remove(entry.ID); err != nil {
+			return messages.MessageRestoredMsg{Err: fmt.Errorf("message restored, but %w", err)}
+		}
+		return messages.MessageRestoredMsg{Err: nil}
+	}
+}
+
+// LoadTrash creates a command to read the messages in the trash.
+func LoadTrash(bin *trash.Bin) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := bin.Entries()
+		return messages.TrashLoadedMsg{Entries: entries, Err: err}
+	}
+}
+
+// PurgeTrash creates a command to delete all messages in the trash for good.
+func PurgeTrash(bin *trash.Bin) tea.Cmd {
+	return func() tea.Msg {
+		return messages.TrashPurgedMsg{Err: bin.Purge()}
+	}
+}
+
+// SendMessage creates a command to send a message to a queue. The body is
+// not recorded in the audit log, only its size.
+func SendMessage(ctx context.Context, client *sqs.Client, rec audit.Recorder, input kue.SendMessageInput) tea.Cmd {
+	return func() tea.Msg {
+		err := kue.SendMessage(client, ctx, input)
+		params := map[string]string{"body_bytes": strconv.Itoa(len(input.MessageBody))}
+		if input.MessageGroupId != "" {
+			params["message_group_id"] = input.MessageGroupId
+		}
+		if input.MessageDeduplicationId != "" {
+			params["message_deduplication_id"] = input.MessageDeduplicationId
+		}
+		rec.Record("SendMessage", rec.QueueArn(input.QueueUrl), params, err)
 		return messages.MessageCreatedMsg{Err: err}
 	}
 }
@@ -119,15 +380,24 @@ func RefreshTick(d time.Duration, page string) tea.Cmd {
 	})
 }
 
-// ScheduleRefresh creates a command to schedule the next refresh for a page.
-func ScheduleRefresh(page string) tea.Cmd {
-	return RefreshTick(RefreshInterval, page)
+// ScheduleRefresh creates a command to schedule the next refresh for a page
+// after the refresh interval.
+func ScheduleRefresh(interval time.Duration, page string) tea.Cmd {
+	return RefreshTick(interval, page)
 }
 
 // StartRedrive creates a command to start a DLQ redrive task.
-func StartRedrive(ctx context.Context, client *sqs.Client, sourceArn string, destinationArn string) tea.Cmd {
+func StartRedrive(ctx context.Context, client *sqs.Client, rec audit.Recorder, sourceArn string, destinationArn string) tea.Cmd {
 	return func() tea.Msg {
 		taskHandle, err := kue.StartMessageMoveTask(client, ctx, sourceArn, destinationArn)
+		params := map[string]string{"destination": destinationArn}
+		if destinationArn == "" {
+			params["destination"] = "original source queues"
+		}
+		if taskHandle != "" {
+			params["task_handle"] = taskHandle
+		}
+		rec.Record("StartMessageMoveTask", sourceArn, params, err)
 		return messages.QueueRedriveStartedMsg{TaskHandle: taskHandle, Err: err}
 	}
 }
@@ -141,13 +411,22 @@ func ScheduleRedrivePoll(d time.Duration, ctx context.Context, client *sqs.Clien
 }
 
 // PurgeQueue creates a command to purge all messages from a queue.
-func PurgeQueue(ctx context.Context, client *sqs.Client, queueUrl string) tea.Cmd {
+func PurgeQueue(ctx context.Context, client *sqs.Client, rec audit.Recorder, queueUrl string) tea.Cmd {
 	return func() tea.Msg {
 		err := kue.PurgeQueue(client, ctx, queueUrl)
+		rec.Record("PurgeQueue", rec.QueueArn(queueUrl), nil, err)
 		return messages.QueuePurgedMsg{Err: err}
 	}
 }
 
+// LoadAuditLog creates a command to read the entries of the audit log.
+func LoadAuditLog(log *audit.Log) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := log.Entries()
+		return messages.AuditLogLoadedMsg{Entries: entries, Err: err}
+	}
+}
+
 // CopyToClipboard creates a command to copy text to the system clipboard.
 func CopyToClipboard(text string) tea.Cmd {
 	return func() tea.Msg {
@@ -162,3 +441,179 @@ func ClearStatusAfter(d time.Duration) tea.Cmd {
 		return messages.StatusClearMsg{}
 	})
 }
+
+// CooldownTick creates a command that sends a CooldownTickMsg after a second.
+func CooldownTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return messages.CooldownTickMsg{}
+	})
+}
+
+// SaveState creates a command to persist the UI state between sessions.
+func SaveState(state config.State) tea.Cmd {
+	return func() tea.Msg {
+		return messages.StateSavedMsg{Err: state.Save()}
+	}
+}
+
+// LoadOldestMessageAges creates a command to read the age of the oldest
+// message of each queue from CloudWatch, with the client of its context and
+// at most concurrency requests in flight. Queues that fail are reported
+// without failing the others.
+func LoadOldestMessageAges(ctx context.Context, clients map[string]*cloudwatch.Client, queues []kue.Queue, concurrency int) tea.Cmd {
+	return func() tea.Msg {
+		var mu sync.Mutex
+		ages := make(map[kue.QueueKey]time.Duration)
+		errs := make(map[kue.QueueKey]error)
+		forEachQueue(queues, concurrency, func(q kue.Queue) {
+			age, ok, err := kue.FetchOldestMessageAge(clientOf(clients, q), ctx, q.Name)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				errs[q.Key()] = err
+			case ok:
+				ages[q.Key()] = age
+			}
+		})
+		return messages.OldestMessageAgesLoadedMsg{RequestID: requestID(ctx), Ages: ages, Errs: errs}
+	}
+}
+
+// forEachQueue calls fn for each queue with at most concurrency calls running
+// at once, and returns once all calls have returned.
+func forEachQueue(queues []kue.Queue, concurrency int, fn func(q kue.Queue)) {
+	jobs := make(chan kue.Queue)
+	var wg sync.WaitGroup
+	for range max(1, min(concurrency, len(queues))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q := range jobs {
+				fn(q)
+			}
+		}()
+	}
+	for _, q := range queues {
+		jobs <- q
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// NotifyAlerts creates a command that runs the alert command for alerts that
+// started matching. The bell is rung by the view, see StopBellAfter.
+func NotifyAlerts(settings config.AlertSettings, alerts []alert.Alert) tea.Cmd {
+	return func() tea.Msg {
+		return messages.AlertsNotifiedMsg{Err: alert.Notify(settings, alerts)}
+	}
+}
+
+// StopBellAfter creates a command that sends a BellStoppedMsg after the given
+// duration.
+func StopBellAfter(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return messages.BellStoppedMsg{}
+	})
+}
+
+// LoadDeadLetterSources creates a command to list the source queues of each
+// of the given dead-letter queues, with the client of its context.
+func LoadDeadLetterSources(ctx context.Context, clients map[string]*sqs.Client, queues []kue.Queue, concurrency int) tea.Cmd {
+	return func() tea.Msg {
+		var mu sync.Mutex
+		sources := make(map[kue.QueueKey][]string)
+		errs := make(map[kue.QueueKey]error)
+		forEachQueue(queues, concurrency, func(q kue.Queue) {
+			urls, err := kue.ListDeadLetterSourceQueues(clientOf(clients, q), ctx, q.Url)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[q.Key()] = err
+				return
+			}
+			sources[q.Key()] = urls
+		})
+		return messages.DeadLetterSourcesLoadedMsg{RequestID: requestID(ctx), Sources: sources, Errs: errs}
+	}
+}
+
+// SwitchAWSContext creates a command to create the session for another AWS
+// profile or region.
+func SwitchAWSContext(ctx context.Context, opts client.Options) tea.Cmd {
+	return func() tea.Msg {
+		session, err := client.NewSession(ctx, opts)
+		return messages.AWSContextSwitchedMsg{Options: opts, Session: session, Err: err}
+	}
+}
+
+// LoadAccountID creates a command to resolve the account ID of the
+// credentials of a session.
+func LoadAccountID(ctx context.Context, session *client.Session) tea.Cmd {
+	return func() tea.Msg {
+		account, err := session.AccountID(ctx)
+		return messages.AccountIDLoadedMsg{Session: session, Account: account, Err: err}
+	}
+}
+
+// WaitForMFARequest creates a command that waits for credential resolution to
+// ask for an MFA code.
+func WaitForMFARequest(prompt *client.MFAPrompt) tea.Cmd {
+	return func() tea.Msg {
+		return messages.MFARequestedMsg{Request: <-prompt.Requests()}
+	}
+}
+
+// WaitForRetryStatus creates a command that waits for calls to start or stop
+// being retried.
+func WaitForRetryStatus(monitor *client.RetryMonitor) tea.Cmd {
+	if monitor == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		<-monitor.Changes()
+		return messages.RetryStatusMsg{Status: monitor.Status()}
+	}
+}
+
+// RefreshCredentials creates a command to resolve the credentials of the
+// options again, e.g. after an SSO login.
+func RefreshCredentials(ctx context.Context, opts client.Options) tea.Cmd {
+	return func() tea.Msg {
+		session, err := client.NewSession(ctx, opts)
+		if err != nil {
+			return messages.CredentialsRefreshedMsg{Err: err}
+		}
+		if err := session.Resolve(ctx); err != nil {
+			return messages.CredentialsRefreshedMsg{Err: err}
+		}
+		return messages.CredentialsRefreshedMsg{Session: session}
+	}
+}
+
+// OpenContexts creates a command to create the sessions of several contexts,
+// keyed by context name.
+func OpenContexts(ctx context.Context, opts map[string]client.Options) tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := client.NewSessions(ctx, opts)
+		return messages.ContextsOpenedMsg{Sessions: sessions, Err: err}
+	}
+}
+
+// RefreshContextCredentials creates a command to resolve the credentials of
+// several contexts again, keyed by context name. Contexts are resolved one at
+// a time, so MFA prompts do not overlap.
+func RefreshContextCredentials(ctx context.Context, opts map[string]client.Options) tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := client.NewSessions(ctx, opts)
+		if err != nil {
+			return messages.CredentialsRefreshedMsg{Err: err}
+		}
+		for name, session := range sessions {
+			if err := session.Resolve(ctx); err != nil {
+				return messages.CredentialsRefreshedMsg{Err: fmt.Errorf("context %s: %w", name, err)}
+			}
+		}
+		return messages.CredentialsRefreshedMsg{Contexts: sessions}
+	}
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/commands"
+)
+
+// queueContext is a named profile, region and endpoint whose queues are shown
+// in the aggregated overview.
+type queueContext struct {
+	name    string
+	session *client.Session
+	client  *sqs.Client
+	metrics *cloudwatch.Client // nil unless an alert rule needs CloudWatch metrics
+}
+
+// contextOptions returns the options of a configured context. The endpoint
+// and roles of the profile apply unless the context sets its own endpoint, a
+// context is read-only when kue or the context is.
+func contextOptions(cfg config.Config, prompt *client.MFAPrompt, retries *client.RetryMonitor, c config.ContextSettings) client.Options {
+	opts := awsOptions(cfg, prompt, retries, c.Profile, c.Region)
+	if c.Endpoint != "" && cfg.AWS.EndpointOverride == "" {
+		opts.Endpoint = c.Endpoint
+	}
+	opts.ReadOnly = cfg.ReadOnly || c.ReadOnly
+	return opts
+}
+
+// contextsOptions returns the options of all configured contexts, keyed by
+// context name.
+func contextsOptions(cfg config.Config, prompt *client.MFAPrompt, retries *client.RetryMonitor) map[string]client.Options {
+	opts := make(map[string]client.Options)
+	for _, c := range cfg.AWS.Contexts {
+		opts[c.Name] = contextOptions(cfg, prompt, retries, c)
+	}
+	return opts
+}
+
+// aggregating reports whether the overview shows the queues of several
+// contexts.
+func (m model) aggregating() bool {
+	return len(m.contexts) > 0
+}
+
+// useContexts switches to the aggregated overview of the sessions, keyed by
+// context name. The first configured context is used for requests that are
+// not about a queue, such as creating one.
+func (m model) useContexts(sessions map[string]*client.Session) model {
+	m.contexts = nil
+	m.contextByName = make(map[string]int)
+	for _, c := range m.config.AWS.Contexts {
+		session, ok := sessions[c.Name]
+		if !ok {
+			continue
+		}
+		qc := queueContext{name: c.Name, session: session, client: session.SQS()}
+		if m.alertRules.UsesMetric("oldest") {
+			qc.metrics = session.CloudWatch()
+		}
+		m.contextByName[c.Name] = len(m.contexts)
+		m.contexts = append(m.contexts, qc)
+	}
+	if len(m.contexts) > 0 {
+		m = m.useSession(m.contexts[0].session)
+	}
+	return m
+}
+
+// contextNames returns the names of the aggregated contexts in config order.
+func (m model) contextNames() []string {
+	var names []string
+	for _, c := range m.contexts {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// contextOf returns the context a queue was loaded from, or nil when the
+// overview does not aggregate contexts.
+func (m model) contextOf(queue kue.QueueKey) *queueContext {
+	if i, ok := m.contextByName[queue.Context]; ok {
+		return &m.contexts[i]
+	}
+	return nil
+}
+
+// clientFor returns the SQS client of the context a queue was loaded from.
+func (m model) clientFor(queue kue.QueueKey) *sqs.Client {
+	if c := m.contextOf(queue); c != nil {
+		return c.client
+	}
+	return m.client
+}
+
+// sqsClients returns the SQS client of each context keyed by context name, and
+// the client of the session under the empty name, for commands that request
+// several queues in the background.
+func (m model) sqsClients() map[string]*sqs.Client {
+	clients := map[string]*sqs.Client{"": m.client}
+	for _, c := range m.contexts {
+		clients[c.name] = c.client
+	}
+	return clients
+}
+
+// cloudWatchClients returns the CloudWatch clients like sqsClients.
+func (m model) cloudWatchClients() map[string]*cloudwatch.Client {
+	clients := map[string]*cloudwatch.Client{"": m.metrics}
+	for _, c := range m.contexts {
+		clients[c.name] = c.metrics
+	}
+	return clients
+}
+
+// metricsFor returns the CloudWatch client of the context a queue was loaded
+// from.
+func (m model) metricsFor(queue kue.QueueKey) *cloudwatch.Client {
+	if c := m.contextOf(queue); c != nil {
+		return c.metrics
+	}
+	return m.metrics
+}
+
+// loadQueues returns the command loading the overview queues, from all
+// contexts when aggregating.
+func (m model) loadQueues() tea.Cmd {
+	clients := map[string]*sqs.Client{"": m.client}
+	if m.aggregating() {
+		clients = make(map[string]*sqs.Client)
+		for _, c := range m.contexts {
+			clients[c.name] = c.client
+		}
+	}
+	return commands.LoadQueues(m.pageContext, clients)
+}
+
+// contextErrorsStatus summarises contexts that failed to load.
+func contextErrorsStatus(errs map[string]error) string {
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %v", name, errs[name]))
+	}
+	return "Failed to load contexts " + strings.Join(parts, "; ")
+}
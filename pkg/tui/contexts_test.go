@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
+)
+
+// newContextsModel returns a model aggregating the prod and staging contexts.
+func newContextsModel(t *testing.T) model {
+	t.Helper()
+	m := newTestModel()
+	m.config.AWS.Contexts = []config.ContextSettings{{Name: "prod"}, {Name: "staging"}}
+	m = m.useContexts(map[string]*client.Session{
+		"staging": newTestSession(t, "staging"),
+		"prod":    newTestSession(t, "prod"),
+	})
+	return m
+}
+
+func TestUseContexts(t *testing.T) {
+	m := newContextsModel(t)
+
+	if !m.aggregating() || strings.Join(m.contextNames(), ",") != "prod,staging" {
+		t.Fatalf("Expected the contexts in config order, got %v", m.contextNames())
+	}
+	if m.session != m.contexts[0].session || m.awsInfo.Profile != "prod" {
+		t.Errorf("Expected the first context to be the primary session, got %+v", m.awsInfo)
+	}
+
+	header := formatContextsHeader("test", "kue", "queue overview", m.contextNames())
+	if !strings.Contains(header, "contexts: prod, staging") {
+		t.Errorf("Expected the contexts in the header, got %q", header)
+	}
+}
+
+func TestClientForRoutesToContext(t *testing.T) {
+	m := newContextsModel(t)
+	// Both contexts point at the same emulator
+	m.state.queueOverview.queues = []kue.Queue{
+		{Name: "orders", Url: "http://localhost:4566/000000000000/orders", Context: "prod"},
+		{Name: "orders", Url: "http://localhost:4566/000000000000/orders", Context: "staging"},
+	}
+
+	if m.clientFor(m.state.queueOverview.queues[1].Key()) != m.contexts[1].client {
+		t.Error("Expected the staging client for a staging queue")
+	}
+	if m.clientFor(m.state.queueOverview.queues[0].Key()) != m.contexts[0].client {
+		t.Error("Expected the prod client for a prod queue")
+	}
+	if m.clientFor(kue.QueueKey{Url: "http://unknown/orders"}) != m.client {
+		t.Error("Expected the primary client for queues of no context")
+	}
+}
+
+func TestSameQueueUrlInTwoContexts(t *testing.T) {
+	m := newContextsModel(t)
+	url := "http://localhost:4566/000000000000/orders"
+	m.state.queueOverview.queues = []kue.Queue{
+		{Name: "orders", Url: url, Context: "prod"},
+		{Name: "orders", Url: url, Context: "staging"},
+	}
+	m, _ = m.loadAttributes(true)
+
+	m, _ = m.queueAttributesStreamed(messages.QueueAttributesStreamedMsg{
+		Results: []messages.QueueAttributesLoadedMsg{{Queue: kue.Queue{Name: "orders", Url: url, Context: "staging", ApproximateNumberOfMessages: "7"}}},
+	})
+	if q := m.state.queueOverview.queues; q[0].ApproximateNumberOfMessages != "" || q[1].ApproximateNumberOfMessages != "7" {
+		t.Errorf("Expected the attributes to fill in the staging queue only, got %+v", q)
+	}
+	if !m.state.queueOverview.pending[kue.QueueKey{Context: "prod", Url: url}] {
+		t.Error("Expected the prod queue to keep loading")
+	}
+}
+
+func TestContextColumn(t *testing.T) {
+	m := newTestModel()
+	for _, c := range m.overviewColumns() {
+		if c.id == "context" {
+			t.Fatal("Expected no context column for a single profile")
+		}
+	}
+
+	m = newContextsModel(t)
+	columns := m.overviewColumns()
+	if len(columns) < 2 || columns[1].id != "context" {
+		t.Fatalf("Expected the context column after the name, got %+v", columns)
+	}
+	if got := columns[1].value(m, kue.Queue{Context: "staging"}); got != "staging" {
+		t.Errorf("Expected the context name, got %q", got)
+	}
+}
+
+func TestQueuesLoadedWithFailedContext(t *testing.T) {
+	m := newContextsModel(t)
+	updated, _ := m.Update(messages.QueuesLoadedMsg{
+		Queues:        []kue.Queue{{Name: "orders", Url: "http://prod/orders", Context: "prod"}},
+		ContextErrors: map[string]error{"staging": errors.New("connection refused")},
+	})
+	m = updated.(model)
+
+	if m.error != "" || len(m.state.queueOverview.queues) != 1 {
+		t.Fatalf("Expected the queues of the other contexts to load, got error %q", m.error)
+	}
+	if !strings.Contains(m.statusMsg, "staging: connection refused") {
+		t.Errorf("Expected the failed context in the status, got %q", m.statusMsg)
+	}
+}
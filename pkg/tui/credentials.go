@@ -0,0 +1,252 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/tui/commands"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
+)
+
+// credentialsState holds the state for the expired credentials and MFA
+// prompt view, shown on top of the page the user was on.
+type credentialsState struct {
+	returnTo   page
+	err        error                     // error of the request that failed
+	problem    client.CredentialsProblem // why the credentials failed
+	mfaRequest *client.MFARequest        // pending MFA code request, nil when not prompting
+	tokenInput textinput.Model
+}
+
+// awsOptions returns the options for a profile and region, including the
+// configured endpoint and roles.
+func awsOptions(cfg config.Config, prompt *client.MFAPrompt, retries *client.RetryMonitor, profile, region string) client.Options {
+	return client.Options{
+		Profile:  profile,
+		Region:   region,
+		Endpoint: cfg.AWS.EndpointFor(profile),
+		Roles:    cfg.AWS.Roles[profile],
+		MFA:      prompt,
+		Timeouts: client.Timeouts{Default: cfg.AWS.Timeout, Operations: cfg.AWS.Timeouts},
+		Retry: client.RetryOptions{
+			MaxAttempts: cfg.AWS.Retry.MaxAttempts,
+			MaxBackoff:  cfg.AWS.Retry.MaxBackoff,
+			RateLimit:   cfg.AWS.Retry.RateLimit,
+		},
+		Retries:  retries,
+		ReadOnly: cfg.ReadOnly,
+	}
+}
+
+func initTokenInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "123456"
+	ti.CharLimit = 6
+	ti.Width = 10
+	ti.Validate = func(s string) error {
+		for _, r := range s {
+			if r < '0' || r > '9' {
+				return fmt.Errorf("MFA codes only contain digits")
+			}
+		}
+		return nil
+	}
+	return ti
+}
+
+// showCredentialsPage shows the credentials page on top of the current page,
+// remembering the page to resume afterwards. The requests of the page are
+// kept, a request waiting for an MFA code continues once it is entered.
+func (m model) showCredentialsPage() model {
+	if m.page != credentials {
+		m.state.credentials.returnTo = m.page
+		m.page = credentials
+	}
+	return m
+}
+
+// credentialsExpired shows the credentials page for a request that failed
+// because the credentials expired.
+func (m model) credentialsExpired(err error, problem client.CredentialsProblem) (model, tea.Cmd) {
+	m.loading = false
+	m.loadingMsg = ""
+	m.error = ""
+	m.state.credentials.err = err
+	m.state.credentials.problem = problem
+	return m.showCredentialsPage(), nil
+}
+
+// mfaRequested prompts for the MFA code credential resolution is waiting for.
+func (m model) mfaRequested(msg messages.MFARequestedMsg) (model, tea.Cmd) {
+	m.state.credentials.mfaRequest = &msg.Request
+	m.state.credentials.tokenInput = initTokenInput()
+	m = m.showCredentialsPage()
+	return m, tea.Batch(
+		m.state.credentials.tokenInput.Focus(),
+		commands.WaitForMFARequest(m.mfa),
+	)
+}
+
+// credentialsRefreshed replaces the session and resumes the page the
+// credentials expired on.
+func (m model) credentialsRefreshed(msg messages.CredentialsRefreshedMsg) (model, tea.Cmd) {
+	m.loading = false
+	m.loadingMsg = ""
+	if msg.Err != nil {
+		m.state.credentials.err = msg.Err
+		if problem := client.ClassifyCredentialsError(msg.Err); problem != client.CredentialsValid {
+			m.state.credentials.problem = problem
+		}
+		return m, nil
+	}
+
+	account := m.awsInfo.Account
+	if msg.Contexts != nil {
+		m = m.useContexts(msg.Contexts)
+	} else {
+		m = m.useSession(msg.Session)
+	}
+	m.awsInfo.Account = account
+	m.state.credentials.err = nil
+	m.page = m.state.credentials.returnTo
+	return m.resumePage()
+}
+
+// useSession replaces the clients with clients of the session.
+func (m model) useSession(session *client.Session) model {
+	m.session = session
+	m.client = session.SQS()
+	m.awsInfo = session.Info
+	m.metrics = nil
+	if m.alertRules.UsesMetric("oldest") {
+		m.metrics = session.CloudWatch()
+	}
+	return m.applyReadOnly()
+}
+
+// resumePage reloads the data of the current page after the credentials were
+// resolved again. Forms are not resubmitted, the overview is shown instead.
+// Requests still running with the expired credentials are discarded.
+func (m model) resumePage() (model, tea.Cmd) {
+	m = m.newPageContext()
+	switch m.page {
+	case queueDetails:
+		m.loading = true
+		m.loadingMsg = "Loading queue details..."
+		return m, m.loadQueueDetails(m.state.queueDetails.queue.Key())
+	case queueTopology:
+		if queues := deadLetterCandidates(m.state.queueOverview.queues); len(queues) > 0 {
+			m.loading = true
+			m.loadingMsg = "Loading dead-letter queue sources..."
+			return m, commands.LoadDeadLetterSources(m.pageContext, m.sqsClients(), queues, m.config.Overview.Concurrency)
+		}
+		return m, nil
+	case queueCreate, awsContext:
+		return m.QueueOverviewSwitchPage(nil)
+	case queueOverview:
+		m.loading = true
+		m.loadingMsg = "Loading queues..."
+		return m, m.loadQueues()
+	}
+	return m, nil
+}
+
+func (m model) CredentialsUpdate(msg tea.Msg) (model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if req := m.state.credentials.mfaRequest; req != nil {
+		switch keyMsg.Type {
+		case tea.KeyEnter:
+			code := m.state.credentials.tokenInput.Value()
+			if len(code) != 6 {
+				return m, nil
+			}
+			req.Respond(code)
+		case tea.KeyEsc, tea.KeyCtrlC:
+			req.Respond("")
+		default:
+			var cmd tea.Cmd
+			m.state.credentials.tokenInput, cmd = m.state.credentials.tokenInput.Update(msg)
+			return m, cmd
+		}
+
+		// Resume the page, the request waiting for the code continues
+		m.state.credentials.mfaRequest = nil
+		if m.state.credentials.err == nil {
+			m.page = m.state.credentials.returnTo
+		}
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.keys.View):
+		m.loading = true
+		m.loadingMsg = "Resolving credentials..."
+		if m.aggregating() {
+			return m, commands.RefreshContextCredentials(m.context, contextsOptions(m.config, m.mfa, m.retries))
+		}
+		return m, commands.RefreshCredentials(m.context, m.session.Options)
+	case key.Matches(keyMsg, m.keys.Quit):
+		m.error = fmt.Sprintf("Error: %v", m.state.credentials.err)
+		m.state.credentials.err = nil
+		m.page = m.state.credentials.returnTo
+	}
+	return m, nil
+}
+
+func (m model) CredentialsView() string {
+	titleStyle := styles.Warning().Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.MediumGray)
+	commandStyle := lipgloss.NewStyle().Foreground(styles.TextWhite).Bold(true)
+
+	var lines []string
+	if req := m.state.credentials.mfaRequest; req != nil {
+		lines = append(lines,
+			titleStyle.Render("MFA code required"),
+			"",
+			mutedStyle.Render("Enter the code of "+req.Serial+" to assume the role:"),
+			"",
+			m.state.credentials.tokenInput.View(),
+			"",
+			mutedStyle.Render("enter to continue • esc to cancel"),
+		)
+		return strings.Join(lines, "\n")
+	}
+
+	profile := m.awsInfo.Profile
+	switch m.state.credentials.problem {
+	case client.SSOLoginRequired:
+		lines = append(lines,
+			titleStyle.Render("SSO session expired"),
+			"",
+			mutedStyle.Render("Renew the session of profile "+profile+" in another terminal:"),
+			"",
+			commandStyle.Render("  "+client.SSOLoginCommand(profile)),
+			"",
+		)
+	default:
+		lines = append(lines,
+			titleStyle.Render("Credentials expired"),
+			"",
+			mutedStyle.Render("The credentials of profile "+profile+" expired. Refresh them, e.g. by assuming the role again,"),
+			mutedStyle.Render("or resolve the credentials again to assume configured roles."),
+			"",
+		)
+	}
+	if err := m.state.credentials.err; err != nil {
+		lines = append(lines, mutedStyle.Width(m.contentWidth()-4).Render(err.Error()), "")
+	}
+	lines = append(lines, mutedStyle.Render(m.keys.View.Help().Key+" to resolve the credentials again and resume • "+m.keys.Quit.Help().Key+" to go back"))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,217 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/commands"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
+)
+
+func TestCredentialsExpiredResumesPage(t *testing.T) {
+	m := newTestModel()
+	m = m.useSession(newTestSession(t, "prod"))
+	m.awsInfo.Account = "123456789012"
+	m.page = queueDetails
+	m.loading = true
+	m.state.queueDetails.queue = kue.Queue{Name: "orders", Url: "http://test/orders"}
+
+	expired := &smithy.GenericAPIError{Code: "ExpiredToken", Message: "The security token included in the request is expired"}
+	updated, _ := m.Update(messages.MessagesLoadedMsg{Err: expired})
+	m = updated.(model)
+
+	if m.page != credentials || m.loading || m.error != "" {
+		t.Fatalf("Expected the credentials page instead of an error, got page %d and %q", m.page, m.error)
+	}
+	if view := m.CredentialsView(); !strings.Contains(view, "Credentials expired") {
+		t.Errorf("Unexpected view:\n%s", view)
+	}
+
+	m, cmd := m.CredentialsUpdate(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.loading || cmd == nil {
+		t.Fatalf("Expected enter to resolve the credentials again")
+	}
+
+	updated, cmd = m.Update(messages.CredentialsRefreshedMsg{Session: newTestSession(t, "prod")})
+	m = updated.(model)
+	if m.page != queueDetails || m.state.queueDetails.queue.Name != "orders" || cmd == nil {
+		t.Errorf("Expected to resume the queue details, got page %d", m.page)
+	}
+	if m.awsInfo.Account != "123456789012" {
+		t.Errorf("Expected to keep the account ID, got %q", m.awsInfo.Account)
+	}
+}
+
+func TestSSOSessionExpired(t *testing.T) {
+	m := newTestModel()
+	m = m.useSession(newTestSession(t, "prod"))
+
+	updated, _ := m.Update(messages.QueuesLoadedMsg{Err: &smithy.GenericAPIError{Code: "UnauthorizedException", Message: "Session token not found or invalid"}})
+	m = updated.(model)
+	if m.page != queueOverview {
+		t.Fatalf("Expected errors other than expired credentials to be shown as usual, got page %d", m.page)
+	}
+
+	m, _ = m.credentialsExpired(errSSOExpired{}, client.SSOLoginRequired)
+	if view := m.CredentialsView(); !strings.Contains(view, "aws sso login --profile prod") {
+		t.Errorf("Expected the SSO login command, got:\n%s", view)
+	}
+
+	m, _ = m.CredentialsUpdate(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if m.page != queueOverview || m.error == "" {
+		t.Errorf("Expected to go back with the error, got page %d and %q", m.page, m.error)
+	}
+}
+
+type errSSOExpired struct{}
+
+func (errSSOExpired) Error() string { return "the SSO session has expired or is invalid" }
+
+func TestMFAPromptPage(t *testing.T) {
+	t.Setenv("AWS_CONFIG_FILE", "/nonexistent")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	m := newTestModel()
+	m.mfa = client.NewMFAPrompt()
+	m.loading = true
+	session, err := client.NewSession(context.Background(), client.Options{
+		Region:   "us-east-1",
+		Endpoint: "http://localhost:4566",
+		Roles:    []config.AssumeRole{{RoleArn: "arn:aws:iam::123456789012:role/kue", MFASerial: "arn:aws:iam::123456789012:mfa/alice"}},
+		MFA:      m.mfa,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	m = m.useSession(session)
+
+	// Credential resolution asks for the code, the STS request itself is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resolved := make(chan error, 1)
+	go func() { resolved <- session.Resolve(ctx) }()
+
+	updated, _ := m.Update(commands.WaitForMFARequest(m.mfa)())
+	m = updated.(model)
+	if m.page != credentials || !strings.Contains(m.View(), "arn:aws:iam::123456789012:mfa/alice") {
+		t.Fatalf("Expected the MFA prompt, got page %d", m.page)
+	}
+
+	for _, r := range "123456" {
+		m, _ = m.CredentialsUpdate(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m, _ = m.CredentialsUpdate(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.page != queueOverview || m.state.credentials.mfaRequest != nil {
+		t.Errorf("Expected to return to the overview, got page %d", m.page)
+	}
+
+	cancel()
+	if err := <-resolved; errors.Is(err, client.ErrMFACancelled) {
+		t.Errorf("Expected the code to be used, got %v", err)
+	}
+}
+
+func TestMFAPromptKeepsTheWaitingRequest(t *testing.T) {
+	t.Setenv("AWS_CONFIG_FILE", "/nonexistent")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	m := newTestModel()
+	m.mfa = client.NewMFAPrompt()
+	session, err := client.NewSession(context.Background(), client.Options{
+		Region:   "us-east-1",
+		Endpoint: "http://localhost:4566",
+		Roles:    []config.AssumeRole{{RoleArn: "arn:aws:iam::123456789012:role/kue", MFASerial: "arn:aws:iam::123456789012:mfa/alice"}},
+		MFA:      m.mfa,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	m = m.useSession(session).newPageContext()
+	m.loading = true
+	m.loadingMsg = "Loading queues..."
+	requestID := m.requestID
+
+	// The queues of the overview wait for the code while resolving credentials
+	ctx, cancel := context.WithCancel(m.pageContext)
+	defer cancel()
+	resolved := make(chan error, 1)
+	go func() { resolved <- session.Resolve(ctx) }()
+
+	updated, _ := m.Update(commands.WaitForMFARequest(m.mfa)())
+	m = updated.(model)
+	for _, r := range "123456" {
+		m, _ = m.CredentialsUpdate(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m, _ = m.CredentialsUpdate(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.pageContext.Err() != nil || m.requestID != requestID {
+		t.Fatal("Expected the request waiting for the code to continue")
+	}
+
+	updated, cmd := m.Update(messages.QueuesLoadedMsg{RequestID: requestID, Queues: []kue.Queue{{Name: "orders", Url: "http://test/orders"}}})
+	m = updated.(model)
+	if m.loading || len(m.state.queueOverview.queues) != 1 || cmd == nil {
+		t.Errorf("Expected the queues to be shown and refreshed, got loading %v and %d queues", m.loading, len(m.state.queueOverview.queues))
+	}
+
+	cancel()
+	<-resolved
+}
+
+func TestCredentialsExpiredDuringAttributeStream(t *testing.T) {
+	m := newTestModel()
+	m = m.useSession(newTestSession(t, "prod")).newPageContext()
+	queues := []kue.Queue{
+		{Name: "orders", Url: "http://test/orders"},
+		{Name: "payments", Url: "http://test/payments"},
+	}
+	updated, _ := m.Update(messages.QueuesLoadedMsg{RequestID: m.requestID, Queues: queues})
+	m = updated.(model)
+	if len(m.state.queueOverview.pending) != 2 {
+		t.Fatalf("Expected the attributes of both queues to load, got %d pending", len(m.state.queueOverview.pending))
+	}
+
+	// The credentials expire while the attributes stream in
+	expired := &smithy.GenericAPIError{Code: "ExpiredToken", Message: "The security token included in the request is expired"}
+	updated, _ = m.Update(messages.QueueAttributesStreamedMsg{
+		RequestID: m.requestID,
+		Results:   []messages.QueueAttributesLoadedMsg{{RequestID: m.requestID, Queue: queues[0], Err: expired}},
+	})
+	m = updated.(model)
+	if m.page != credentials {
+		t.Fatalf("Expected the credentials page, got page %d", m.page)
+	}
+
+	updated, _ = m.Update(messages.CredentialsRefreshedMsg{Session: newTestSession(t, "prod")})
+	m = updated.(model)
+	if m.page != queueOverview || len(m.state.queueOverview.pending) != 0 {
+		t.Fatalf("Expected the overview to resume without pending attributes, got page %d and %d pending", m.page, len(m.state.queueOverview.pending))
+	}
+
+	updated, _ = m.Update(messages.QueuesLoadedMsg{RequestID: m.requestID, Queues: queues})
+	m = updated.(model)
+	if len(m.state.queueOverview.pending) != 2 {
+		t.Fatalf("Expected the attributes of both queues to load again, got %d pending", len(m.state.queueOverview.pending))
+	}
+
+	loaded := []messages.QueueAttributesLoadedMsg{
+		{RequestID: m.requestID, Queue: kue.Queue{Name: "orders", Url: "http://test/orders", ApproximateNumberOfMessages: "3"}},
+		{RequestID: m.requestID, Queue: kue.Queue{Name: "payments", Url: "http://test/payments", ApproximateNumberOfMessages: "0"}},
+	}
+	updated, _ = m.Update(messages.QueueAttributesStreamedMsg{RequestID: m.requestID, Results: loaded, Done: true})
+	m = updated.(model)
+	if progress := m.attributesProgress(); progress != "" {
+		t.Errorf("Expected the attributes to finish loading, got %q", progress)
+	}
+	if m.state.queueOverview.queues[0].ApproximateNumberOfMessages != "3" {
+		t.Errorf("Expected the attributes to be shown, got %+v", m.state.queueOverview.queues[0])
+	}
+}
@@ -2,11 +2,57 @@ package tui
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
 )
 
 func formatHeader(projectName, programName, viewName string, awsInfo client.AWSInfo) string {
-	return fmt.Sprintf("%s/%s • %s • profile: %s | region: %s",
+	header := fmt.Sprintf("%s/%s • %s • profile: %s | region: %s",
 		projectName, programName, viewName, awsInfo.Profile, awsInfo.Region)
+	if awsInfo.Account != "" {
+		header += " | account: " + awsInfo.Account
+	}
+	return header
+}
+
+// formatContextsHeader formats the header when the overview shows the queues
+// of several contexts.
+func formatContextsHeader(projectName, programName, viewName string, contexts []string) string {
+	return fmt.Sprintf("%s/%s • %s • contexts: %s",
+		projectName, programName, viewName, strings.Join(contexts, ", "))
+}
+
+// renderEndpointBadge renders a badge for sessions that do not use the AWS
+// endpoints, so a local emulator is not mistaken for a real account.
+func renderEndpointBadge(awsInfo client.AWSInfo) string {
+	if awsInfo.Endpoint == "" {
+		return ""
+	}
+
+	badge := lipgloss.NewStyle().Bold(true).Padding(0, 1)
+	if client.IsLocalEndpoint(awsInfo.Endpoint) {
+		return badge.Inherit(styles.Highlight(styles.AccentColor)).Render("LOCAL") +
+			" " + client.EndpointHost(awsInfo.Endpoint)
+	}
+	return badge.Inherit(styles.Highlight(styles.WarningAmber)).Render("ENDPOINT") +
+		" " + client.EndpointHost(awsInfo.Endpoint)
+}
+
+// renderEndpointBadges renders the endpoint badge of the session, or of each
+// context shown together that does not use the AWS endpoints.
+func (m model) renderEndpointBadges() string {
+	if !m.aggregating() {
+		return renderEndpointBadge(m.awsInfo)
+	}
+	var badges []string
+	for _, c := range m.contexts {
+		if badge := renderEndpointBadge(c.session.Info); badge != "" {
+			badges = append(badges, c.name+" "+badge)
+		}
+	}
+	return strings.Join(badges, " • ")
 }
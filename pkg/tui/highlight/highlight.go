@@ -0,0 +1,167 @@
+// Package highlight provides line-based syntax highlighting for message bodies.
+package highlight
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
+)
+
+// Format is the detected syntax of a body.
+type Format int
+
+const (
+	Plain Format = iota
+	JSON
+	XML
+	YAML
+)
+
+var formatNames = map[Format]string{
+	Plain: "text",
+	JSON:  "json",
+	XML:   "xml",
+	YAML:  "yaml",
+}
+
+func (f Format) String() string {
+	return formatNames[f]
+}
+
+// Kind classifies a token.
+type Kind int
+
+const (
+	Text Kind = iota
+	Key
+	String
+	Number
+	Literal
+	Punctuation
+	Comment
+)
+
+// Token is a piece of a line with a single kind.
+type Token struct {
+	Text string
+	Kind Kind
+}
+
+// yamlLine matches a "key: value" or "- item" YAML line.
+var yamlLine = regexp.MustCompile(`^\s*(- )?[\w"'.-]+:(\s|$)`)
+
+// Detect guesses the format of body.
+func Detect(body string) Format {
+	trimmed := strings.TrimSpace(body)
+	switch {
+	case trimmed == "":
+		return Plain
+	case (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid([]byte(trimmed)):
+		return JSON
+	case trimmed[0] == '<' && strings.HasSuffix(trimmed, ">"):
+		return XML
+	case strings.HasPrefix(trimmed, "---"):
+		return YAML
+	}
+
+	for _, line := range strings.Split(trimmed, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if yamlLine.MatchString(line) {
+			return YAML
+		}
+		break
+	}
+
+	return Plain
+}
+
+// Tokenize splits a single line into highlighted tokens.
+func Tokenize(format Format, line string) []Token {
+	switch format {
+	case JSON:
+		return tokenizeJSON(line)
+	case XML:
+		return tokenizeXML(line)
+	case YAML:
+		return tokenizeYAML(line)
+	default:
+		return []Token{{Text: line, Kind: Text}}
+	}
+}
+
+// Style returns the style used to render tokens of the given kind.
+func Style(kind Kind) lipgloss.Style {
+	switch kind {
+	case Key:
+		return lipgloss.NewStyle().Foreground(styles.SyntaxKey)
+	case String:
+		return lipgloss.NewStyle().Foreground(styles.SyntaxString)
+	case Number:
+		return lipgloss.NewStyle().Foreground(styles.SyntaxNumber)
+	case Literal:
+		return lipgloss.NewStyle().Foreground(styles.SyntaxLiteral)
+	case Punctuation:
+		return lipgloss.NewStyle().Foreground(styles.SyntaxPunctuation)
+	case Comment:
+		return lipgloss.NewStyle().Foreground(styles.SyntaxComment).Italic(true)
+	default:
+		return lipgloss.NewStyle()
+	}
+}
+
+// Line renders a line with syntax highlighting.
+func Line(format Format, line string) string {
+	var b strings.Builder
+	for _, t := range Tokenize(format, line) {
+		b.WriteString(Style(t.Kind).Render(t.Text))
+	}
+	return b.String()
+}
+
+// appendToken appends text to tokens, merging it with the previous token when
+// both have the same kind.
+func appendToken(tokens []Token, text string, kind Kind) []Token {
+	if text == "" {
+		return tokens
+	}
+	if n := len(tokens); n > 0 && tokens[n-1].Kind == kind {
+		tokens[n-1].Text += text
+		return tokens
+	}
+	return append(tokens, Token{Text: text, Kind: kind})
+}
+
+// scalarKind classifies an unquoted scalar value.
+func scalarKind(value string) Kind {
+	switch strings.ToLower(value) {
+	case "true", "false", "null", "~", "yes", "no":
+		return Literal
+	}
+	if isNumber(value) {
+		return Number
+	}
+	return String
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	digits := 0
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case (r == '-' || r == '+') && i == 0:
+		case r == '.' || r == 'e' || r == 'E' || r == '-' || r == '+':
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
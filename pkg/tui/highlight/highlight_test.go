@@ -0,0 +1,100 @@
+package highlight
+
+import (
+	"strings"
+	"testing"
+)
+
+// joinTokens concatenates token texts, which must always equal the input line.
+func joinTokens(tokens []Token) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteString(t.Text)
+	}
+	return b.String()
+}
+
+func kindOf(tokens []Token, text string) (Kind, bool) {
+	for _, t := range tokens {
+		if strings.TrimSpace(t.Text) == text {
+			return t.Kind, true
+		}
+	}
+	return Text, false
+}
+
+func TestDetect(t *testing.T) {
+	tests := map[string]Format{
+		`{"key": "value"}`:         JSON,
+		`[1, 2]`:                   JSON,
+		`<order id="1"></order>`:   XML,
+		"---\nkey: value":          YAML,
+		"order:\n  status: FAILED": YAML,
+		"# comment\nkey: value":    YAML,
+		"just some text":           Plain,
+		`{"broken": `:              Plain,
+	}
+
+	for body, want := range tests {
+		if got := Detect(body); got != want {
+			t.Errorf("Detect(%q) = %v, want %v", body, got, want)
+		}
+	}
+}
+
+func TestTokenizeJSON(t *testing.T) {
+	line := `  "status": "FAILED", "count": 3, "ok": true`
+	tokens := Tokenize(JSON, line)
+
+	if joinTokens(tokens) != line {
+		t.Fatalf("Tokens do not reconstruct the line: %q", joinTokens(tokens))
+	}
+	if kind, _ := kindOf(tokens, `"status"`); kind != Key {
+		t.Errorf("Expected key kind for \"status\", got %v", kind)
+	}
+	if kind, _ := kindOf(tokens, `"FAILED"`); kind != String {
+		t.Errorf("Expected string kind for \"FAILED\", got %v", kind)
+	}
+	if kind, _ := kindOf(tokens, `3`); kind != Number {
+		t.Errorf("Expected number kind for 3, got %v", kind)
+	}
+	if kind, _ := kindOf(tokens, `true`); kind != Literal {
+		t.Errorf("Expected literal kind for true, got %v", kind)
+	}
+}
+
+func TestTokenizeXML(t *testing.T) {
+	line := `<order id="1"><!-- note -->text</order>`
+	tokens := Tokenize(XML, line)
+
+	if joinTokens(tokens) != line {
+		t.Fatalf("Tokens do not reconstruct the line: %q", joinTokens(tokens))
+	}
+	if kind, _ := kindOf(tokens, "order"); kind != Key {
+		t.Errorf("Expected key kind for tag name, got %v", kind)
+	}
+	if kind, _ := kindOf(tokens, `"1"`); kind != String {
+		t.Errorf("Expected string kind for attribute value, got %v", kind)
+	}
+	if kind, _ := kindOf(tokens, "<!-- note -->"); kind != Comment {
+		t.Errorf("Expected comment kind, got %v", kind)
+	}
+}
+
+func TestTokenizeYAML(t *testing.T) {
+	line := `  - status: "FAILED" # retried`
+	tokens := Tokenize(YAML, line)
+
+	if joinTokens(tokens) != line {
+		t.Fatalf("Tokens do not reconstruct the line: %q", joinTokens(tokens))
+	}
+	if kind, _ := kindOf(tokens, "status"); kind != Key {
+		t.Errorf("Expected key kind, got %v", kind)
+	}
+	if kind, _ := kindOf(tokens, `"FAILED"`); kind != String {
+		t.Errorf("Expected string kind, got %v", kind)
+	}
+	if kind, _ := kindOf(tokens, "# retried"); kind != Comment {
+		t.Errorf("Expected comment kind, got %v", kind)
+	}
+}
@@ -0,0 +1,54 @@
+package highlight
+
+import "strings"
+
+// tokenizeJSON highlights one line of indented JSON.
+func tokenizeJSON(line string) []Token {
+	var tokens []Token
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == '"':
+			end := closingQuote(line, i)
+			text := line[i:end]
+			kind := String
+			if strings.HasPrefix(strings.TrimLeft(line[end:], " "), ":") {
+				kind = Key
+			}
+			tokens = appendToken(tokens, text, kind)
+			i = end
+		case strings.ContainsRune("{}[],:", rune(c)):
+			tokens = appendToken(tokens, string(c), Punctuation)
+			i++
+		case c == ' ' || c == '\t':
+			tokens = appendToken(tokens, string(c), Text)
+			i++
+		default:
+			end := i
+			for end < len(line) && !strings.ContainsRune(" \t{}[],:\"", rune(line[end])) {
+				end++
+			}
+			if end == i {
+				end++
+			}
+			word := line[i:end]
+			tokens = appendToken(tokens, word, scalarKind(word))
+			i = end
+		}
+	}
+	return tokens
+}
+
+// closingQuote returns the index just past the string starting at start,
+// honouring backslash escapes. Unterminated strings run to the end of line.
+func closingQuote(line string, start int) int {
+	for i := start + 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(line)
+}
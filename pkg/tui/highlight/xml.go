@@ -0,0 +1,81 @@
+package highlight
+
+import "strings"
+
+// tokenizeXML highlights one line of XML. Tag names are rendered as keys,
+// attribute names as literals and attribute values as strings.
+func tokenizeXML(line string) []Token {
+	var tokens []Token
+	for i := 0; i < len(line); {
+		switch {
+		case strings.HasPrefix(line[i:], "<!--"):
+			end := strings.Index(line[i:], "-->")
+			if end < 0 {
+				end = len(line) - i
+			} else {
+				end += len("-->")
+			}
+			tokens = appendToken(tokens, line[i:i+end], Comment)
+			i += end
+		case line[i] == '<':
+			i = tokenizeTag(line, i, &tokens)
+		default:
+			end := strings.IndexByte(line[i:], '<')
+			if end < 0 {
+				end = len(line) - i
+			}
+			tokens = appendToken(tokens, line[i:i+end], Text)
+			i += end
+		}
+	}
+	return tokens
+}
+
+// tokenizeTag highlights a tag starting at i and returns the index after it.
+func tokenizeTag(line string, i int, tokens *[]Token) int {
+	start := i
+	i++
+	for i < len(line) && (line[i] == '/' || line[i] == '?' || line[i] == '!') {
+		i++
+	}
+	*tokens = appendToken(*tokens, line[start:i], Punctuation)
+
+	nameEnd := i
+	for nameEnd < len(line) && !strings.ContainsRune(" \t/>?", rune(line[nameEnd])) {
+		nameEnd++
+	}
+	*tokens = appendToken(*tokens, line[i:nameEnd], Key)
+	i = nameEnd
+
+	for i < len(line) {
+		c := line[i]
+		switch {
+		case c == '>':
+			*tokens = appendToken(*tokens, ">", Punctuation)
+			return i + 1
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(line[i+1:], c)
+			if end < 0 {
+				end = len(line) - i - 1
+			} else {
+				end++
+			}
+			*tokens = appendToken(*tokens, line[i:i+end+1], String)
+			i += end + 1
+		case c == '=' || c == '/' || c == '?':
+			*tokens = appendToken(*tokens, string(c), Punctuation)
+			i++
+		case c == ' ' || c == '\t':
+			*tokens = appendToken(*tokens, string(c), Text)
+			i++
+		default:
+			end := i
+			for end < len(line) && !strings.ContainsRune(" \t=/>?", rune(line[end])) {
+				end++
+			}
+			*tokens = appendToken(*tokens, line[i:end], Literal)
+			i = end
+		}
+	}
+	return i
+}
@@ -0,0 +1,92 @@
+package highlight
+
+import (
+	"regexp"
+	"strings"
+)
+
+// yamlKey matches the optional list marker and key of a YAML mapping line.
+var yamlKey = regexp.MustCompile(`^(\s*)(- )?([^:#]+?)(:)(\s|$)`)
+
+// tokenizeYAML highlights one line of YAML.
+func tokenizeYAML(line string) []Token {
+	var tokens []Token
+
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(trimmed, "#"):
+		return appendToken(tokens, line, Comment)
+	case trimmed == "---" || trimmed == "...":
+		return appendToken(tokens, line, Punctuation)
+	}
+
+	rest := line
+	if m := yamlKey.FindStringSubmatchIndex(line); m != nil {
+		tokens = appendToken(tokens, line[m[2]:m[3]], Text)
+		if m[4] >= 0 {
+			tokens = appendToken(tokens, line[m[4]:m[5]], Punctuation)
+		}
+		tokens = appendToken(tokens, line[m[6]:m[7]], Key)
+		tokens = appendToken(tokens, line[m[8]:m[9]], Punctuation)
+		rest = line[m[9]:]
+	} else {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		tokens = appendToken(tokens, line[:indent], Text)
+		rest = line[indent:]
+		if strings.HasPrefix(rest, "- ") {
+			tokens = appendToken(tokens, "- ", Punctuation)
+			rest = rest[2:]
+		}
+	}
+
+	return append(tokens, tokenizeYAMLValue(rest)...)
+}
+
+// tokenizeYAMLValue highlights a scalar value with an optional trailing comment.
+func tokenizeYAMLValue(value string) []Token {
+	var tokens []Token
+
+	comment := ""
+	if idx := commentIndex(value); idx >= 0 {
+		value, comment = value[:idx], value[idx:]
+	}
+
+	leading := value[:len(value)-len(strings.TrimLeft(value, " "))]
+	scalar := strings.TrimSpace(value)
+	trailing := value[len(leading)+len(scalar):]
+
+	tokens = appendToken(tokens, leading, Text)
+	switch {
+	case scalar == "":
+	case strings.HasPrefix(scalar, `"`) || strings.HasPrefix(scalar, "'"):
+		tokens = appendToken(tokens, scalar, String)
+	case strings.ContainsAny(scalar[:1], "{}[]|>&*"):
+		tokens = appendToken(tokens, scalar, Punctuation)
+	default:
+		tokens = appendToken(tokens, scalar, scalarKind(scalar))
+	}
+	tokens = appendToken(tokens, trailing, Text)
+	tokens = appendToken(tokens, comment, Comment)
+
+	return tokens
+}
+
+// commentIndex returns the index of the space preceding a trailing comment,
+// ignoring '#' inside quoted strings, or -1 if there is none.
+func commentIndex(value string) int {
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && i > 0 && value[i-1] == ' ':
+			return i - 1
+		}
+	}
+	return -1
+}
@@ -16,27 +16,47 @@ import (
 // queueMessageCreateState holds the state for message creation.
 type queueMessageCreateState struct {
 	queueName string
-	queueUrl  string
+	queue     kue.QueueKey
 	isFifo    bool
 	textarea  textarea.Model
 	selected  int // 0 = textarea, 1 = cancel, 2 = submit
 }
 
-const (
-	messageLeftPanelWidth  = (contentWidth - 1) / 2                   // Split evenly, 1 for divider
-	messageRightPanelWidth = contentWidth - messageLeftPanelWidth - 1 // Remainder goes to right panel
-	messageTextareaWidth   = messageRightPanelWidth - 4               // Panel padding(4)
-	messageTextareaHeight  = contentHeight - 8                        // Height for textarea minus headers and panel padding
-)
+// messageLeftPanelWidth returns the width of the queue panel, the content is
+// split evenly with 1 for the divider.
+func (m model) messageLeftPanelWidth() int {
+	return (m.contentWidth() - 1) / 2
+}
+
+// messageRightPanelWidth returns the remainder of the width for the body panel.
+func (m model) messageRightPanelWidth() int {
+	return m.contentWidth() - m.messageLeftPanelWidth() - 1
+}
+
+// messageTextareaWidth returns the width of the textarea inside the panel
+// padding.
+func (m model) messageTextareaWidth() int {
+	return m.messageRightPanelWidth() - 4
+}
+
+// messageTextareaHeight returns the height of the textarea minus headers and
+// panel padding.
+func (m model) messageTextareaHeight() int {
+	return m.contentHeight() - 8
+}
 
 func (m model) QueueMessageCreateSwitchPage(msg tea.Msg) (model, tea.Cmd) {
+	if reason := m.refuseReadOnly(m.state.queueMessageCreate.queue); reason != "" {
+		m.error = reason
+		return m, nil
+	}
 	m.error = ""
 
 	ta := textarea.New()
 	ta.Placeholder = "Enter message body (JSON or plain text)..."
 	ta.Focus()
-	ta.SetWidth(messageTextareaWidth)
-	ta.SetHeight(messageTextareaHeight)
+	ta.SetWidth(m.messageTextareaWidth())
+	ta.SetHeight(m.messageTextareaHeight())
 	ta.CharLimit = 262144
 
 	m.state.queueMessageCreate.textarea = ta
@@ -45,10 +65,10 @@ func (m model) QueueMessageCreateSwitchPage(msg tea.Msg) (model, tea.Cmd) {
 }
 
 func (m model) QueueMessageCreateView() string {
-	const (
-		leftContentWidth  = messageLeftPanelWidth - 4  // Account for padding (4)
-		rightContentWidth = messageRightPanelWidth - 4 // Account for padding (4)
-		labelWidth        = 14
+	const labelWidth = 14
+	var (
+		leftContentWidth  = m.messageLeftPanelWidth() - 4  // Account for padding (4)
+		rightContentWidth = m.messageRightPanelWidth() - 4 // Account for padding (4)
 		valueWidth        = leftContentWidth - labelWidth - 2
 	)
 
@@ -131,22 +151,22 @@ func (m model) QueueMessageCreateView() string {
 	// Combine top and bottom with bottom aligned to the bottom
 	leftPanelInner := lipgloss.JoinVertical(lipgloss.Left,
 		topContent,
-		lipgloss.PlaceVertical(contentHeight-lipgloss.Height(topContent), lipgloss.Bottom, bottomContent),
+		lipgloss.PlaceVertical(m.contentHeight()-lipgloss.Height(topContent), lipgloss.Bottom, bottomContent),
 	)
 
 	leftPanelStyle := lipgloss.NewStyle().
 		PaddingLeft(2).
 		PaddingRight(2).
-		Width(messageLeftPanelWidth).
-		Height(contentHeight)
+		Width(m.messageLeftPanelWidth()).
+		Height(m.contentHeight())
 
 	leftPanel := leftPanelStyle.Render(leftPanelInner)
 
 	// Vertical divider - create full height line
 	var dividerLines string
-	for i := 0; i < contentHeight; i++ {
+	for i := 0; i < m.contentHeight(); i++ {
 		dividerLines += "│"
-		if i < contentHeight-1 {
+		if i < m.contentHeight()-1 {
 			dividerLines += "\n"
 		}
 	}
@@ -168,8 +188,8 @@ func (m model) QueueMessageCreateView() string {
 	rightPanelStyle := lipgloss.NewStyle().
 		PaddingLeft(2).
 		PaddingRight(2).
-		Width(messageRightPanelWidth).
-		Height(contentHeight)
+		Width(m.messageRightPanelWidth()).
+		Height(m.contentHeight())
 
 	rightContent := lipgloss.JoinVertical(lipgloss.Left,
 		bodyHeaderStyle.Render("Message Body"),
@@ -184,7 +204,7 @@ func (m model) QueueMessageCreateView() string {
 		rightPanel,
 	)
 
-	return lipgloss.PlaceHorizontal(contentWidth, lipgloss.Center, content)
+	return lipgloss.PlaceHorizontal(m.contentWidth(), lipgloss.Center, content)
 }
 
 func (m model) QueueMessageCreateUpdate(msg tea.Msg) (model, tea.Cmd) {
@@ -228,13 +248,13 @@ func (m model) QueueMessageCreateUpdate(msg tea.Msg) (model, tea.Cmd) {
 				m.loadingMsg = "Sending message..."
 
 				input := kue.SendMessageInput{
-					QueueUrl:    m.state.queueMessageCreate.queueUrl,
+					QueueUrl:    m.state.queueMessageCreate.queue.Url,
 					MessageBody: body,
 				}
 				if m.state.queueMessageCreate.isFifo {
 					input.MessageGroupId = "default"
 				}
-				return m, commands.SendMessage(m.context, m.client, input)
+				return m, commands.SendMessage(m.context, m.clientFor(m.state.queueMessageCreate.queue), m.auditFor(m.state.queueMessageCreate.queue), input)
 			}
 		}
 
@@ -14,17 +14,31 @@ import (
 
 // queueMessageDeleteState holds the state for message deletion confirmation.
 type queueMessageDeleteState struct {
-	message   kue.Message
-	messages  []kue.Message
-	queueUrl  string
-	queueName string
-	selected  int // 0 = no, 1 = yes
+	message    kue.Message
+	messages   []kue.Message
+	queue      kue.QueueKey
+	queueName  string
+	selected   int // 0 = no, 1 = yes
+	protection protection
 }
 
 func (m model) QueueMessageDeleteSwitchPage(msg tea.Msg) (model, tea.Cmd) {
+	if reason := m.refuseReadOnly(m.state.queueMessageDelete.queue); reason != "" {
+		m.error = reason
+		return m, nil
+	}
 	m.error = ""
 	m.state.queueMessageDelete.selected = 0
-	return m.SwitchPage(queueMessageDelete), nil
+	// Single messages are confirmed with the buttons, even in protected queues
+	m.state.queueMessageDelete.protection = protection{}
+	if len(m.state.queueMessageDelete.messages) > 1 {
+		queue := kue.Queue{Name: m.state.queueMessageDelete.queueName, Url: m.state.queueMessageDelete.queue.Url, Context: m.state.queueMessageDelete.queue.Context}
+		if m.state.queueDetails.queue.Key() == queue.Key() {
+			queue = m.state.queueDetails.queue
+		}
+		m.state.queueMessageDelete.protection = m.protectionFor(queue)
+	}
+	return m.SwitchPage(queueMessageDelete), m.state.queueMessageDelete.protection.init()
 }
 
 func (m model) QueueMessageDeleteView() string {
@@ -42,6 +56,18 @@ func (m model) QueueMessageDeleteView() string {
 	}
 	queueName := styles.Bold.Render(m.state.queueMessageDelete.queueName)
 
+	if m.state.queueMessageDelete.protection.required() {
+		dialog := lipgloss.JoinVertical(lipgloss.Center,
+			"warning: message deletion",
+			"",
+			"are you sure you want to delete: "+messageDisplay,
+			"from queue: "+queueName+" ?",
+			"",
+			m.state.queueMessageDelete.protection.view(),
+		)
+		return lipgloss.Place(m.contentWidth(), m.contentHeight()-2, lipgloss.Center, lipgloss.Center, dialog)
+	}
+
 	confirm := "yes"
 	abort := "no"
 
@@ -62,7 +88,7 @@ func (m model) QueueMessageDeleteView() string {
 		"",
 		buttons,
 	)
-	return lipgloss.Place(contentWidth, contentHeight-2, lipgloss.Center, lipgloss.Center, dialog)
+	return lipgloss.Place(m.contentWidth(), m.contentHeight()-2, lipgloss.Center, lipgloss.Center, dialog)
 }
 
 func (m model) switchMessageDeleteOption() (model, tea.Cmd) {
@@ -70,9 +96,29 @@ func (m model) switchMessageDeleteOption() (model, tea.Cmd) {
 	return m, nil
 }
 
+// queueMessageDeleteGoBack returns to the page the deletion was started from.
+func (m model) queueMessageDeleteGoBack(msg tea.Msg) (model, tea.Cmd) {
+	if m.previous == queueMessageDetails {
+		return m.QueueMessageDetailsSwitchPage(msg)
+	}
+	return m.QueueDetailsGoBack(msg)
+}
+
 func (m model) QueueMessageDeleteUpdate(msg tea.Msg) (model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if m.state.queueMessageDelete.protection.required() {
+		var result confirmation
+		m.state.queueMessageDelete.protection, result, cmd = m.state.queueMessageDelete.protection.update(msg)
+		switch result {
+		case confirmAccepted:
+			return m.deleteMessages()
+		case confirmCancelled:
+			return m.queueMessageDeleteGoBack(msg)
+		}
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
@@ -82,37 +128,39 @@ func (m model) QueueMessageDeleteUpdate(msg tea.Msg) (model, tea.Cmd) {
 			m, cmd = m.switchMessageDeleteOption()
 		case key.Matches(msg, m.keys.View):
 			if m.state.queueMessageDelete.selected == 0 {
-				if m.previous == queueMessageDetails {
-					return m.QueueMessageDetailsSwitchPage(msg)
-				}
-				return m.QueueDetailsGoBack(msg)
-			}
-			m.loading = true
-			numMessages := len(m.state.queueMessageDelete.messages)
-			if numMessages == 1 {
-				m.loadingMsg = "Deleting message..."
-				return m, commands.DeleteMessage(
-					m.context,
-					m.client,
-					m.state.queueMessageDelete.queueUrl,
-					m.state.queueMessageDelete.messages[0].ReceiptHandle,
-				)
+				return m.queueMessageDeleteGoBack(msg)
 			}
-			m.loadingMsg = fmt.Sprintf("Deleting %d messages...", numMessages)
-			return m, commands.DeleteMessages(
-				m.context,
-				m.client,
-				m.state.queueMessageDelete.queueUrl,
-				m.state.queueMessageDelete.messages,
-			)
+			return m.deleteMessages()
 		case key.Matches(msg, m.keys.Quit):
 			m.state.queueMessageDelete.selected = 0
-			if m.previous == queueMessageDetails {
-				return m.QueueMessageDetailsSwitchPage(msg)
-			}
-			return m.QueueDetailsGoBack(msg)
+			return m.queueMessageDeleteGoBack(msg)
 		}
 	}
 
 	return m, cmd
 }
+
+func (m model) deleteMessages() (model, tea.Cmd) {
+	m.loading = true
+	numMessages := len(m.state.queueMessageDelete.messages)
+	if numMessages == 1 {
+		m.loadingMsg = "Deleting message..."
+		return m, commands.DeleteMessage(
+			m.context,
+			m.clientFor(m.state.queueMessageDelete.queue),
+			m.auditFor(m.state.queueMessageDelete.queue),
+			m.trash,
+			m.state.queueMessageDelete.queue.Url,
+			m.state.queueMessageDelete.messages[0],
+		)
+	}
+	m.loadingMsg = fmt.Sprintf("Deleting %d messages...", numMessages)
+	return m, commands.DeleteMessages(
+		m.context,
+		m.clientFor(m.state.queueMessageDelete.queue),
+		m.auditFor(m.state.queueMessageDelete.queue),
+		m.trash,
+		m.state.queueMessageDelete.queue.Url,
+		m.state.queueMessageDelete.messages,
+	)
+}
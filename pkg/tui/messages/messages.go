@@ -2,25 +2,45 @@
 package messages
 
 import (
+	"time"
+
+	"github.com/kontrolplane/kue/pkg/audit"
+	"github.com/kontrolplane/kue/pkg/client"
 	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/trash"
 )
 
-// QueuesLoadedMsg is sent when the queue list has been loaded.
+// QueuesLoadedMsg is sent when the queue list has been loaded. The queues
+// only have their URL, name and context, their attributes are loaded on
+// demand.
 type QueuesLoadedMsg struct {
-	Queues []kue.Queue
-	Err    error
+	RequestID     uint64
+	Queues        []kue.Queue
+	ContextErrors map[string]error // contexts that failed to load, keyed by context name
+	Err           error
+}
+
+// QueueAttributesStreamedMsg is sent with the queue attributes that loaded
+// since the previous message of the same stream.
+type QueueAttributesStreamedMsg struct {
+	RequestID  uint64
+	Results    []QueueAttributesLoadedMsg
+	Attributes <-chan QueueAttributesLoadedMsg // the stream the results were read from
+	Done       bool                            // the attributes of all queues have loaded
 }
 
 // QueueAttributesLoadedMsg is sent when queue attributes have been fetched.
 type QueueAttributesLoadedMsg struct {
-	Queue kue.Queue
-	Err   error
+	RequestID uint64
+	Queue     kue.Queue
+	Err       error
 }
 
 // MessagesLoadedMsg is sent when queue messages have been loaded.
 type MessagesLoadedMsg struct {
-	Messages []kue.Message
-	Err      error
+	RequestID uint64
+	Messages  []kue.Message
+	Err       error
 }
 
 // QueueCreatedMsg is sent when a queue has been created.
@@ -79,3 +99,172 @@ type ClipboardCopiedMsg struct {
 
 // StatusClearMsg is sent to clear the transient status message.
 type StatusClearMsg struct{}
+
+// BellStoppedMsg is sent when the frames stop ringing the terminal bell.
+type BellStoppedMsg struct{}
+
+// StateSavedMsg is sent after the persisted UI state has been written.
+type StateSavedMsg struct {
+	Err error
+}
+
+// OldestMessageAgesLoadedMsg is sent when the age of the oldest message of
+// each queue has been read from CloudWatch.
+type OldestMessageAgesLoadedMsg struct {
+	RequestID uint64
+	Ages      map[kue.QueueKey]time.Duration
+	Errs      map[kue.QueueKey]error // queues that failed
+}
+
+// AlertsNotifiedMsg is sent after new alerts have been announced.
+type AlertsNotifiedMsg struct {
+	Err error
+}
+
+// DeadLetterSourcesLoadedMsg is sent when the source queues of the
+// dead-letter queues have been listed.
+type DeadLetterSourcesLoadedMsg struct {
+	RequestID uint64
+	Sources   map[kue.QueueKey][]string // source queue URLs of each dead-letter queue
+	Errs      map[kue.QueueKey]error    // queues that failed
+}
+
+// AWSContextSwitchedMsg is sent when the session for another AWS profile or
+// region has been created.
+type AWSContextSwitchedMsg struct {
+	Options client.Options
+	Session *client.Session
+	Err     error
+}
+
+// AccountIDLoadedMsg is sent when the account ID of the credentials of a
+// session has been resolved.
+type AccountIDLoadedMsg struct {
+	Session *client.Session
+	Account string
+	Err     error
+}
+
+// MFARequestedMsg is sent when credential resolution needs an MFA code.
+type MFARequestedMsg struct {
+	Request client.MFARequest
+}
+
+// AuditLogLoadedMsg is sent when the entries of the audit log have been read.
+type AuditLogLoadedMsg struct {
+	Entries []audit.Entry
+	Err     error
+}
+
+// TrashLoadedMsg is sent when the messages in the trash have been read.
+type TrashLoadedMsg struct {
+	Entries []trash.Entry
+	Err     error
+}
+
+// MessageRestoredMsg is sent when a message of the trash was sent again.
+type MessageRestoredMsg struct {
+	Err error
+}
+
+// TrashPurgedMsg is sent when the trash was emptied.
+type TrashPurgedMsg struct {
+	Err error
+}
+
+// CooldownTickMsg is sent every second while the confirmation of a protected
+// queue is cooling down.
+type CooldownTickMsg struct{}
+
+// RetryStatusMsg is sent when calls start or stop being retried.
+type RetryStatusMsg struct {
+	Status client.RetryStatus
+}
+
+// CredentialsRefreshedMsg is sent when the credentials have been resolved
+// again after they expired.
+type CredentialsRefreshedMsg struct {
+	Session  *client.Session
+	Contexts map[string]*client.Session // sessions keyed by context name when aggregating contexts
+	Err      error
+}
+
+// ContextsOpenedMsg is sent when the sessions of the configured contexts have
+// been created.
+type ContextsOpenedMsg struct {
+	Sessions map[string]*client.Session // keyed by context name
+	Err      error
+}
+
+// RequestIDOf returns the ID of the page request a message is the result of.
+// Results of requests that are not scoped to a page have no request ID.
+func RequestIDOf(msg any) (uint64, bool) {
+	switch msg := msg.(type) {
+	case QueuesLoadedMsg:
+		return msg.RequestID, true
+	case QueueAttributesStreamedMsg:
+		return msg.RequestID, true
+	case QueueAttributesLoadedMsg:
+		return msg.RequestID, true
+	case MessagesLoadedMsg:
+		return msg.RequestID, true
+	case OldestMessageAgesLoadedMsg:
+		return msg.RequestID, true
+	case DeadLetterSourcesLoadedMsg:
+		return msg.RequestID, true
+	}
+	return 0, false
+}
+
+// ErrorOf returns the error of a message reporting the result of an AWS
+// request, or nil.
+func ErrorOf(msg any) error {
+	switch msg := msg.(type) {
+	case QueuesLoadedMsg:
+		return msg.Err
+	case QueueAttributesLoadedMsg:
+		return msg.Err
+	case QueueAttributesStreamedMsg:
+		for _, r := range msg.Results {
+			if r.Err != nil {
+				return r.Err
+			}
+		}
+	case MessagesLoadedMsg:
+		return msg.Err
+	case QueueCreatedMsg:
+		return msg.Err
+	case QueueDeletedMsg:
+		return msg.Err
+	case MessageDeletedMsg:
+		return msg.Err
+	case MessageCreatedMsg:
+		return msg.Err
+	case QueueRedriveStartedMsg:
+		return msg.Err
+	case QueueRedriveStatusMsg:
+		return msg.Err
+	case QueuePurgedMsg:
+		return msg.Err
+	case OldestMessageAgesLoadedMsg:
+		return firstError(msg.Errs)
+	case DeadLetterSourcesLoadedMsg:
+		return firstError(msg.Errs)
+	}
+	return nil
+}
+
+// firstError returns the error of the first queue by context and URL, so the
+// same error is reported however the queues were loaded.
+func firstError(errs map[kue.QueueKey]error) error {
+	var first *kue.QueueKey
+	for key := range errs {
+		if first == nil || key.Context < first.Context || key.Context == first.Context && key.Url < first.Url {
+			first = &key
+		}
+	}
+	if first == nil {
+		return nil
+	}
+	return errs[*first]
+}
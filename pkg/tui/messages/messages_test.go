@@ -0,0 +1,27 @@
+package messages
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+func TestErrorOfReportsTheFirstQueue(t *testing.T) {
+	errs := map[kue.QueueKey]error{
+		{Context: "staging", Url: "http://test/a"}: errors.New("staging a"),
+		{Context: "prod", Url: "http://test/b"}:    errors.New("prod b"),
+		{Context: "prod", Url: "http://test/a"}:    errors.New("prod a"),
+	}
+	for range 10 {
+		if err := ErrorOf(OldestMessageAgesLoadedMsg{Errs: errs}); err == nil || err.Error() != "prod a" {
+			t.Fatalf("Expected the error of the first queue, got %v", err)
+		}
+		if err := ErrorOf(DeadLetterSourcesLoadedMsg{Errs: errs}); err == nil || err.Error() != "prod a" {
+			t.Fatalf("Expected the error of the first queue, got %v", err)
+		}
+	}
+	if err := ErrorOf(OldestMessageAgesLoadedMsg{}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
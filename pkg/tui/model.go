@@ -3,10 +3,17 @@ package tui
 import (
 	"context"
 
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 
+	"github.com/kontrolplane/kue/pkg/alert"
+	"github.com/kontrolplane/kue/pkg/audit"
 	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/decode"
+	"github.com/kontrolplane/kue/pkg/history"
 	keys "github.com/kontrolplane/kue/pkg/keys"
+	"github.com/kontrolplane/kue/pkg/trash"
 )
 
 // Layout constants for consistent sizing across all views
@@ -17,8 +24,6 @@ const (
 	attributesTableHeight = 8   // Reserved height for attributes table in details view
 	minTableHeight        = 5   // Minimum height for any table
 	defaultTableHeight    = 10  // Default table height when window size unknown
-	contentWidth          = 140 // Fixed width for content area
-	contentHeight         = 25  // Fixed height for content area
 )
 
 type model struct {
@@ -28,8 +33,27 @@ type model struct {
 	previous    page
 	state       state
 	client      *sqs.Client
+	metrics     *cloudwatch.Client // nil unless an alert rule needs CloudWatch metrics
 	awsInfo     client.AWSInfo
-	context     context.Context
+	session     *client.Session   // profile, region and credentials the clients use
+	contexts    []queueContext    // contexts shown together in the overview, empty for a single profile
+	contextByName map[string]int  // index of each context in contexts
+	mfa         *client.MFAPrompt // asks for MFA codes while credentials are resolved
+	retries     *client.RetryMonitor // calls being retried, shown in the status bar
+	retryStatus client.RetryStatus
+	auditLog    *audit.Log // records mutating actions, nil when disabled
+	user        string     // OS user recorded in the audit log
+	trash       *trash.Bin // keeps deleted messages, nil when disabled
+	context     context.Context    // for requests that must complete, such as deletes
+	pageContext context.Context    // for requests of the current page, cancelled when leaving it
+	cancelPage  context.CancelFunc
+	requestID   uint64             // identifies the requests of the current page
+	config      config.Config
+	uiState     config.State
+	decoders    *decode.Registry
+	history     *history.Store
+	alertRules  *alert.Rules
+	bell        bool // rings the terminal bell with the frames rendered until it stops
 	width       int
 	height      int
 	keys        keys.KeyMap
@@ -40,16 +64,26 @@ type model struct {
 	statusMsg  string
 }
 
+// contentWidth returns the fixed width of the content area.
+func (m model) contentWidth() int {
+	return m.config.Layout.Width
+}
+
+// contentHeight returns the fixed height of the content area.
+func (m model) contentHeight() int {
+	return m.config.Layout.Height
+}
+
 // getTableHeight returns the height available for tables.
 func (m model) getTableHeight() int {
 	// Account for table header (2 lines) and some padding
-	return contentHeight - 3
+	return m.contentHeight() - 3
 }
 
 // getMessageTableHeight returns the height for the messages table in details view.
 func (m model) getMessageTableHeight() int {
 	// Content height minus attributes table area
-	available := contentHeight - attributesTableHeight - 3
+	available := m.contentHeight() - attributesTableHeight - 3
 	if available < minTableHeight {
 		return minTableHeight
 	}
@@ -66,4 +100,9 @@ type state struct {
 	queueMessageDetails queueMessageDetailsState
 	queueMessageCreate  queueMessageCreateState
 	queueMessageDelete  queueMessageDeleteState
+	queueTopology       queueTopologyState
+	awsContext          awsContextState
+	credentials         credentialsState
+	auditLog            auditLogState
+	trash               trashState
 }
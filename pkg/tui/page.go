@@ -1,5 +1,11 @@
 package tui
 
+import (
+	"context"
+
+	"github.com/kontrolplane/kue/pkg/tui/commands"
+)
+
 type page uint
 
 const (
@@ -12,6 +18,11 @@ const (
 	queueMessageDetails
 	queueMessageCreate
 	queueMessageDelete
+	queueTopology
+	awsContext
+	credentials
+	auditLog
+	messageTrash
 )
 
 var views = map[page]string{
@@ -24,10 +35,35 @@ var views = map[page]string{
 	queueMessageDetails: "queue message details",
 	queueMessageCreate:  "queue message create",
 	queueMessageDelete:  "queue message delete",
+	queueTopology:       "queue topology",
+	awsContext:          "aws profile",
+	credentials:         "credentials",
+	auditLog:            "audit log",
+	messageTrash:        "trash",
 }
 
 func (m model) SwitchPage(page page) model {
+	if page != m.page {
+		m = m.newPageContext()
+	}
 	m.previous = m.page
 	m.page = page
 	return m
 }
+
+// newPageContext cancels the requests of the current page and starts a new
+// request ID, so results of the cancelled requests that still arrive are
+// discarded.
+func (m model) newPageContext() model {
+	if m.cancelPage != nil {
+		m.cancelPage()
+	}
+	m.requestID++
+	ctx, cancel := context.WithCancel(m.context)
+	m.pageContext = commands.WithRequestID(ctx, m.requestID)
+	m.cancelPage = cancel
+
+	// Attributes requested by the overview are discarded with its requests
+	m.state.queueOverview.pending = nil
+	return m
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+
+	tea "github.com/charmbracelet/bubbletea"
+	kue "github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/commands"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
+)
+
+// confirmation is the outcome of a key pressed on a typed confirmation.
+type confirmation int
+
+const (
+	confirmPending confirmation = iota
+	confirmAccepted
+	confirmCancelled
+)
+
+// protection asks to type the name of a protected queue before deleting,
+// purging, deleting several messages or redriving can be confirmed. Queues
+// no protection rule matches are confirmed with the yes/no buttons alone.
+type protection struct {
+	name    string // queue name to type, empty when the queue is not protected
+	input   textinput.Model
+	readyAt time.Time // the action cannot be confirmed before then
+}
+
+// protectionFor returns the protection of a queue from the rules matching
+// it. The longest cooldown of the matching rules applies.
+func (m model) protectionFor(q kue.Queue) protection {
+	var p protection
+	for _, r := range m.config.Protection.Rules {
+		// Tags are unknown until the attributes of the queue are loaded, the
+		// queue is protected in case it has them
+		if r.HasTags() && q.Arn == "" {
+			r.Tags = nil
+		}
+		if !r.Matches(q.Name, q.Context, q.Tags) {
+			continue
+		}
+		if !p.required() {
+			p = newProtection(q.Name)
+		}
+		if readyAt := time.Now().Add(r.Cooldown); readyAt.After(p.readyAt) {
+			p.readyAt = readyAt
+		}
+	}
+	return p
+}
+
+// protectedQueues returns the queues a protection rule matches.
+func (m model) protectedQueues(queues []kue.Queue) []kue.Queue {
+	var protected []kue.Queue
+	for _, q := range queues {
+		if m.protectionFor(q).required() {
+			protected = append(protected, q)
+		}
+	}
+	return protected
+}
+
+func newProtection(name string) protection {
+	ti := textinput.New()
+	ti.Placeholder = name
+	ti.CharLimit = 80 // the longest queue name
+	ti.Width = len(name) + 1
+	ti.Focus()
+	return protection{name: name, input: ti}
+}
+
+// required reports whether the name of the queue must be typed.
+func (p protection) required() bool {
+	return p.name != ""
+}
+
+// cooldown returns how long the action cannot be confirmed yet.
+func (p protection) cooldown() time.Duration {
+	return max(0, time.Until(p.readyAt))
+}
+
+// ready reports whether the name was typed exactly and the cooldown is over.
+func (p protection) ready() bool {
+	return p.input.Value() == p.name && p.cooldown() == 0
+}
+
+// init starts the cursor blinking and the countdown of the cooldown.
+func (p protection) init() tea.Cmd {
+	if !p.required() {
+		return nil
+	}
+	if p.cooldown() > 0 {
+		return tea.Batch(textinput.Blink, commands.CooldownTick())
+	}
+	return textinput.Blink
+}
+
+// update passes keys to the input. Enter accepts once the confirmation is
+// ready and esc cancels, like the filter inputs.
+func (p protection) update(msg tea.Msg) (protection, confirmation, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.Type {
+		case tea.KeyEsc, tea.KeyCtrlC:
+			return p, confirmCancelled, nil
+		case tea.KeyEnter:
+			if p.ready() {
+				return p, confirmAccepted, nil
+			}
+			return p, confirmPending, nil
+		}
+	}
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return p, confirmPending, cmd
+}
+
+// view shows the input for the queue name and the confirm button, which is
+// inactive until the name matches and the cooldown is over.
+func (p protection) view() string {
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.MediumGray)
+
+	button := styles.ButtonPrimary.Render("confirm")
+	if p.ready() {
+		button = styles.ButtonSecondary.Render("confirm")
+	}
+	status := mutedStyle.Render("enter to confirm, esc to cancel")
+	if d := p.cooldown(); d > 0 {
+		status = mutedStyle.Render(fmt.Sprintf("confirm available in %ds", int((d+time.Second-1)/time.Second)))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Center,
+		styles.Danger().Render("this queue is protected")+", type "+styles.Bold.Render(p.name)+" to confirm",
+		"",
+		p.input.View(),
+		"",
+		button,
+		"",
+		status,
+	)
+}
+
+// activeProtection returns the protection of the confirmation page shown.
+func (m model) activeProtection() protection {
+	switch m.page {
+	case queueDelete:
+		return m.state.queueDelete.protection
+	case queuePurge:
+		return m.state.queuePurge.protection
+	case queueRedrive:
+		return m.state.queueRedrive.protection
+	case queueMessageDelete:
+		return m.state.queueMessageDelete.protection
+	}
+	return protection{}
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+// typeText sends the keys of a text to the model.
+func typeText(t *testing.T, m model, text string) model {
+	t.Helper()
+	for _, r := range text {
+		result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = result.(model)
+	}
+	return m
+}
+
+func newProtectedModel(rules ...config.ProtectionRule) model {
+	m := newTestModel()
+	m.config.Protection.Rules = rules
+	return m
+}
+
+func TestProtectedPurgeRequiresQueueName(t *testing.T) {
+	m := newProtectedModel(config.ProtectionRule{Pattern: "*-prod"})
+	m.state.queuePurge.queue = kue.Queue{Name: "orders-prod", Url: "http://test/orders-prod", Arn: "arn:aws:sqs:us-east-1:123456789012:orders-prod"}
+	m.state.queuePurge.fromOverview = true
+	m, _ = m.QueuePurgeSwitchPage(nil)
+
+	if !m.state.queuePurge.protection.required() {
+		t.Fatal("Expected the queue to be protected")
+	}
+	if view := m.QueuePurgeView(); !strings.Contains(view, "this queue is protected") || strings.Contains(view, "yes") {
+		t.Errorf("Expected the name input instead of the buttons, got %q", view)
+	}
+
+	// Keys bound to the buttons are typed into the input
+	m = typeText(t, m, "orders-pr")
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(model)
+	if m.page != queuePurge || m.loading {
+		t.Fatal("Expected a partial name not to confirm the purge")
+	}
+
+	m = typeText(t, m, "od")
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(model)
+	if !m.loading || cmd == nil {
+		t.Error("Expected the exact name to confirm the purge")
+	}
+}
+
+func TestProtectedConfirmationCancels(t *testing.T) {
+	m := newProtectedModel(config.ProtectionRule{Pattern: "orders"})
+	m.state.queueRedrive.queue = kue.Queue{Name: "orders", Url: "http://test/orders"}
+	m.state.queueRedrive.fromOverview = true
+	m, _ = m.QueueRedriveSwitchPage(nil)
+
+	m = typeText(t, m, "q")
+	if m.page != queueRedrive {
+		t.Fatal("Expected q to be typed rather than go back")
+	}
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if page := result.(model).page; page != queueOverview {
+		t.Errorf("Expected esc to cancel the redrive, got page %v", views[page])
+	}
+}
+
+func TestProtectionCooldown(t *testing.T) {
+	m := newProtectedModel(config.ProtectionRule{Pattern: "orders", Cooldown: 10 * time.Second})
+	m.state.queueDelete.queues = []kue.Queue{{Name: "orders", Url: "http://test/orders"}}
+	m, cmd := m.QueueDeleteSwitchPage(nil)
+	if cmd == nil {
+		t.Fatal("Expected the countdown to start")
+	}
+
+	m = typeText(t, m, "orders")
+	if view := m.QueueDeleteView(); !strings.Contains(view, "confirm available in 10s") {
+		t.Errorf("Expected the countdown, got %q", view)
+	}
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(model)
+	if m.loading {
+		t.Fatal("Expected the deletion to wait for the cooldown")
+	}
+
+	m.state.queueDelete.protection.readyAt = time.Now().Add(-time.Second)
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !result.(model).loading {
+		t.Error("Expected the deletion to be confirmed after the cooldown")
+	}
+}
+
+func TestProtectionByTagsAndContext(t *testing.T) {
+	m := newProtectedModel(
+		config.ProtectionRule{Tags: map[string]string{"env": "prod"}},
+		config.ProtectionRule{Context: "live"},
+	)
+
+	tests := []struct {
+		name  string
+		queue kue.Queue
+		want  bool
+	}{
+		{"prod tag", kue.Queue{Name: "a", Arn: "arn:a", Tags: map[string]string{"env": "prod"}}, true},
+		{"dev tag", kue.Queue{Name: "b", Arn: "arn:b", Tags: map[string]string{"env": "dev"}}, false},
+		{"tags not loaded", kue.Queue{Name: "c"}, true},
+		{"protected context", kue.Queue{Name: "d", Arn: "arn:d", Context: "live"}, true},
+	}
+	for _, tt := range tests {
+		if got := m.protectionFor(tt.queue).required(); got != tt.want {
+			t.Errorf("%s: expected protected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestProtectedQueuesAreDeletedOneAtATime(t *testing.T) {
+	m := newProtectedModel(config.ProtectionRule{Pattern: "*-prod"})
+	m.state.queueDelete.queues = []kue.Queue{
+		{Name: "orders-dev", Url: "http://test/orders-dev"},
+		{Name: "orders-prod", Url: "http://test/orders-prod"},
+	}
+
+	m, _ = m.QueueDeleteSwitchPage(nil)
+	if m.page == queueDelete || !strings.Contains(m.error, "orders-prod is protected") {
+		t.Errorf("Expected the bulk deletion to be refused, got error %q", m.error)
+	}
+}
+
+func TestProtectedBulkMessageDelete(t *testing.T) {
+	m := newProtectedModel(config.ProtectionRule{Pattern: "orders"})
+	m.state.queueMessageDelete.queueName = "orders"
+	m.state.queueMessageDelete.queue = kue.QueueKey{Url: "http://test/orders"}
+
+	m.state.queueMessageDelete.messages = []kue.Message{{MessageID: "1"}}
+	single, _ := m.QueueMessageDeleteSwitchPage(nil)
+	if single.state.queueMessageDelete.protection.required() {
+		t.Error("Expected a single message to be confirmed with the buttons")
+	}
+
+	m.state.queueMessageDelete.messages = []kue.Message{{MessageID: "1"}, {MessageID: "2"}}
+	bulk, _ := m.QueueMessageDeleteSwitchPage(nil)
+	if !bulk.state.queueMessageDelete.protection.required() {
+		t.Error("Expected deleting several messages to require the queue name")
+	}
+}
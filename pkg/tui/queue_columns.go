@@ -0,0 +1,344 @@
+package tui
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/history"
+	kue "github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/commands"
+)
+
+// queueColumn describes a column of the queue overview table.
+type queueColumn struct {
+	id      string
+	title   string
+	width   int
+	numeric bool                              // sort by numeric value and center the cells
+	value   func(m model, q kue.Queue) string // nil for the dlq column, see columnValue
+	sortBy  func(m model, q kue.Queue) string // sort value when it differs from the cell value
+}
+
+// queueColumns are the available overview columns. Tag columns are created on
+// demand by queueColumnByID.
+var queueColumns = []queueColumn{
+	{id: "name", title: "queue name", width: 30, value: func(_ model, q kue.Queue) string { return q.Name }},
+	{id: "context", title: "context", width: 15, value: func(_ model, q kue.Queue) string { return q.Context }},
+	{id: "type", title: "type", width: 10, value: func(_ model, q kue.Queue) string { return queueType(q) }},
+	{id: "available", title: "available", width: 10, numeric: true, value: func(_ model, q kue.Queue) string { return q.ApproximateNumberOfMessages }},
+	{id: "in-flight", title: "not visible", width: 10, numeric: true, value: func(_ model, q kue.Queue) string { return q.ApproximateNumberOfMessagesNotVisible }},
+	{id: "delayed", title: "delayed", width: 10, numeric: true, value: func(_ model, q kue.Queue) string { return q.ApproximateNumberOfMessagesDelayed }},
+	{id: "trend", title: "trend", width: 10, numeric: true, value: func(m model, q kue.Queue) string { return m.queueTrend(q) }, sortBy: func(m model, q kue.Queue) string { return m.queueRate(q) }},
+	{id: "dlq", title: "dlq depth", width: 10, numeric: true},
+	{id: "visibility", title: "visibility", width: 10, numeric: true, value: func(_ model, q kue.Queue) string { return q.VisibilityTimeout + "s" }},
+	{id: "retention", title: "retention", width: 10, numeric: true, value: func(_ model, q kue.Queue) string { return formatRetention(q.MessageRetentionPeriod) }},
+	{id: "encryption", title: "encryption", width: 10, value: func(_ model, q kue.Queue) string { return queueEncryption(q) }},
+	{id: "last-modified", title: "last updated", width: 20, value: func(_ model, q kue.Queue) string { return q.LastModified }},
+}
+
+// defaultQueueColumns are shown when no columns are configured.
+var defaultQueueColumns = []string{"name", "type", "available", "in-flight", "delayed", "trend", "visibility", "retention", "last-modified"}
+
+// tagColumnPrefix prefixes column ids that show a queue tag, e.g. tag:team.
+const tagColumnPrefix = "tag:"
+
+// queueColumnByID returns the column with the given id.
+func queueColumnByID(id string) (queueColumn, bool) {
+	if tag, ok := strings.CutPrefix(id, tagColumnPrefix); ok && tag != "" {
+		return queueColumn{
+			id:    id,
+			title: tag,
+			width: 15,
+			value: func(_ model, q kue.Queue) string { return q.Tags[tag] },
+		}, true
+	}
+	for _, c := range queueColumns {
+		if c.id == id {
+			return c, true
+		}
+	}
+	return queueColumn{}, false
+}
+
+// overviewColumns returns the configured overview columns, ignoring unknown ids.
+func (m model) overviewColumns() []queueColumn {
+	ids := m.config.Overview.Columns
+	if len(ids) == 0 {
+		ids = defaultQueueColumns
+		if m.aggregating() {
+			// Show which context a queue belongs to right after its name
+			ids = append([]string{ids[0], "context"}, ids[1:]...)
+		}
+	}
+
+	var columns []queueColumn
+	for _, id := range ids {
+		if c, ok := queueColumnByID(id); ok {
+			columns = append(columns, c)
+		}
+	}
+	if len(columns) == 0 {
+		return defaultOverviewColumns()
+	}
+	return columns
+}
+
+// defaultOverviewColumns returns the columns shown when none are configured.
+func defaultOverviewColumns() []queueColumn {
+	var columns []queueColumn
+	for _, id := range defaultQueueColumns {
+		c, _ := queueColumnByID(id)
+		columns = append(columns, c)
+	}
+	return columns
+}
+
+// tableColumns converts queue columns to table columns, marking the sort column.
+func tableColumns(cols []queueColumn, sortColumn string, sortDesc bool) []table.Column {
+	var columns []table.Column
+	for _, c := range cols {
+		title := c.title
+		if c.id == sortColumn {
+			if sortDesc {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		columns = append(columns, table.Column{Title: title, Width: c.width})
+	}
+	return columns
+}
+
+// setOverviewRows replaces the columns and rows of the overview table.
+func (m model) setOverviewRows(queues []kue.Queue) model {
+	columns := m.overviewColumns()
+	depths := m.deadLetterDepths()
+
+	var rows []table.Row
+	for _, queue := range queues {
+		rows = append(rows, m.overviewRow(columns, depths, queue, m.isQueueSelected(queue)))
+	}
+
+	// Clear the rows first, the table renders existing rows with the new columns
+	m.state.queueOverview.table.SetRows(nil)
+	m.state.queueOverview.table.SetColumns(tableColumns(columns, m.state.queueOverview.sortColumn, m.state.queueOverview.sortDesc))
+	m.state.queueOverview.table.SetRows(rows)
+	return m
+}
+
+// isQueueSelected reports whether the queue is selected for a bulk operation.
+func (m model) isQueueSelected(q kue.Queue) bool {
+	for origIdx, origQueue := range m.state.queueOverview.queues {
+		if origQueue.Key() == q.Key() {
+			return m.state.queueOverview.selectedItems[origIdx]
+		}
+	}
+	return false
+}
+
+// overviewRow renders the table row of a queue, with the dead-letter depths
+// resolved once for all rows.
+func (m model) overviewRow(columns []queueColumn, depths map[string]int, q kue.Queue, selected bool) table.Row {
+	failed := m.state.queueOverview.attributeErrs[q.Key()] != nil
+	var row table.Row
+	for _, c := range columns {
+		value := columnValue(m, c, depths, q)
+		if c.id != "name" && c.id != "context" {
+			_, fetched := m.state.queueOverview.fetchedAt[q.Key()]
+			switch {
+			case failed:
+				value = "-"
+			case m.state.queueOverview.pending[q.Key()] && !fetched:
+				// Cached attributes stay visible while they refresh
+				value = "…"
+			}
+		}
+		if c.id == "name" && failed {
+			value = "⚠ " + value
+		}
+		if c.id == "name" && selected {
+			value = "● " + value
+		}
+		if c.numeric {
+			value = centerText(value, c.width)
+		}
+		row = append(row, value)
+	}
+	return row
+}
+
+// sortQueues returns the queues ordered by the active sort column. The input
+// slice is not modified.
+func (m model) sortQueues(queues []kue.Queue) []kue.Queue {
+	column, ok := queueColumnByID(m.state.queueOverview.sortColumn)
+	if !ok {
+		return queues
+	}
+
+	var depths map[string]int
+	if column.id == "dlq" {
+		depths = m.deadLetterDepths()
+	}
+	value := column.sortBy
+	if value == nil {
+		value = func(m model, q kue.Queue) string { return columnValue(m, column, depths, q) }
+	}
+
+	// The sort values are computed once per queue, not per comparison
+	type sortItem struct {
+		queue kue.Queue
+		value string
+	}
+	items := make([]sortItem, len(queues))
+	for i, q := range queues {
+		items[i] = sortItem{queue: q, value: value(m, q)}
+	}
+
+	desc := m.state.queueOverview.sortDesc
+	sort.SliceStable(items, func(i, j int) bool {
+		c := compareColumnValues(column, items[i].value, items[j].value)
+		if c == 0 {
+			return items[i].queue.Name < items[j].queue.Name
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	sorted := make([]kue.Queue, len(items))
+	for i, item := range items {
+		sorted[i] = item.queue
+	}
+	return sorted
+}
+
+// compareColumnValues compares two cell values. Numeric columns compare by
+// number and place empty or non-numeric values first.
+func compareColumnValues(c queueColumn, a, b string) int {
+	if c.numeric {
+		x, xerr := columnNumber(a)
+		y, yerr := columnNumber(b)
+		switch {
+		case xerr != nil && yerr != nil:
+			return 0
+		case xerr != nil:
+			return -1
+		case yerr != nil:
+			return 1
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// columnNumber parses a numeric cell such as 42, 30s, 4d or 12h into a number
+// of its smallest unit.
+func columnNumber(s string) (float64, error) {
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(s, "d"):
+		multiplier, s = 86400, strings.TrimSuffix(s, "d")
+	case strings.HasSuffix(s, "h"):
+		multiplier, s = 3600, strings.TrimSuffix(s, "h")
+	case strings.HasSuffix(s, "s"):
+		s = strings.TrimSuffix(s, "s")
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	return n * multiplier, err
+}
+
+// nextSortColumn cycles the sort through the visible columns and back to the
+// unsorted list order.
+func (m model) nextSortColumn() model {
+	columns := m.overviewColumns()
+	next := ""
+	if m.state.queueOverview.sortColumn == "" {
+		next = columns[0].id
+	} else {
+		for i, c := range columns {
+			if c.id == m.state.queueOverview.sortColumn && i+1 < len(columns) {
+				next = columns[i+1].id
+				break
+			}
+		}
+	}
+	m.state.queueOverview.sortColumn = next
+	m.state.queueOverview.selected = 0
+	return m
+}
+
+// saveOverviewSort persists the current sort so it is restored next session.
+func (m model) saveOverviewSort() (model, tea.Cmd) {
+	m.uiState.Overview.SortColumn = m.state.queueOverview.sortColumn
+	m.uiState.Overview.SortDescending = m.state.queueOverview.sortDesc
+	return m, commands.SaveState(m.uiState)
+}
+
+// queueTrend returns a sparkline of the visible messages of a queue.
+func (m model) queueTrend(q kue.Queue) string {
+	samples := m.queueSamples(q.Key())
+	if len(samples) < 2 {
+		return "-"
+	}
+	return sparkline(history.Values(samples, history.Visible), 8)
+}
+
+// queueRate returns the rate of change of the visible messages of a queue.
+func (m model) queueRate(q kue.Queue) string {
+	rate, ok := history.Rate(m.queueSamples(q.Key()), history.Visible, rateWindow)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+// queueType returns the display type of a queue.
+func queueType(q kue.Queue) string {
+	if q.FifoQueue == "true" {
+		return "fifo"
+	}
+	return "standard"
+}
+
+// queueEncryption returns how a queue is encrypted at rest.
+func queueEncryption(q kue.Queue) string {
+	switch {
+	case q.KmsMasterKeyId != "":
+		return "kms"
+	case q.SqsManagedSseEnabled == "true":
+		return "sse-sqs"
+	}
+	return "none"
+}
+
+// columnValue returns the cell value of a queue. The dlq column reads the
+// depths resolved once per render or sort instead of scanning all queues for
+// each cell.
+func columnValue(m model, c queueColumn, depths map[string]int, q kue.Queue) string {
+	if c.id == "dlq" {
+		return deadLetterDepthOf(depths, q)
+	}
+	return c.value(m, q)
+}
+
+// deadLetterDepthOf returns the depth of the queue's dead-letter queue from
+// the depths keyed by ARN, see deadLetterDepths.
+func deadLetterDepthOf(depths map[string]int, q kue.Queue) string {
+	if q.DeadLetterTargetARN == "" {
+		return "-"
+	}
+	if n, ok := depths[q.DeadLetterTargetARN]; ok {
+		return strconv.Itoa(n)
+	}
+	return "?"
+}
@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+func newTestSortModel() model {
+	m := newTestModel()
+	m.state.queueOverview.queues = []kue.Queue{
+		{Name: "orders", Url: "http://test/orders", ApproximateNumberOfMessages: "10", DeadLetterTargetARN: "arn:orders-dlq"},
+		{Name: "orders-dlq", Url: "http://test/orders-dlq", Arn: "arn:orders-dlq", ApproximateNumberOfMessages: "250"},
+		{Name: "billing", Url: "http://test/billing", ApproximateNumberOfMessages: "3", Tags: map[string]string{"team": "payments"}},
+	}
+	return m
+}
+
+func queueNames(queues []kue.Queue) string {
+	var names []string
+	for _, q := range queues {
+		names = append(names, q.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSortQueues(t *testing.T) {
+	tests := []struct {
+		column string
+		desc   bool
+		want   string
+	}{
+		{"", false, "orders,orders-dlq,billing"},
+		{"name", false, "billing,orders,orders-dlq"},
+		{"available", false, "billing,orders,orders-dlq"},
+		{"available", true, "orders-dlq,orders,billing"},
+		{"dlq", true, "orders,billing,orders-dlq"},
+		{"tag:team", true, "billing,orders,orders-dlq"},
+	}
+
+	for _, tt := range tests {
+		m := newTestSortModel()
+		m.state.queueOverview.sortColumn = tt.column
+		m.state.queueOverview.sortDesc = tt.desc
+
+		if got := queueNames(m.getFilteredQueues()); got != tt.want {
+			t.Errorf("sort %q desc=%v: expected %s, got %s", tt.column, tt.desc, tt.want, got)
+		}
+	}
+}
+
+func TestSortDoesNotReorderQueues(t *testing.T) {
+	m := newTestSortModel()
+	m.state.queueOverview.sortColumn = "name"
+	m.getFilteredQueues()
+
+	if got := queueNames(m.state.queueOverview.queues); got != "orders,orders-dlq,billing" {
+		t.Errorf("Expected the loaded queues to keep their order, got %s", got)
+	}
+}
+
+func TestNextSortColumnCycles(t *testing.T) {
+	m := newTestSortModel()
+	m.config.Overview.Columns = []string{"name", "available"}
+
+	for _, want := range []string{"name", "available", ""} {
+		m = m.nextSortColumn()
+		if m.state.queueOverview.sortColumn != want {
+			t.Errorf("Expected sort column %q, got %q", want, m.state.queueOverview.sortColumn)
+		}
+	}
+}
+
+func TestSaveOverviewSortUpdatesState(t *testing.T) {
+	m := newTestSortModel()
+	m.state.queueOverview.sortColumn = "available"
+	m.state.queueOverview.sortDesc = true
+
+	m, cmd := m.saveOverviewSort()
+	if cmd == nil {
+		t.Error("Expected a command to persist the state")
+	}
+	want := config.OverviewState{SortColumn: "available", SortDescending: true}
+	if m.uiState.Overview != want {
+		t.Errorf("Expected %+v, got %+v", want, m.uiState.Overview)
+	}
+}
+
+func TestConfiguredOverviewColumns(t *testing.T) {
+	m := newTestSortModel()
+	m.config.Overview.Columns = []string{"name", "tag:team", "unknown", "dlq"}
+	m.state.queueOverview.sortColumn = "dlq"
+
+	m = m.updateQueueOverviewTableFiltered()
+
+	columns := m.state.queueOverview.table.Columns()
+	if len(columns) != 3 {
+		t.Fatalf("Expected 3 columns, got %d", len(columns))
+	}
+	if columns[1].Title != "team" || columns[2].Title != "dlq depth ▲" {
+		t.Errorf("Unexpected column titles %q, %q", columns[1].Title, columns[2].Title)
+	}
+
+	rows := m.state.queueOverview.table.Rows()
+	if rows[2][0] != "orders" || strings.TrimSpace(rows[2][2]) != "250" {
+		t.Errorf("Unexpected rows %v", rows)
+	}
+}
+
+func TestToggleSelectionUsesSortedQueue(t *testing.T) {
+	m := newTestSortModel()
+	m.state.queueOverview.sortColumn = "name"
+	m.state.queueOverview.selectedItems = make(map[int]bool)
+
+	m, _ = m.toggleQueueSelection()
+
+	selected := m.getSelectedQueues()
+	if len(selected) != 1 || selected[0].Name != "billing" {
+		t.Errorf("Expected billing to be selected, got %v", queueNames(selected))
+	}
+}
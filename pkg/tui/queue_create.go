@@ -184,6 +184,11 @@ func validateIntRangeOrEmpty(min, max int) func(string) error {
 }
 
 func (m model) QueueCreateSwitchPage(msg tea.Msg) (model, tea.Cmd) {
+	// New queues are created with the session of the first context
+	if reason := m.refuseReadOnly(kue.QueueKey{}); reason != "" {
+		m.error = reason
+		return m, nil
+	}
 	m.error = ""
 	m.state.queueCreate.input = &queueCreateInput{
 		queueType:           "standard",
@@ -203,7 +208,7 @@ func (m model) QueueCreateView() string {
 		m.renderFormHeader(),
 		m.state.queueCreate.form.View(),
 	)
-	return lipgloss.Place(contentWidth, contentHeight, lipgloss.Center, lipgloss.Top, content)
+	return lipgloss.Place(m.contentWidth(), m.contentHeight(), lipgloss.Center, lipgloss.Top, content)
 }
 
 // detectFormStep determines the current step by checking for unique field titles.
@@ -333,5 +338,5 @@ func (m model) submitQueueCreate(msg tea.Msg) (model, tea.Cmd) {
 
 	m.loading = true
 	m.loadingMsg = "Creating queue..."
-	return m, commands.CreateQueue(m.context, m.client, config)
+	return m, commands.CreateQueue(m.context, m.client, m.auditFor(kue.QueueKey{}), config)
 }
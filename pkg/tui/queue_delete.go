@@ -14,14 +14,33 @@ import (
 
 // queueDeleteState holds the state for queue deletion confirmation.
 type queueDeleteState struct {
-	queues   []kue.Queue
-	selected int // 0 = no, 1 = yes
+	queues     []kue.Queue
+	selected   int // 0 = no, 1 = yes
+	protection protection
 }
 
 func (m model) QueueDeleteSwitchPage(msg tea.Msg) (model, tea.Cmd) {
+	var keys []kue.QueueKey
+	for _, q := range m.state.queueDelete.queues {
+		keys = append(keys, q.Key())
+	}
+	if reason := m.refuseReadOnly(keys...); reason != "" {
+		m.error = reason
+		return m, nil
+	}
+	// A typed queue name confirms a single queue
+	queues := m.state.queueDelete.queues
+	if protected := m.protectedQueues(queues); len(protected) > 0 && len(queues) > 1 {
+		m.error = "Protected queues are deleted one at a time, " + protected[0].Name + " is protected"
+		return m, nil
+	}
 	m.error = ""
 	m.state.queueDelete.selected = 0
-	return m.SwitchPage(queueDelete), nil
+	m.state.queueDelete.protection = protection{}
+	if len(queues) == 1 {
+		m.state.queueDelete.protection = m.protectionFor(queues[0])
+	}
+	return m.SwitchPage(queueDelete), m.state.queueDelete.protection.init()
 }
 
 func (m model) QueueDeleteView() string {
@@ -34,6 +53,17 @@ func (m model) QueueDeleteView() string {
 		queueDisplay = styles.Bold.Render(fmt.Sprintf("%d queues", numQueues))
 	}
 
+	if m.state.queueDelete.protection.required() {
+		dialog := lipgloss.JoinVertical(lipgloss.Center,
+			"warning: queue deletion",
+			"",
+			"are you sure you want to delete: "+queueDisplay+" ?",
+			"",
+			m.state.queueDelete.protection.view(),
+		)
+		return lipgloss.Place(m.contentWidth(), m.contentHeight()-2, lipgloss.Center, lipgloss.Center, dialog)
+	}
+
 	confirm := "yes"
 	abort := "no"
 
@@ -53,7 +83,7 @@ func (m model) QueueDeleteView() string {
 		"",
 		buttons,
 	)
-	return lipgloss.Place(contentWidth, contentHeight-2, lipgloss.Center, lipgloss.Center, dialog)
+	return lipgloss.Place(m.contentWidth(), m.contentHeight()-2, lipgloss.Center, lipgloss.Center, dialog)
 }
 
 func (m model) switchOption() (model, tea.Cmd) {
@@ -64,6 +94,18 @@ func (m model) switchOption() (model, tea.Cmd) {
 func (m model) QueueDeleteUpdate(msg tea.Msg) (model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if m.state.queueDelete.protection.required() {
+		var result confirmation
+		m.state.queueDelete.protection, result, cmd = m.state.queueDelete.protection.update(msg)
+		switch result {
+		case confirmAccepted:
+			return m.deleteQueues()
+		case confirmCancelled:
+			return m.QueueOverviewSwitchPage(msg)
+		}
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
@@ -75,14 +117,7 @@ func (m model) QueueDeleteUpdate(msg tea.Msg) (model, tea.Cmd) {
 			if m.state.queueDelete.selected == 0 {
 				return m.QueueOverviewSwitchPage(msg)
 			}
-			m.loading = true
-			numQueues := len(m.state.queueDelete.queues)
-			if numQueues == 1 {
-				m.loadingMsg = "Deleting queue..."
-				return m, commands.DeleteQueue(m.context, m.client, m.state.queueDelete.queues[0].Name)
-			}
-			m.loadingMsg = fmt.Sprintf("Deleting %d queues...", numQueues)
-			return m, commands.DeleteQueues(m.context, m.client, m.state.queueDelete.queues)
+			return m.deleteQueues()
 		case key.Matches(msg, m.keys.Quit):
 			m.state.queueDelete.selected = 0
 			return m.QueueOverviewSwitchPage(msg)
@@ -95,3 +130,14 @@ func (m model) QueueDeleteUpdate(msg tea.Msg) (model, tea.Cmd) {
 
 	return m, cmd
 }
+
+func (m model) deleteQueues() (model, tea.Cmd) {
+	m.loading = true
+	numQueues := len(m.state.queueDelete.queues)
+	if numQueues == 1 {
+		m.loadingMsg = "Deleting queue..."
+		return m, commands.DeleteQueue(m.context, m.clientFor(m.state.queueDelete.queues[0].Key()), m.auditFor(m.state.queueDelete.queues[0].Key()), m.state.queueDelete.queues[0])
+	}
+	m.loadingMsg = fmt.Sprintf("Deleting %d queues...", numQueues)
+	return m, commands.DeleteQueues(m.context, m.sqsClients(), m.auditRecorders(), m.state.queueDelete.queues)
+}
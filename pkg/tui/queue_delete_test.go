@@ -1,15 +1,19 @@
 package tui
 
 import (
+	"context"
 	"strings"
 	"testing"
 
+	"github.com/kontrolplane/kue/pkg/config"
 	"github.com/kontrolplane/kue/pkg/keys"
 	"github.com/kontrolplane/kue/pkg/kue"
 )
 
 func newTestDeleteModel() model {
 	return model{
+		config:      config.Default(),
+		context:     context.Background(),
 		projectName: "test",
 		programName: "kue",
 		page:        queueDelete,
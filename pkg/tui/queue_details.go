@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
@@ -10,6 +11,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/filter"
 	kue "github.com/kontrolplane/kue/pkg/kue"
 	"github.com/kontrolplane/kue/pkg/tui/commands"
 	"github.com/kontrolplane/kue/pkg/tui/styles"
@@ -26,6 +28,11 @@ type queueDetailsState struct {
 	filtering       bool
 	filterInput     textinput.Model
 	filterText      string
+	filterExpr      *filter.Expr      // compiled filter when filterText is an expression
+	filterErr       error             // syntax error of the filter expression
+	decodedBodies   map[string]string // decoded message bodies keyed by message ID
+	showTrend       bool              // show the depth history instead of the messages
+	fromTopology    bool              // opened from the dead-letter topology view
 }
 
 // Message table column definitions.
@@ -139,37 +146,66 @@ func (m model) QueueDetailsSwitchPage(msg tea.Msg) (model, tea.Cmd) {
 	m.state.queueDetails.selected = 0
 	m.state.queueDetails.selectedItems = make(map[int]bool)
 	m.state.queueDetails.filtering = false
-	m.state.queueDetails.filterText = ""
 	m.state.queueDetails.filterInput = initMessageFilterInput()
+	m.state.queueDetails.showTrend = false
+	m = m.setMessageFilter("")
 
 	// Clear stale data to prevent showing old content during load
 	m.state.queueDetails.attributesTable = ""
 	m.state.queueDetails.messages = nil
+	m.state.queueDetails.decodedBodies = nil
 	m.state.queueDetails.messagesTable = initMessageDetailsTable(m.getMessageTableHeight())
 
-	return m, tea.Batch(
-		commands.LoadQueueAttributes(m.context, m.client, m.state.queueDetails.queue.Url),
-		commands.LoadMessages(m.context, m.client, m.state.queueDetails.queue.Url, 10),
+	return m, m.loadQueueDetails(m.state.queueDetails.queue.Key())
+}
+
+// loadQueueDetails returns the command loading the attributes and messages of
+// a queue with the client of its context.
+func (m model) loadQueueDetails(queue kue.QueueKey) tea.Cmd {
+	client := m.clientFor(queue)
+	return tea.Batch(
+		commands.LoadQueueAttributes(m.pageContext, client, queue.Url),
+		commands.LoadMessages(m.pageContext, client, queue.Url, m.config.Messages.FetchCount),
 	)
 }
 
 func initMessageFilterInput() textinput.Model {
 	ti := textinput.New()
-	ti.Placeholder = "Type to filter messages..."
-	ti.CharLimit = 50
-	ti.Width = 30
+	ti.Placeholder = `Text or expression, e.g. .status == "FAILED"`
+	ti.CharLimit = 200
+	ti.Width = 50
 	return ti
 }
 
+// setMessageFilter sets the message filter text, compiling it when it is an
+// expression such as `attr.tenant == "acme" and age > 1h`.
+func (m model) setMessageFilter(text string) model {
+	m.state.queueDetails.filterText = text
+	m.state.queueDetails.filterExpr, m.state.queueDetails.filterErr = filter.CompileFilter(text)
+	return m
+}
+
 func (m model) getFilteredMessages() []kue.Message {
-	if m.state.queueDetails.filterText == "" {
+	if m.state.queueDetails.filterText == "" || m.state.queueDetails.filterErr != nil {
 		return m.state.queueDetails.messages
 	}
-	filter := strings.ToLower(m.state.queueDetails.filterText)
+
+	now := time.Now()
+	text := strings.ToLower(m.state.queueDetails.filterText)
 	var filtered []kue.Message
 	for _, msg := range m.state.queueDetails.messages {
-		if strings.Contains(strings.ToLower(msg.MessageID), filter) ||
-			strings.Contains(strings.ToLower(msg.Body), filter) {
+		body, ok := m.state.queueDetails.decodedBodies[msg.MessageID]
+		if !ok {
+			body = msg.Body
+		}
+		if expr := m.state.queueDetails.filterExpr; expr != nil {
+			if expr.Match(filter.NewMessageSubject(msg, body, now)) {
+				filtered = append(filtered, msg)
+			}
+			continue
+		}
+		if strings.Contains(strings.ToLower(msg.MessageID), text) ||
+			strings.Contains(strings.ToLower(body), text) {
 			filtered = append(filtered, msg)
 		}
 	}
@@ -245,7 +281,7 @@ func (m model) QueueDetailsUpdate(msg tea.Msg) (model, tea.Cmd) {
 				return m, nil
 			case tea.KeyEnter:
 				m.state.queueDetails.filtering = false
-				m.state.queueDetails.filterText = m.state.queueDetails.filterInput.Value()
+				m = m.setMessageFilter(m.state.queueDetails.filterInput.Value())
 				m.state.queueDetails.filterInput.Blur()
 				m.state.queueDetails.selected = 0
 				return m, nil
@@ -253,7 +289,7 @@ func (m model) QueueDetailsUpdate(msg tea.Msg) (model, tea.Cmd) {
 		}
 		m.state.queueDetails.filterInput, cmd = m.state.queueDetails.filterInput.Update(msg)
 		// Live filtering as user types
-		m.state.queueDetails.filterText = m.state.queueDetails.filterInput.Value()
+		m = m.setMessageFilter(m.state.queueDetails.filterInput.Value())
 		m.state.queueDetails.selected = 0
 		return m, cmd
 	}
@@ -273,13 +309,15 @@ func (m model) QueueDetailsUpdate(msg tea.Msg) (model, tea.Cmd) {
 			m.state.queueDetails.messagesTable.SetCursor(m.state.queueDetails.selected)
 		case key.Matches(msg, m.keys.Select):
 			m, cmd = m.toggleMessageSelection()
+		case key.Matches(msg, m.keys.Trend):
+			m.state.queueDetails.showTrend = !m.state.queueDetails.showTrend
 		case key.Matches(msg, m.keys.View):
 			filteredMessages := m.getFilteredMessages()
 			if len(filteredMessages) > 0 {
 				selected := m.state.queueDetails.selected
 				m.state.queueMessageDetails.message = filteredMessages[selected]
 				m.state.queueMessageDetails.queueName = m.state.queueDetails.queue.Name
-				m.state.queueMessageDetails.queueUrl = m.state.queueDetails.queue.Url
+				m.state.queueMessageDetails.queue = m.state.queueDetails.queue.Key()
 				m.state.queueMessageDetails.isFifo = m.state.queueDetails.queue.FifoQueue == "true"
 				return m.QueueMessageDetailsSwitchPage(msg)
 			}
@@ -297,7 +335,7 @@ func (m model) QueueDetailsUpdate(msg tea.Msg) (model, tea.Cmd) {
 					}
 				}
 				if len(m.state.queueMessageDelete.messages) > 0 {
-					m.state.queueMessageDelete.queueUrl = m.state.queueDetails.queue.Url
+					m.state.queueMessageDelete.queue = m.state.queueDetails.queue.Key()
 					m.state.queueMessageDelete.queueName = m.state.queueDetails.queue.Name
 					return m.QueueMessageDeleteSwitchPage(msg)
 				}
@@ -321,13 +359,13 @@ func (m model) QueueDetailsUpdate(msg tea.Msg) (model, tea.Cmd) {
 			m.error = "This queue is not a dead-letter queue"
 		case key.Matches(msg, m.keys.Create):
 			m.state.queueMessageCreate.queueName = m.state.queueDetails.queue.Name
-			m.state.queueMessageCreate.queueUrl = m.state.queueDetails.queue.Url
+			m.state.queueMessageCreate.queue = m.state.queueDetails.queue.Key()
 			m.state.queueMessageCreate.isFifo = m.state.queueDetails.queue.FifoQueue == "true"
 			return m.QueueMessageCreateSwitchPage(msg)
 		case key.Matches(msg, m.keys.Quit):
 			// If filtering, clear filter
 			if m.state.queueDetails.filterText != "" {
-				m.state.queueDetails.filterText = ""
+				m = m.setMessageFilter("")
 				m.state.queueDetails.filterInput.SetValue("")
 				m.state.queueDetails.selected = 0
 				return m, nil
@@ -337,6 +375,10 @@ func (m model) QueueDetailsUpdate(msg tea.Msg) (model, tea.Cmd) {
 				m.state.queueDetails.selectedItems = make(map[int]bool)
 				return m, nil
 			}
+			if m.state.queueDetails.fromTopology {
+				m.error = ""
+				return m.SwitchPage(queueTopology), nil
+			}
 			return m.QueueOverviewSwitchPage(msg)
 		default:
 			m.state.queueDetails.messagesTable, cmd = m.state.queueDetails.messagesTable.Update(msg)
@@ -358,6 +400,11 @@ func (m model) QueueDetailsView() string {
 			Render("Loading queue attributes...")
 	}
 
+	if m.state.queueDetails.showTrend {
+		trend := m.renderQueueTrend(m.state.queueDetails.queue.Key(), trendChartWidth, trendChartHeight)
+		return attributesTableView + "\n\n" + trend
+	}
+
 	// Rebuild table rows to reflect current selection state
 	m = m.updateMessagesTableFiltered()
 	messagesTableView := m.state.queueDetails.messagesTable.View()
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+func newTestQueueDetailsModel() model {
+	m := newTestModel()
+	m.page = queueDetails
+	m.state.queueDetails.filterInput = initMessageFilterInput()
+	m.state.queueDetails.messages = []kue.Message{
+		{MessageID: "msg-1", Body: `{"status":"FAILED"}`, ReceiveCount: "5", MessageAttributes: map[string]string{"tenant": "acme"}},
+		{MessageID: "msg-2", Body: `{"status":"OK"}`, ReceiveCount: "1", MessageAttributes: map[string]string{"tenant": "acme"}},
+		{MessageID: "msg-3", Body: `{"status":"FAILED"}`, ReceiveCount: "1", MessageAttributes: map[string]string{"tenant": "other"}},
+	}
+	return m
+}
+
+func TestMessageFilterExpression(t *testing.T) {
+	tests := map[string][]string{
+		`.status == "FAILED"`:                        {"msg-1", "msg-3"},
+		`attr.tenant == "acme" and receiveCount > 3`: {"msg-1"},
+		`not (.status == "FAILED")`:                  {"msg-2"},
+		`msg-2`:                                      {"msg-2"},
+		`failed`:                                     {"msg-1", "msg-3"},
+	}
+
+	for text, want := range tests {
+		m := newTestQueueDetailsModel().setMessageFilter(text)
+		if m.state.queueDetails.filterErr != nil {
+			t.Errorf("Unexpected error for %q: %v", text, m.state.queueDetails.filterErr)
+			continue
+		}
+
+		got := m.getFilteredMessages()
+		if len(got) != len(want) {
+			t.Errorf("Filter %q: expected %d messages, got %d", text, len(want), len(got))
+			continue
+		}
+		for i, msg := range got {
+			if msg.MessageID != want[i] {
+				t.Errorf("Filter %q: expected %s at %d, got %s", text, want[i], i, msg.MessageID)
+			}
+		}
+	}
+}
+
+func TestMessageFilterSearchesTextWithOperators(t *testing.T) {
+	m := newTestQueueDetailsModel()
+	m.state.queueDetails.messages = append(m.state.queueDetails.messages, kue.Message{MessageID: "msg-4", Body: "<order> moved a->b"})
+
+	for _, text := range []string{"<order>", "a->b"} {
+		m = m.setMessageFilter(text)
+		if m.state.queueDetails.filterErr != nil {
+			t.Errorf("Unexpected error for %q: %v", text, m.state.queueDetails.filterErr)
+		}
+		if got := m.getFilteredMessages(); len(got) != 1 || got[0].MessageID != "msg-4" {
+			t.Errorf("Filter %q: expected the message containing the text, got %v", text, got)
+		}
+	}
+}
+
+func TestMessageFilterSyntaxError(t *testing.T) {
+	m := newTestQueueDetailsModel().setMessageFilter(`.status == "FAILED`)
+
+	if m.state.queueDetails.filterErr == nil {
+		t.Fatal("Expected a syntax error for an unterminated string")
+	}
+	if got := len(m.getFilteredMessages()); got != 3 {
+		t.Errorf("Expected all 3 messages while the filter is invalid, got %d", got)
+	}
+
+	m = m.setMessageFilter("")
+	if m.state.queueDetails.filterErr != nil {
+		t.Error("Expected the error to clear with the filter")
+	}
+}
@@ -1,54 +1,113 @@
 package tui
 
 import (
-	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/decode"
+	"github.com/kontrolplane/kue/pkg/envelope"
 	kue "github.com/kontrolplane/kue/pkg/kue"
 	"github.com/kontrolplane/kue/pkg/tui/commands"
 	"github.com/kontrolplane/kue/pkg/tui/styles"
 )
 
-const (
-	detailsLeftPanelWidth    = (contentWidth - 1) / 2                    // Split evenly, 1 for divider
-	detailsRightPanelWidth   = contentWidth - detailsLeftPanelWidth - 1  // Remainder goes to right panel
-	detailsRightContentWidth = detailsRightPanelWidth - 4
-	detailsViewportHeight    = contentHeight - 3 // Account for header and margin
-)
+// detailsLeftPanelWidth returns the width of the attributes panel, the
+// content is split evenly with 1 for the divider.
+func (m model) detailsLeftPanelWidth() int {
+	return (m.contentWidth() - 1) / 2
+}
+
+// detailsRightPanelWidth returns the remainder of the width for the body panel.
+func (m model) detailsRightPanelWidth() int {
+	return m.contentWidth() - m.detailsLeftPanelWidth() - 1
+}
+
+func (m model) detailsRightContentWidth() int {
+	return m.detailsRightPanelWidth() - 4
+}
+
+// detailsViewportHeight returns the height of the body, accounting for the
+// header and margin.
+func (m model) detailsViewportHeight() int {
+	return m.contentHeight() - 3
+}
 
 // queueMessageDetailsState holds the state for message details view.
 type queueMessageDetailsState struct {
 	message   kue.Message
 	queueName string
-	queueUrl  string
+	queue     kue.QueueKey
 	isFifo    bool
-	viewport  viewport.Model
+	body      bodyViewer
+	envelope  envelope.Envelope
+	decoded   decode.Result
+	decodeErr error
+	showRaw   bool // show the body exactly as received instead of the decoded payload
 }
 
-func formatMessageBody(body string) string {
-	var raw json.RawMessage
-	if err := json.Unmarshal([]byte(body), &raw); err != nil {
-		return body
+// decodeMessageBody decodes a message body with the decoder chain configured
+// for the queue, falling back to auto-detection when none is configured.
+func (m model) decodeMessageBody(queueName string, body string) (decode.Result, error) {
+	settings := m.config.QueueSettings(queueName)
+	if len(settings.Decoders) == 0 || m.decoders == nil {
+		return decode.Detect(body), nil
 	}
-	pretty, err := json.MarshalIndent(raw, "", "  ")
+
+	chain, err := m.decoders.Chain(settings.Decoders, decode.Options{
+		DescriptorSet: settings.Protobuf.DescriptorSet,
+		MessageType:   settings.Protobuf.MessageType,
+	})
 	if err != nil {
-		return body
+		return decode.Result{Body: body}, err
+	}
+	return chain.Decode(body)
+}
+
+// unwrapMessageBody removes SNS, EventBridge and S3 envelopes from a message
+// body and decodes the inner payload.
+func (m model) unwrapMessageBody(queueName string, body string) (envelope.Envelope, decode.Result, error) {
+	env := envelope.Unwrap(body)
+	decoded, err := m.decodeMessageBody(queueName, env.Payload)
+	return env, decoded, err
+}
+
+// decodeMessageBodies decodes all message bodies of a queue, keyed by message ID.
+func (m model) decodeMessageBodies(queueName string, msgs []kue.Message) map[string]string {
+	bodies := make(map[string]string, len(msgs))
+	for _, msg := range msgs {
+		_, result, _ := m.unwrapMessageBody(queueName, msg.Body)
+		bodies[msg.MessageID] = result.Body
 	}
-	return string(pretty)
+	return bodies
+}
+
+// messageDetailsBody returns the body shown in the viewport.
+func (m model) messageDetailsBody() string {
+	if m.state.queueMessageDetails.showRaw {
+		return m.state.queueMessageDetails.message.Body
+	}
+	return m.state.queueMessageDetails.decoded.Body
 }
 
 func (m model) QueueMessageDetailsSwitchPage(msg tea.Msg) (model, tea.Cmd) {
 	m.error = ""
 
-	// Initialize viewport for message body
-	vp := viewport.New(detailsRightContentWidth, detailsViewportHeight)
-	vp.SetContent(formatMessageBody(m.state.queueMessageDetails.message.Body))
-	m.state.queueMessageDetails.viewport = vp
+	env, decoded, err := m.unwrapMessageBody(
+		m.state.queueMessageDetails.queueName,
+		m.state.queueMessageDetails.message.Body,
+	)
+	m.state.queueMessageDetails.envelope = env
+	m.state.queueMessageDetails.decoded = decoded
+	m.state.queueMessageDetails.decodeErr = err
+	m.state.queueMessageDetails.showRaw = false
+
+	// Initialize viewer for message body
+	m.state.queueMessageDetails.body = newBodyViewer(m.detailsRightContentWidth(), m.detailsViewportHeight()).
+		SetContent(m.messageDetailsBody())
 
 	return m.SwitchPage(queueMessageDetails), nil
 }
@@ -56,25 +115,40 @@ func (m model) QueueMessageDetailsSwitchPage(msg tea.Msg) (model, tea.Cmd) {
 func (m model) QueueMessageDetailsUpdate(msg tea.Msg) (model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	// Handle search mode
+	if m.state.queueMessageDetails.body.searching {
+		m.state.queueMessageDetails.body, cmd = m.state.queueMessageDetails.body.Update(msg, m.keys)
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, m.keys.CopyToClipboard):
 			return m, commands.CopyToClipboard(m.state.queueMessageDetails.message.Body)
+		case key.Matches(msg, m.keys.ToggleRaw):
+			m.state.queueMessageDetails.showRaw = !m.state.queueMessageDetails.showRaw
+			m.state.queueMessageDetails.body = m.state.queueMessageDetails.body.SetContent(m.messageDetailsBody())
+			return m, nil
 		case key.Matches(msg, m.keys.DeleteMessage):
 			if m.state.queueMessageDetails.message.ReceiptHandle != "" {
 				m.state.queueMessageDelete.message = m.state.queueMessageDetails.message
-				m.state.queueMessageDelete.queueUrl = m.state.queueMessageDetails.queueUrl
+				m.state.queueMessageDelete.queue = m.state.queueMessageDetails.queue
 				m.state.queueMessageDelete.queueName = m.state.queueMessageDetails.queueName
 				return m.QueueMessageDeleteSwitchPage(msg)
 			}
 		case key.Matches(msg, m.keys.Quit):
+			// If searching, clear search
+			if m.state.queueMessageDetails.body.query != "" {
+				m.state.queueMessageDetails.body = m.state.queueMessageDetails.body.ClearSearch()
+				return m, nil
+			}
 			return m.QueueDetailsGoBack(msg)
 		}
 	}
 
-	// Update viewport for scrolling
-	m.state.queueMessageDetails.viewport, cmd = m.state.queueMessageDetails.viewport.Update(msg)
+	// Update viewer for search, display toggles and scrolling
+	m.state.queueMessageDetails.body, cmd = m.state.queueMessageDetails.body.Update(msg, m.keys)
 	return m, cmd
 }
 
@@ -85,10 +159,10 @@ func (m model) QueueMessageDetailsView() string {
 func (m model) renderMessageDetails() string {
 	msg := m.state.queueMessageDetails.message
 
-	const (
-		leftPanelWidth   = detailsLeftPanelWidth
+	const labelWidth = 16
+	var (
+		leftPanelWidth   = m.detailsLeftPanelWidth()
 		leftContentWidth = leftPanelWidth - 4 // Account for padding (4)
-		labelWidth       = 16
 		valueWidth       = leftContentWidth - labelWidth - 2
 	)
 
@@ -137,6 +211,15 @@ func (m model) renderMessageDetails() string {
 	leftSections = append(leftSections, row("Receive Count", msg.ReceiveCount))
 	leftSections = append(leftSections, row("Body Size", fmt.Sprintf("%d bytes", len(msg.Body))))
 	leftSections = append(leftSections, row("MD5", msg.MD5OfBody))
+	if err := m.state.queueMessageDetails.decodeErr; err != nil {
+		errStyle := styles.Danger().Width(valueWidth)
+		leftSections = append(leftSections, lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle.Render("Decoding"),
+			errStyle.Render(err.Error()),
+		))
+	} else if steps := m.state.queueMessageDetails.decoded.Steps; len(steps) > 0 {
+		leftSections = append(leftSections, row("Decoding", strings.Join(steps, " → ")))
+	}
 
 	if msg.MessageGroupID != "" || msg.MessageDeduplicationID != "" || msg.SequenceNumber != "" {
 		leftSections = append(leftSections, sectionHeader.MarginTop(1).Render("FIFO Attributes"))
@@ -158,6 +241,13 @@ func (m model) renderMessageDetails() string {
 		}
 	}
 
+	for _, layer := range m.state.queueMessageDetails.envelope.Layers {
+		leftSections = append(leftSections, sectionHeader.MarginTop(1).Render(layer.Kind+" Envelope"))
+		for _, field := range layer.Fields {
+			leftSections = append(leftSections, row(field.Label, field.Value))
+		}
+	}
+
 	var sysAttrs []string
 	skip := map[string]bool{
 		"SentTimestamp": true, "ApproximateFirstReceiveTimestamp": true,
@@ -178,15 +268,15 @@ func (m model) renderMessageDetails() string {
 		PaddingLeft(2).
 		PaddingRight(2).
 		Width(leftPanelWidth).
-		Height(contentHeight)
+		Height(m.contentHeight())
 
 	leftPanel := leftPanelStyle.Render(lipgloss.JoinVertical(lipgloss.Left, leftSections...))
 
 	// Vertical divider - create full height line
 	var dividerLines string
-	for i := 0; i < contentHeight; i++ {
+	for i := 0; i < m.contentHeight(); i++ {
 		dividerLines += "│"
-		if i < contentHeight-1 {
+		if i < m.contentHeight()-1 {
 			dividerLines += "\n"
 		}
 	}
@@ -202,18 +292,26 @@ func (m model) renderMessageDetails() string {
 		BorderStyle(lipgloss.NormalBorder()).
 		BorderBottom(true).
 		BorderForeground(styles.BorderColor).
-		Width(detailsRightContentWidth).
+		Width(m.detailsRightContentWidth()).
 		MarginBottom(1)
 
 	rightPanelStyle := lipgloss.NewStyle().
 		PaddingLeft(2).
 		PaddingRight(2).
-		Width(detailsRightPanelWidth).
-		Height(contentHeight)
+		Width(m.detailsRightPanelWidth()).
+		Height(m.contentHeight())
+
+	bodyTitle := "Message Body · " + m.state.queueMessageDetails.body.format.String()
+	switch {
+	case m.state.queueMessageDetails.showRaw:
+		bodyTitle += " (raw)"
+	case m.state.queueMessageDetails.envelope.Wrapped():
+		bodyTitle += " (unwrapped from " + strings.Join(m.state.queueMessageDetails.envelope.Kinds(), " → ") + ")"
+	}
 
 	rightContent := lipgloss.JoinVertical(lipgloss.Left,
-		bodyHeaderStyle.Render("Message Body"),
-		m.state.queueMessageDetails.viewport.View(),
+		bodyHeaderStyle.Render(bodyTitle),
+		m.state.queueMessageDetails.body.View(),
 	)
 	rightPanel := rightPanelStyle.Render(rightContent)
 
@@ -224,5 +322,5 @@ func (m model) renderMessageDetails() string {
 		rightPanel,
 	)
 
-	return lipgloss.PlaceHorizontal(contentWidth, lipgloss.Center, content)
+	return lipgloss.PlaceHorizontal(m.contentWidth(), lipgloss.Center, content)
 }
@@ -1,10 +1,12 @@
 package tui
 
 import (
+	"context"
 	"strings"
 	"testing"
 
-	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/config"
 	"github.com/kontrolplane/kue/pkg/keys"
 	"github.com/kontrolplane/kue/pkg/kue"
 )
@@ -24,11 +26,9 @@ func newTestMessageDetailsModel() model {
 		},
 	}
 
-	// Initialize viewport with message body
-	vp := viewport.New(detailsRightContentWidth, detailsViewportHeight)
-	vp.SetContent(msg.Body)
-
-	return model{
+	m := model{
+		config:      config.Default(),
+		context:     context.Background(),
 		projectName: "test",
 		programName: "kue",
 		page:        queueMessageDetails,
@@ -50,10 +50,13 @@ func newTestMessageDetailsModel() model {
 			queueMessageDetails: queueMessageDetailsState{
 				queueName: "test-queue",
 				message:   msg,
-				viewport:  vp,
 			},
 		},
 	}
+
+	// Initialize viewer with message body
+	m.state.queueMessageDetails.body = newBodyViewer(m.detailsRightContentWidth(), m.detailsViewportHeight()).SetContent(msg.Body)
+	return m
 }
 
 func TestQueueMessageDetailsViewContainsMessageID(t *testing.T) {
@@ -166,3 +169,36 @@ func TestQueueMessageDetailsWithFIFOAttributes(t *testing.T) {
 		t.Error("Expected view to contain deduplication ID")
 	}
 }
+
+func TestQueueMessageDetailsUnwrapsSNSEnvelope(t *testing.T) {
+	m := newTestMessageDetailsModel()
+	m.state.queueMessageDetails.message.Body = `{"Type":"Notification","TopicArn":"arn:aws:sns:us-east-1:000000000000:orders","Message":"{\"inner\":\"payload\"}"}`
+
+	m, _ = m.QueueMessageDetailsSwitchPage(nil)
+	view := m.renderMessageDetails()
+
+	if !strings.Contains(view, "SNS Envelope") {
+		t.Error("Expected view to contain 'SNS Envelope' section")
+	}
+	if !strings.Contains(view, "arn:aws:sns:us-east-1:000000000000:orders") {
+		t.Error("Expected view to contain the topic ARN")
+	}
+	if !strings.Contains(m.state.queueMessageDetails.body.View(), `"inner": "payload"`) {
+		t.Error("Expected viewport to contain the pretty-printed inner payload")
+	}
+}
+
+func TestQueueMessageDetailsToggleRaw(t *testing.T) {
+	m := newTestMessageDetailsModel()
+	m.state.queueMessageDetails.message.Body = `{"Type":"Notification","TopicArn":"arn:aws:sns:us-east-1:000000000000:orders","Message":"{\"inner\":\"payload\"}"}`
+	m, _ = m.QueueMessageDetailsSwitchPage(nil)
+
+	m, _ = m.QueueMessageDetailsUpdate(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+
+	if !m.state.queueMessageDetails.showRaw {
+		t.Fatal("Expected raw body to be shown after toggling")
+	}
+	if !strings.Contains(m.state.queueMessageDetails.body.View(), `"TopicArn"`) {
+		t.Error("Expected viewport to contain the raw envelope")
+	}
+}
@@ -1,7 +1,8 @@
 package tui
 
 import (
-	"strings"
+	"fmt"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
@@ -9,8 +10,11 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/alert"
+	"github.com/kontrolplane/kue/pkg/filter"
 	kue "github.com/kontrolplane/kue/pkg/kue"
 	"github.com/kontrolplane/kue/pkg/tui/commands"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
 	"github.com/kontrolplane/kue/pkg/tui/styles"
 )
 
@@ -23,45 +27,16 @@ type queueOverviewState struct {
 	filtering     bool
 	filterInput   textinput.Model
 	filterText    string
-}
-
-// Queue table column definitions.
-var columnMap = map[int]string{
-	0: "queue name",
-	1: "type",
-	2: "available",
-	3: "not visible",
-	4: "delayed",
-	5: "visibility",
-	6: "retention",
-	7: "last updated",
-}
-
-var queueOverviewColumns []table.Column = []table.Column{
-	{
-		Title: columnMap[0], Width: 40,
-	},
-	{
-		Title: columnMap[1], Width: 10,
-	},
-	{
-		Title: columnMap[2], Width: 10,
-	},
-	{
-		Title: columnMap[3], Width: 10,
-	},
-	{
-		Title: columnMap[4], Width: 10,
-	},
-	{
-		Title: columnMap[5], Width: 10,
-	},
-	{
-		Title: columnMap[6], Width: 10,
-	},
-	{
-		Title: columnMap[7], Width: 20,
-	},
+	filterQuery   *filter.QueueQuery // compiled filterText
+	filterErr     error              // syntax error of the filter query
+	sortColumn    string             // id of the column the queues are sorted by, empty for list order
+	sortDesc      bool
+	alerts        []alert.Alert
+	alertSeverity map[kue.QueueKey]alert.Severity // highest alert severity of each queue
+	oldestAges    map[kue.QueueKey]time.Duration  // age of the oldest message of each queue
+	fetchedAt     map[kue.QueueKey]time.Time      // when the attributes of each queue were last requested
+	pending       map[kue.QueueKey]bool           // queues whose attributes are loading
+	attributeErrs map[kue.QueueKey]error          // errors of the last attribute request of each queue
 }
 
 func (m model) QueueOverviewSwitchPage(msg tea.Msg) (model, tea.Cmd) {
@@ -71,31 +46,236 @@ func (m model) QueueOverviewSwitchPage(msg tea.Msg) (model, tea.Cmd) {
 	m.loadingMsg = "Loading queues..."
 	m.state.queueOverview.selectedItems = make(map[int]bool)
 	m.state.queueOverview.filtering = false
-	m.state.queueOverview.filterText = ""
+	m = m.setQueueFilter("")
 	m.state.queueOverview.filterInput = initFilterInput()
-	return m, commands.LoadQueues(m.context, m.client)
+	return m, m.loadQueues()
+}
+
+// queuesLoaded replaces the overview queues with a new listing. Queues that
+// were listed before keep their cached attributes.
+func (m model) queuesLoaded(msg messages.QueuesLoadedMsg) (model, tea.Cmd) {
+	previous := make(map[kue.QueueKey]kue.Queue)
+	for _, q := range m.state.queueOverview.queues {
+		previous[q.Key()] = q
+	}
+
+	queues := make([]kue.Queue, len(msg.Queues))
+	for i, q := range msg.Queues {
+		if old, ok := previous[q.Key()]; ok {
+			q = old
+		}
+		queues[i] = q
+	}
+	m.state.queueOverview.queues = queues
+	m = m.updateQueueOverviewTableFiltered()
+
+	// Visible queues refresh with every listing, even when cached
+	m, cmd := m.loadAttributes(true)
+	if cmd == nil && len(m.state.queueOverview.pending) == 0 {
+		return m.queueAttributesDone()
+	}
+	return m, cmd
+}
+
+// attributesCached reports whether the attributes of a queue were requested
+// within the cache TTL.
+func (m model) attributesCached(queue kue.QueueKey) bool {
+	fetchedAt, ok := m.state.queueOverview.fetchedAt[queue]
+	return ok && time.Since(fetchedAt) < m.config.Overview.CacheTTL
+}
+
+// priorityQueues returns the queues in or near the viewport of the overview,
+// and all queues matching the filter when one is active.
+func (m model) priorityQueues() []kue.Queue {
+	filtered := m.getFilteredQueues()
+	if m.state.queueOverview.filterText != "" {
+		return filtered
+	}
+
+	// The table scrolls with the cursor, a table height on either side of the
+	// cursor covers the viewport and the rows next to it
+	height := m.state.queueOverview.table.Height()
+	cursor := m.state.queueOverview.selected
+	return filtered[max(0, cursor-2*height):min(len(filtered), cursor+2*height)]
+}
+
+// loadAttributes starts loading the attributes of the priority queues that
+// are not cached, or of all priority queues with force. Other queues that are
+// not cached load in batches while nothing else is loading.
+func (m model) loadAttributes(force bool) (model, tea.Cmd) {
+	if m.state.queueOverview.pending == nil {
+		m.state.queueOverview.pending = make(map[kue.QueueKey]bool)
+	}
+	pending := m.state.queueOverview.pending
+
+	var queues []kue.Queue
+	for _, q := range m.priorityQueues() {
+		if !pending[q.Key()] && (force || !m.attributesCached(q.Key())) {
+			queues = append(queues, q)
+		}
+	}
+
+	if len(queues) == 0 && len(pending) == 0 {
+		// Queues outside the viewport load at a lower priority, one batch at a time
+		for _, q := range m.state.queueOverview.queues {
+			if len(queues) == m.config.Overview.Concurrency {
+				break
+			}
+			if !m.attributesCached(q.Key()) {
+				queues = append(queues, q)
+			}
+		}
+	}
+	if len(queues) == 0 {
+		return m, nil
+	}
+
+	for _, q := range queues {
+		pending[q.Key()] = true
+	}
+	return m, commands.StreamQueueAttributes(m.pageContext, m.sqsClients(), queues, m.config.Overview.Concurrency)
+}
+
+// queueAttributesStreamed fills in the attributes that loaded since the
+// previous message, keeping the rows of queues that failed. Once nothing is
+// loading, the queues left in the background load next.
+func (m model) queueAttributesStreamed(msg messages.QueueAttributesStreamedMsg) (model, tea.Cmd) {
+	if m.state.queueOverview.fetchedAt == nil {
+		m.state.queueOverview.fetchedAt = make(map[kue.QueueKey]time.Time)
+	}
+	if m.state.queueOverview.attributeErrs == nil {
+		m.state.queueOverview.attributeErrs = make(map[kue.QueueKey]error)
+	}
+
+	index := make(map[kue.QueueKey]int)
+	for i, q := range m.state.queueOverview.queues {
+		index[q.Key()] = i
+	}
+
+	var loaded []kue.Queue
+	for _, r := range msg.Results {
+		key := r.Queue.Key()
+		// Ignore queues of an earlier account
+		if !m.state.queueOverview.pending[key] {
+			continue
+		}
+		delete(m.state.queueOverview.pending, key)
+		m.state.queueOverview.fetchedAt[key] = time.Now()
+		if r.Err != nil {
+			m.state.queueOverview.attributeErrs[key] = r.Err
+			continue
+		}
+		delete(m.state.queueOverview.attributeErrs, key)
+		if i, ok := index[key]; ok {
+			m.state.queueOverview.queues[i] = r.Queue
+			loaded = append(loaded, r.Queue)
+		}
+	}
+	if m.history != nil {
+		m.history.Record(time.Now(), loaded...)
+	}
+	m = m.updateQueueOverviewTableFiltered()
+
+	var cmds []tea.Cmd
+	if !msg.Done {
+		cmds = append(cmds, commands.WaitForQueueAttributes(msg.RequestID, msg.Attributes))
+	}
+	if len(m.state.queueOverview.pending) == 0 {
+		var loadCmd tea.Cmd
+		m, loadCmd = m.loadAttributes(false)
+		if loadCmd != nil {
+			cmds = append(cmds, loadCmd)
+		} else {
+			var doneCmd tea.Cmd
+			m, doneCmd = m.queueAttributesDone()
+			cmds = append(cmds, doneCmd)
+		}
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// queueAttributesDone reports failed queues and evaluates the alert rules once
+// the attributes of all queues are cached.
+func (m model) queueAttributesDone() (model, tea.Cmd) {
+	var cmds []tea.Cmd
+	if len(m.state.queueOverview.attributeErrs) > 0 {
+		m.statusMsg = m.attributeErrorsStatus()
+		cmds = append(cmds, commands.ClearStatusAfter(5*time.Second))
+	}
+
+	var alertCmd tea.Cmd
+	m, alertCmd = m.refreshAlerts()
+	cmds = append(cmds, alertCmd)
+	return m, tea.Batch(cmds...)
+}
+
+// attributeErrorsStatus summarises the queues whose attributes failed to load.
+func (m model) attributeErrorsStatus() string {
+	errs := m.state.queueOverview.attributeErrs
+	for _, q := range m.state.queueOverview.queues {
+		if err := errs[q.Key()]; err != nil {
+			if len(errs) == 1 {
+				return fmt.Sprintf("Failed to load the attributes of %s: %v", q.Name, err)
+			}
+			return fmt.Sprintf("Failed to load the attributes of %d queues, %s: %v", len(errs), q.Name, err)
+		}
+	}
+	return ""
+}
+
+// attributesProgress describes how many queues are loading their attributes,
+// or returns an empty string when none are.
+func (m model) attributesProgress() string {
+	pending := len(m.state.queueOverview.pending)
+	if pending == 0 {
+		return ""
+	}
+	if pending == 1 {
+		return "loading the attributes of 1 queue"
+	}
+	return fmt.Sprintf("loading the attributes of %d queues", pending)
 }
 
 func initFilterInput() textinput.Model {
 	ti := textinput.New()
-	ti.Placeholder = "Type to filter..."
-	ti.CharLimit = 50
-	ti.Width = 30
+	ti.Placeholder = "Name or query, e.g. tag:team=payments dlq:nonempty"
+	ti.CharLimit = 200
+	ti.Width = 50
 	return ti
 }
 
+// setQueueFilter sets the queue filter text and compiles it.
+func (m model) setQueueFilter(text string) model {
+	m.state.queueOverview.filterText = text
+	m.state.queueOverview.filterQuery, m.state.queueOverview.filterErr = filter.CompileQueueQuery(text)
+	return m
+}
+
 func (m model) getFilteredQueues() []kue.Queue {
-	if m.state.queueOverview.filterText == "" {
-		return m.state.queueOverview.queues
+	query := m.state.queueOverview.filterQuery
+	if m.state.queueOverview.filterText == "" || query == nil {
+		return m.sortQueues(m.state.queueOverview.queues)
 	}
-	filter := strings.ToLower(m.state.queueOverview.filterText)
+
+	depths := m.deadLetterDepths()
+	deadLetters := make(map[string]bool)
+	for _, q := range m.state.queueOverview.queues {
+		if q.DeadLetterTargetARN != "" {
+			deadLetters[q.DeadLetterTargetARN] = true
+		}
+	}
+
 	var filtered []kue.Queue
 	for _, q := range m.state.queueOverview.queues {
-		if strings.Contains(strings.ToLower(q.Name), filter) {
+		subject := filter.QueueSubject{Queue: q, DeadLetterDepth: -1, IsDeadLetter: deadLetters[q.Arn]}
+		if n, ok := depths[q.DeadLetterTargetARN]; ok && q.DeadLetterTargetARN != "" {
+			subject.DeadLetterDepth = n
+		}
+		if query.Match(subject) {
 			filtered = append(filtered, q)
 		}
 	}
-	return filtered
+	return m.sortQueues(filtered)
 }
 
 func (m model) NoQueuesFound() bool {
@@ -112,7 +292,7 @@ func initQueueOverviewTable(height int) table.Model {
 	}
 
 	t := table.New(
-		table.WithColumns(queueOverviewColumns),
+		table.WithColumns(tableColumns(defaultOverviewColumns(), "", false)),
 		table.WithFocused(true),
 		table.WithHeight(height),
 	)
@@ -138,10 +318,21 @@ func (m model) previousQueue() (model, tea.Cmd) {
 }
 
 func (m model) toggleQueueSelection() (model, tea.Cmd) {
-	if len(m.state.queueOverview.queues) == 0 {
+	filteredQueues := m.getFilteredQueues()
+	if len(filteredQueues) == 0 {
+		return m, nil
+	}
+	// Selection is tracked by index into the unsorted, unfiltered queue list
+	idx := -1
+	for i, q := range m.state.queueOverview.queues {
+		if q.Key() == filteredQueues[m.state.queueOverview.selected].Key() {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
 		return m, nil
 	}
-	idx := m.state.queueOverview.selected
 	if m.state.queueOverview.selectedItems == nil {
 		m.state.queueOverview.selectedItems = make(map[int]bool)
 	}
@@ -177,7 +368,7 @@ func (m model) QueueOverviewUpdate(msg tea.Msg) (model, tea.Cmd) {
 				return m, nil
 			case tea.KeyEnter:
 				m.state.queueOverview.filtering = false
-				m.state.queueOverview.filterText = m.state.queueOverview.filterInput.Value()
+				m = m.setQueueFilter(m.state.queueOverview.filterInput.Value())
 				m.state.queueOverview.filterInput.Blur()
 				m.state.queueOverview.selected = 0
 				return m, nil
@@ -185,7 +376,7 @@ func (m model) QueueOverviewUpdate(msg tea.Msg) (model, tea.Cmd) {
 		}
 		m.state.queueOverview.filterInput, cmd = m.state.queueOverview.filterInput.Update(msg)
 		// Live filtering as user types
-		m.state.queueOverview.filterText = m.state.queueOverview.filterInput.Value()
+		m = m.setQueueFilter(m.state.queueOverview.filterInput.Value())
 		m.state.queueOverview.selected = 0
 		return m, cmd
 	}
@@ -204,15 +395,32 @@ func (m model) QueueOverviewUpdate(msg tea.Msg) (model, tea.Cmd) {
 			m, cmd = m.previousQueue()
 		case key.Matches(msg, m.keys.Select):
 			m, cmd = m.toggleQueueSelection()
+		case key.Matches(msg, m.keys.Sort):
+			m = m.nextSortColumn()
+			return m.saveOverviewSort()
+		case key.Matches(msg, m.keys.SortOrder):
+			if m.state.queueOverview.sortColumn != "" {
+				m.state.queueOverview.sortDesc = !m.state.queueOverview.sortDesc
+				return m.saveOverviewSort()
+			}
 		case key.Matches(msg, m.keys.View):
 			filteredQueues := m.getFilteredQueues()
 			if len(filteredQueues) > 0 {
 				selected := m.state.queueOverview.selected
 				m.state.queueDetails.queue = filteredQueues[selected]
+				m.state.queueDetails.fromTopology = false
 				return m.QueueDetailsSwitchPage(msg)
 			}
 		case key.Matches(msg, m.keys.Create):
 			return m.QueueCreateSwitchPage(msg)
+		case key.Matches(msg, m.keys.Topology):
+			return m.QueueTopologySwitchPage(msg)
+		case key.Matches(msg, m.keys.AuditLog):
+			return m.AuditLogSwitchPage(msg)
+		case key.Matches(msg, m.keys.Trash):
+			return m.TrashSwitchPage(msg)
+		case key.Matches(msg, m.keys.Profile):
+			return m.AWSContextSwitchPage(msg)
 		case key.Matches(msg, m.keys.Purge):
 			filteredQueues := m.getFilteredQueues()
 			if len(filteredQueues) > 0 {
@@ -250,7 +458,7 @@ func (m model) QueueOverviewUpdate(msg tea.Msg) (model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Quit):
 			// If filtering, clear filter
 			if m.state.queueOverview.filterText != "" {
-				m.state.queueOverview.filterText = ""
+				m = m.setQueueFilter("")
 				m.state.queueOverview.filterInput.SetValue("")
 				m.state.queueOverview.selected = 0
 				return m, nil
@@ -274,13 +482,17 @@ func (m model) QueueOverviewUpdate(msg tea.Msg) (model, tea.Cmd) {
 func (m model) QueueOverviewView() string {
 	// Rebuild table rows to reflect current selection state
 	m = m.updateQueueOverviewTableFiltered()
-	tableView := m.state.queueOverview.table.View()
-
 	filteredQueues := m.getFilteredQueues()
+	tableView := m.highlightAlertRows(m.state.queueOverview.table.View(), filteredQueues)
+
 	if len(filteredQueues) == 0 {
+		hint := "No queues found. Press " + m.keys.Create.Help().Key + " to create a new queue."
+		if !m.keys.Create.Enabled() {
+			hint = "No queues found."
+		}
 		emptyMsg := lipgloss.NewStyle().
 			Foreground(styles.MediumGray).
-			Render("No queues found. Press Ctrl+N to create a new queue.")
+			Render(hint)
 
 		return tableView + "\n\n" + emptyMsg
 	}
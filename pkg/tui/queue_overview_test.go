@@ -1,10 +1,15 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/config"
 	"github.com/kontrolplane/kue/pkg/keys"
 	"github.com/kontrolplane/kue/pkg/kue"
 	"github.com/kontrolplane/kue/pkg/tui/messages"
@@ -12,6 +17,8 @@ import (
 
 func newTestModel() model {
 	return model{
+		config:      config.Default(),
+		context:     context.Background(),
 		projectName: "test",
 		programName: "kue",
 		page:        queueOverview,
@@ -152,7 +159,7 @@ func TestQueueOverviewViewWithQueues(t *testing.T) {
 	view := m.QueueOverviewView()
 
 	// View should not contain "No queues found" message
-	if view == "No queues found. Press Ctrl+N to create a new queue." {
+	if view == "No queues found. Press ctrl+n to create a new queue." {
 		t.Error("Expected table view, not empty queue message")
 	}
 }
@@ -164,7 +171,200 @@ func TestQueueOverviewViewWithoutQueues(t *testing.T) {
 	view := m.QueueOverviewView()
 
 	// View should contain the empty message centered in the table area
-	if !strings.Contains(view, "No queues found. Press Ctrl+N to create a new queue.") {
+	if !strings.Contains(view, "No queues found. Press ctrl+n to create a new queue.") {
 		t.Errorf("Expected view to contain empty queue message, got '%s'", view)
 	}
 }
+
+func TestQueueFilterQuery(t *testing.T) {
+	m := newTestModel()
+	m.state.queueOverview.queues = []kue.Queue{
+		{Name: "orders", Url: "http://test/orders", DeadLetterTargetARN: "arn:orders-dlq", Tags: map[string]string{"team": "payments"}},
+		{Name: "orders-dlq", Url: "http://test/orders-dlq", Arn: "arn:orders-dlq", ApproximateNumberOfMessages: "4", Tags: map[string]string{"team": "payments"}},
+		{Name: "billing", Url: "http://test/billing", Tags: map[string]string{"team": "billing"}},
+	}
+
+	m = m.setQueueFilter("tag:team=payments dlq:nonempty")
+	filtered := m.getFilteredQueues()
+	if len(filtered) != 1 || filtered[0].Name != "orders" {
+		t.Errorf("Expected only orders to match, got %v", filtered)
+	}
+
+	m = m.setQueueFilter("bill")
+	if filtered := m.getFilteredQueues(); len(filtered) != 1 || filtered[0].Name != "billing" {
+		t.Errorf("Expected plain text to match the queue name, got %v", filtered)
+	}
+
+	m = m.setQueueFilter("has:nothing")
+	if m.state.queueOverview.filterErr == nil {
+		t.Error("Expected a syntax error for an unknown qualifier")
+	}
+	if got := len(m.getFilteredQueues()); got != 3 {
+		t.Errorf("Expected all queues while the query is invalid, got %d", got)
+	}
+}
+
+func TestHelpShowsRemappedKeys(t *testing.T) {
+	m := newTestModel()
+	remapped, err := keys.New(map[string][]string{"purge": {"ctrl+x"}, "delete": {"D"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m.keys = remapped
+
+	help := m.renderHelpContent()
+	for _, want := range []string{"ctrl+x", "delete queue", "delete message"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("Expected the help to contain %q", want)
+		}
+	}
+	if strings.Contains(help, "ctrl+p") {
+		t.Error("Expected the help to no longer show ctrl+p")
+	}
+}
+
+func TestQueueAttributesStream(t *testing.T) {
+	m := newTestModel()
+
+	newModel, cmd := m.Update(messages.QueuesLoadedMsg{
+		Queues: []kue.Queue{
+			{Name: "orders", Url: "http://test/orders"},
+			{Name: "shared", Url: "http://test/shared"},
+		},
+	})
+	m = newModel.(model)
+	if cmd == nil {
+		t.Fatal("Expected a command loading the attributes")
+	}
+	if got := m.attributesProgress(); got != "loading the attributes of 2 queues" {
+		t.Errorf("Expected the progress of the attribute load, got %q", got)
+	}
+	if row := m.overviewRow(m.overviewColumns(), m.deadLetterDepths(), m.state.queueOverview.queues[0], false); row[0] != "orders" || !strings.Contains(row[1], "…") {
+		t.Errorf("Expected the queue name and a loading placeholder, got %v", row)
+	}
+
+	newModel, _ = m.Update(messages.QueueAttributesStreamedMsg{
+		Results: []messages.QueueAttributesLoadedMsg{
+			{Queue: kue.Queue{Name: "orders", Url: "http://test/orders", Arn: "arn:orders", ApproximateNumberOfMessages: "7"}},
+			{Queue: kue.Queue{Name: "shared", Url: "http://test/shared"}, Err: errors.New("AccessDenied")},
+		},
+		Done: true,
+	})
+	m = newModel.(model)
+
+	if m.state.queueOverview.queues[0].ApproximateNumberOfMessages != "7" {
+		t.Error("Expected the streamed attributes to fill in the queue")
+	}
+	if m.attributesProgress() != "" {
+		t.Error("Expected no progress once all attributes have loaded")
+	}
+	if row := m.overviewRow(m.overviewColumns(), m.deadLetterDepths(), m.state.queueOverview.queues[1], false); row[0] != "⚠ shared" || !strings.Contains(row[1], "-") {
+		t.Errorf("Expected the failed queue to be marked, got %v", row)
+	}
+	if !strings.Contains(m.statusMsg, "shared: AccessDenied") {
+		t.Errorf("Expected the failure in the status, got %q", m.statusMsg)
+	}
+	if m.error != "" {
+		t.Errorf("Expected a failed queue not to fail the overview, got %q", m.error)
+	}
+}
+
+func TestQueueAttributesLoadWhileQueuesChange(t *testing.T) {
+	m := newRecordedModel(t, &sqsRecorder{}).newPageContext()
+	for i := range 20 {
+		name := fmt.Sprintf("queue-%02d", i)
+		m.state.queueOverview.queues = append(m.state.queueOverview.queues, kue.Queue{Name: name, Url: "http://test/" + name})
+	}
+
+	m, cmd := m.loadAttributes(true)
+	streamed := make(chan tea.Msg)
+	go func() { streamed <- cmd() }()
+
+	// Streamed attributes are written while the requests are still running,
+	// run with -race to catch requests reading the queues of the model
+	for i := range m.state.queueOverview.queues {
+		m.state.queueOverview.queues[i].ApproximateNumberOfMessages = "1"
+	}
+	msg := (<-streamed).(messages.QueueAttributesStreamedMsg)
+	for range msg.Attributes {
+	}
+}
+
+func TestQueueAttributesIgnoreEarlierAccount(t *testing.T) {
+	m := newTestModel()
+	m.state.queueOverview.queues = []kue.Queue{{Name: "orders", Url: "http://test/orders"}}
+
+	m, _ = m.queueAttributesStreamed(messages.QueueAttributesStreamedMsg{
+		Results: []messages.QueueAttributesLoadedMsg{{Queue: kue.Queue{Name: "orders", Url: "http://test/orders", Arn: "arn:orders"}}},
+		Done:    true,
+	})
+	if m.state.queueOverview.queues[0].Arn != "" {
+		t.Error("Expected attributes that were not requested to be ignored")
+	}
+}
+
+func TestLoadAttributesPrioritizesViewport(t *testing.T) {
+	m := newTestModel()
+	m.state.queueOverview.table.SetHeight(5)
+	for i := range 100 {
+		name := fmt.Sprintf("queue-%03d", i)
+		m.state.queueOverview.queues = append(m.state.queueOverview.queues, kue.Queue{Name: name, Url: "http://test/" + name})
+	}
+	m.state.queueOverview.selected = 50
+
+	m, cmd := m.loadAttributes(false)
+	if cmd == nil {
+		t.Fatal("Expected a command loading the attributes")
+	}
+	pending := m.state.queueOverview.pending
+	if !pending[kue.QueueKey{Url: "http://test/queue-050"}] || !pending[kue.QueueKey{Url: "http://test/queue-045"}] {
+		t.Error("Expected the queues near the cursor to load")
+	}
+	if pending[kue.QueueKey{Url: "http://test/queue-000"}] || pending[kue.QueueKey{Url: "http://test/queue-099"}] {
+		t.Error("Expected queues far from the viewport not to load yet")
+	}
+
+	// Cached queues are not requested again while scrolling
+	m.state.queueOverview.pending = nil
+	m.state.queueOverview.fetchedAt = make(map[kue.QueueKey]time.Time)
+	for _, q := range m.priorityQueues() {
+		m.state.queueOverview.fetchedAt[q.Key()] = time.Now()
+	}
+	m, _ = m.loadAttributes(false)
+	if m.state.queueOverview.pending[kue.QueueKey{Url: "http://test/queue-050"}] {
+		t.Error("Expected cached queues not to load again")
+	}
+	if len(m.state.queueOverview.pending) != m.config.Overview.Concurrency {
+		t.Errorf("Expected one batch of queues outside the viewport to load, got %d", len(m.state.queueOverview.pending))
+	}
+}
+
+func TestSwitchPageDiscardsStaleResults(t *testing.T) {
+	m := newTestModel().newPageContext()
+	overview := m.pageContext
+	stale := m.requestID
+
+	m = m.SwitchPage(queueDetails)
+	if overview.Err() == nil {
+		t.Error("Expected the requests of the previous page to be cancelled")
+	}
+	if m.requestID == stale {
+		t.Fatal("Expected a new request ID for the new page")
+	}
+
+	result, _ := m.Update(messages.MessagesLoadedMsg{
+		RequestID: stale,
+		Messages:  []kue.Message{{MessageID: "msg-1"}},
+	})
+	if got := result.(model).state.queueDetails.messages; got != nil {
+		t.Errorf("Expected the stale result to be discarded, got %v", got)
+	}
+
+	result, _ = m.Update(messages.MessagesLoadedMsg{
+		RequestID: m.requestID,
+		Messages:  []kue.Message{{MessageID: "msg-1"}},
+	})
+	if got := result.(model).state.queueDetails.messages; len(got) != 1 {
+		t.Errorf("Expected the result of the current page, got %v", got)
+	}
+}
@@ -19,13 +19,19 @@ type queuePurgeState struct {
 	selected     int  // 0 = no, 1 = yes
 	secondPrompt bool // true when showing the second confirmation for large queues
 	fromOverview bool // true when triggered from queue overview
+	protection   protection
 }
 
 func (m model) QueuePurgeSwitchPage(msg tea.Msg) (model, tea.Cmd) {
+	if reason := m.refuseReadOnly(m.state.queuePurge.queue.Key()); reason != "" {
+		m.error = reason
+		return m, nil
+	}
 	m.error = ""
 	m.state.queuePurge.selected = 0
 	m.state.queuePurge.secondPrompt = false
-	return m.SwitchPage(queuePurge), nil
+	m.state.queuePurge.protection = m.protectionFor(m.state.queuePurge.queue)
+	return m.SwitchPage(queuePurge), m.state.queuePurge.protection.init()
 }
 
 func (m model) queuePurgeGoBack(msg tea.Msg) (model, tea.Cmd) {
@@ -45,6 +51,17 @@ func (m model) queuePurgeMessageCount() int {
 func (m model) QueuePurgeView() string {
 	queueDisplay := styles.Bold.Render(m.state.queuePurge.queue.Name)
 
+	if m.state.queuePurge.protection.required() {
+		dialog := lipgloss.JoinVertical(lipgloss.Center,
+			"warning: queue purge",
+			"",
+			"are you sure you want to purge all messages from: "+queueDisplay+" ?",
+			"",
+			m.state.queuePurge.protection.view(),
+		)
+		return lipgloss.Place(m.contentWidth(), m.contentHeight()-2, lipgloss.Center, lipgloss.Center, dialog)
+	}
+
 	confirm := "yes"
 	abort := "no"
 
@@ -61,7 +78,7 @@ func (m model) QueuePurgeView() string {
 	var prompt string
 	if m.state.queuePurge.secondPrompt {
 		count := m.queuePurgeMessageCount()
-		dangerStyle := lipgloss.NewStyle().Foreground(styles.DangerRed).Bold(true)
+		dangerStyle := styles.Danger().Bold(true)
 		prompt = fmt.Sprintf(
 			"this queue has %s messages, are you really sure?",
 			dangerStyle.Render(fmt.Sprintf("~%d", count)),
@@ -77,7 +94,7 @@ func (m model) QueuePurgeView() string {
 		"",
 		buttons,
 	)
-	return lipgloss.Place(contentWidth, contentHeight-2, lipgloss.Center, lipgloss.Center, dialog)
+	return lipgloss.Place(m.contentWidth(), m.contentHeight()-2, lipgloss.Center, lipgloss.Center, dialog)
 }
 
 func (m model) switchPurgeOption() (model, tea.Cmd) {
@@ -88,6 +105,19 @@ func (m model) switchPurgeOption() (model, tea.Cmd) {
 func (m model) QueuePurgeUpdate(msg tea.Msg) (model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	// Typing the name of a protected queue replaces both prompts
+	if m.state.queuePurge.protection.required() {
+		var result confirmation
+		m.state.queuePurge.protection, result, cmd = m.state.queuePurge.protection.update(msg)
+		switch result {
+		case confirmAccepted:
+			return m.purgeQueue()
+		case confirmCancelled:
+			return m.queuePurgeGoBack(msg)
+		}
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
@@ -105,9 +135,7 @@ func (m model) QueuePurgeUpdate(msg tea.Msg) (model, tea.Cmd) {
 				m.state.queuePurge.selected = 0
 				return m, nil
 			}
-			m.loading = true
-			m.loadingMsg = "Purging queue..."
-			return m, commands.PurgeQueue(m.context, m.client, m.state.queuePurge.queue.Url)
+			return m.purgeQueue()
 		case key.Matches(msg, m.keys.Quit):
 			m.state.queuePurge.selected = 0
 			return m.queuePurgeGoBack(msg)
@@ -116,3 +144,10 @@ func (m model) QueuePurgeUpdate(msg tea.Msg) (model, tea.Cmd) {
 
 	return m, cmd
 }
+
+func (m model) purgeQueue() (model, tea.Cmd) {
+	queue := m.state.queuePurge.queue
+	m.loading = true
+	m.loadingMsg = "Purging queue..."
+	return m, commands.PurgeQueue(m.context, m.clientFor(queue.Key()), m.auditFor(queue.Key()), queue.Url)
+}
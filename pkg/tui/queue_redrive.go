@@ -22,15 +22,21 @@ type queueRedriveState struct {
 	tasks          []kue.MessageMoveTaskStatus
 	inProgress     bool
 	fromOverview   bool // true when triggered from queue overview
+	protection     protection
 }
 
 func (m model) QueueRedriveSwitchPage(msg tea.Msg) (model, tea.Cmd) {
+	if reason := m.refuseReadOnly(m.state.queueRedrive.queue.Key()); reason != "" {
+		m.error = reason
+		return m, nil
+	}
 	m.error = ""
 	m.state.queueRedrive.selected = 0
 	m.state.queueRedrive.inProgress = false
 	m.state.queueRedrive.taskHandle = ""
 	m.state.queueRedrive.tasks = nil
-	return m.SwitchPage(queueRedrive), nil
+	m.state.queueRedrive.protection = m.protectionFor(m.state.queueRedrive.queue)
+	return m.SwitchPage(queueRedrive), m.state.queueRedrive.protection.init()
 }
 
 func (m model) queueRedriveGoBack(msg tea.Msg) (model, tea.Cmd) {
@@ -51,6 +57,17 @@ func (m model) QueueRedriveView() string {
 func (m model) renderRedriveConfirmation() string {
 	queueDisplay := styles.Bold.Render(m.state.queueRedrive.queue.Name)
 
+	if m.state.queueRedrive.protection.required() {
+		dialog := lipgloss.JoinVertical(lipgloss.Center,
+			"warning: DLQ redrive",
+			"",
+			"are you sure you want to redrive messages from: "+queueDisplay+" ?",
+			"",
+			m.state.queueRedrive.protection.view(),
+		)
+		return lipgloss.Place(m.contentWidth(), m.contentHeight()-2, lipgloss.Center, lipgloss.Center, dialog)
+	}
+
 	confirm := "yes"
 	abort := "no"
 
@@ -70,7 +87,7 @@ func (m model) renderRedriveConfirmation() string {
 		"",
 		buttons,
 	)
-	return lipgloss.Place(contentWidth, contentHeight-2, lipgloss.Center, lipgloss.Center, dialog)
+	return lipgloss.Place(m.contentWidth(), m.contentHeight()-2, lipgloss.Center, lipgloss.Center, dialog)
 }
 
 func (m model) renderRedriveProgress() string {
@@ -87,7 +104,7 @@ func (m model) renderRedriveProgress() string {
 			labelStyle.Render("waiting for status..."),
 		)
 		dialog := lipgloss.JoinVertical(lipgloss.Center, lines...)
-		return lipgloss.Place(contentWidth, contentHeight-2, lipgloss.Center, lipgloss.Center, dialog)
+		return lipgloss.Place(m.contentWidth(), m.contentHeight()-2, lipgloss.Center, lipgloss.Center, dialog)
 	}
 
 	task := m.state.queueRedrive.tasks[0]
@@ -145,7 +162,7 @@ func (m model) renderRedriveProgress() string {
 	// Failure reason
 	if task.FailureReason != "" {
 		lines = append(lines, "")
-		failStyle := lipgloss.NewStyle().Foreground(styles.DangerRed)
+		failStyle := styles.Danger()
 		lines = append(lines, failStyle.Render("error: "+task.FailureReason))
 	}
 
@@ -153,7 +170,7 @@ func (m model) renderRedriveProgress() string {
 	if task.Status != "RUNNING" {
 		lines = append(lines, "")
 		hintStyle := lipgloss.NewStyle().Foreground(styles.DarkGray)
-		lines = append(lines, hintStyle.Render("press q to go back"))
+		lines = append(lines, hintStyle.Render("press "+m.keys.Quit.Help().Key+" to go back"))
 	}
 
 	content := lipgloss.JoinVertical(lipgloss.Center, lines...)
@@ -162,7 +179,7 @@ func (m model) renderRedriveProgress() string {
 		Align(lipgloss.Center).
 		Render(content)
 
-	return lipgloss.Place(contentWidth, contentHeight-2, lipgloss.Center, lipgloss.Center, box)
+	return lipgloss.Place(m.contentWidth(), m.contentHeight()-2, lipgloss.Center, lipgloss.Center, box)
 }
 
 func (m model) switchRedriveOption() (model, tea.Cmd) {
@@ -173,6 +190,18 @@ func (m model) switchRedriveOption() (model, tea.Cmd) {
 func (m model) QueueRedriveUpdate(msg tea.Msg) (model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if m.state.queueRedrive.protection.required() && !m.state.queueRedrive.inProgress {
+		var result confirmation
+		m.state.queueRedrive.protection, result, cmd = m.state.queueRedrive.protection.update(msg)
+		switch result {
+		case confirmAccepted:
+			return m.startRedrive()
+		case confirmCancelled:
+			return m.queueRedriveGoBack(msg)
+		}
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if m.state.queueRedrive.inProgress {
@@ -190,14 +219,7 @@ func (m model) QueueRedriveUpdate(msg tea.Msg) (model, tea.Cmd) {
 			if m.state.queueRedrive.selected == 0 {
 				return m.queueRedriveGoBack(msg)
 			}
-			m.state.queueRedrive.inProgress = true
-			m.loading = true
-			m.loadingMsg = "Starting redrive..."
-			return m, commands.StartRedrive(
-				m.context, m.client,
-				m.state.queueRedrive.queue.Arn,
-				m.state.queueRedrive.destinationArn,
-			)
+			return m.startRedrive()
 		case key.Matches(msg, m.keys.Quit):
 			m.state.queueRedrive.selected = 0
 			return m.queueRedriveGoBack(msg)
@@ -207,6 +229,18 @@ func (m model) QueueRedriveUpdate(msg tea.Msg) (model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m model) startRedrive() (model, tea.Cmd) {
+	m.state.queueRedrive.inProgress = true
+	m.loading = true
+	m.loadingMsg = "Starting redrive..."
+	return m, commands.StartRedrive(
+		m.context, m.clientFor(m.state.queueRedrive.queue.Key()),
+		m.auditFor(m.state.queueRedrive.queue.Key()),
+		m.state.queueRedrive.queue.Arn,
+		m.state.queueRedrive.destinationArn,
+	)
+}
+
 // findSourceQueueArn returns the ARN of the source queue that uses the given
 // ARN as its dead-letter target, or empty string if not found.
 func (m model) findSourceQueueArn(dlqArn string) string {
@@ -0,0 +1,331 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+
+	tea "github.com/charmbracelet/bubbletea"
+	kue "github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/commands"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
+)
+
+// sharedDeadLetterThreshold is the number of source queues from which a
+// dead-letter queue is flagged as shared.
+const sharedDeadLetterThreshold = 3
+
+// queueTopologyState holds the state for the dead-letter topology view.
+type queueTopologyState struct {
+	selected int
+	sources  map[kue.QueueKey][]string // source queue URLs of each dead-letter queue
+	nodes    []topologyNode
+}
+
+// topologyNode is a line of the topology tree: a dead-letter queue or one of
+// its source queues.
+type topologyNode struct {
+	queue    kue.Queue // only the name is known for queues that are not loaded
+	loaded   bool
+	source   bool // a source queue below its dead-letter queue
+	last     bool // the last source of its dead-letter queue
+	parent   int  // index of the dead-letter queue node of a source
+	sources  int  // number of source queues of a dead-letter queue
+	orphaned bool // a dead-letter queue without source queues
+	missing  bool // a dead-letter target that does not exist
+}
+
+func (m model) QueueTopologySwitchPage(msg tea.Msg) (model, tea.Cmd) {
+	m.error = ""
+	m = m.SwitchPage(queueTopology)
+	m.state.queueTopology.selected = 0
+	m.state.queueTopology.sources = nil
+	m.state.queueTopology.nodes = buildTopology(m.state.queueOverview.queues, nil)
+
+	queues := deadLetterCandidates(m.state.queueOverview.queues)
+	if len(queues) == 0 {
+		return m, nil
+	}
+	m.loading = true
+	m.loadingMsg = "Loading dead-letter queue sources..."
+	return m, commands.LoadDeadLetterSources(m.pageContext, m.sqsClients(), queues, m.config.Overview.Concurrency)
+}
+
+// sourceErrorsStatus summarises the dead-letter queues whose source queues
+// failed to be listed, like oldestAgeErrorsStatus.
+func (m model) sourceErrorsStatus(errs map[kue.QueueKey]error) string {
+	for _, q := range m.state.queueOverview.queues {
+		if err := errs[q.Key()]; err != nil {
+			if len(errs) == 1 {
+				return fmt.Sprintf("Failed to list the source queues of %s: %v", q.Name, err)
+			}
+			return fmt.Sprintf("Failed to list the source queues of %d dead-letter queues, %s: %v", len(errs), q.Name, err)
+		}
+	}
+	return ""
+}
+
+// looksLikeDeadLetterQueue reports whether a queue is likely meant to be a
+// dead-letter queue, even when no queue uses it.
+func looksLikeDeadLetterQueue(q kue.Queue) bool {
+	name := strings.ToLower(strings.TrimSuffix(q.Name, ".fifo"))
+	return q.RedriveAllowPolicy != "" ||
+		strings.HasSuffix(name, "dlq") ||
+		strings.Contains(name, "dead-letter") ||
+		strings.Contains(name, "deadletter")
+}
+
+// deadLetterCandidates returns the loaded queues that are or look like
+// dead-letter queues.
+func deadLetterCandidates(queues []kue.Queue) []kue.Queue {
+	targets := make(map[string]bool)
+	for _, q := range queues {
+		if q.DeadLetterTargetARN != "" {
+			targets[q.DeadLetterTargetARN] = true
+		}
+	}
+
+	var candidates []kue.Queue
+	for _, q := range queues {
+		if targets[q.Arn] || looksLikeDeadLetterQueue(q) {
+			candidates = append(candidates, q)
+		}
+	}
+	return candidates
+}
+
+// queueNameFromArn returns the queue name of an SQS queue ARN.
+func queueNameFromArn(arn string) string {
+	return arn[strings.LastIndex(arn, ":")+1:]
+}
+
+// queueNameFromUrl returns the queue name of an SQS queue URL.
+func queueNameFromUrl(url string) string {
+	return url[strings.LastIndex(url, "/")+1:]
+}
+
+// buildTopology builds the tree of dead-letter queues and their sources from
+// the redrive policies of the loaded queues and the listed source queues.
+func buildTopology(queues []kue.Queue, listedSources map[kue.QueueKey][]string) []topologyNode {
+	byArn := make(map[string]kue.Queue)
+	byKey := make(map[kue.QueueKey]kue.Queue)
+	for _, q := range queues {
+		byArn[q.Arn] = q
+		byKey[q.Key()] = q
+	}
+
+	// Source queues keyed by dead-letter queue ARN, deduplicated by queue
+	sources := make(map[string]map[kue.QueueKey]topologyNode)
+	addSource := func(dlqArn string, n topologyNode) {
+		if sources[dlqArn] == nil {
+			sources[dlqArn] = make(map[kue.QueueKey]topologyNode)
+		}
+		sources[dlqArn][n.queue.Key()] = n
+	}
+	for _, q := range queues {
+		if q.DeadLetterTargetARN != "" {
+			addSource(q.DeadLetterTargetARN, topologyNode{queue: q, loaded: true})
+		}
+	}
+	for dlqKey, urls := range listedSources {
+		dlq, ok := byKey[dlqKey]
+		if !ok {
+			continue
+		}
+		// Source queues are listed with the client of the dead-letter queue's context
+		for _, url := range urls {
+			if q, ok := byKey[kue.QueueKey{Context: dlq.Context, Url: url}]; ok {
+				addSource(dlq.Arn, topologyNode{queue: q, loaded: true})
+			} else {
+				addSource(dlq.Arn, topologyNode{queue: kue.Queue{Name: queueNameFromUrl(url), Url: url, Context: dlq.Context}})
+			}
+		}
+	}
+
+	var dlqs []topologyNode
+	for arn, srcs := range sources {
+		if q, ok := byArn[arn]; ok {
+			dlqs = append(dlqs, topologyNode{queue: q, loaded: true, sources: len(srcs)})
+		} else {
+			dlqs = append(dlqs, topologyNode{queue: kue.Queue{Name: queueNameFromArn(arn), Arn: arn}, missing: true, sources: len(srcs)})
+		}
+	}
+	for _, q := range queues {
+		_, listed := listedSources[q.Key()]
+		if _, ok := sources[q.Arn]; !ok && (listed || looksLikeDeadLetterQueue(q)) {
+			dlqs = append(dlqs, topologyNode{queue: q, loaded: true, orphaned: true})
+		}
+	}
+	sort.Slice(dlqs, func(i, j int) bool { return dlqs[i].queue.Name < dlqs[j].queue.Name })
+
+	var nodes []topologyNode
+	for _, dlq := range dlqs {
+		parent := len(nodes)
+		nodes = append(nodes, dlq)
+
+		var srcs []topologyNode
+		for _, n := range sources[dlq.queue.Arn] {
+			srcs = append(srcs, n)
+		}
+		sort.Slice(srcs, func(i, j int) bool { return srcs[i].queue.Name < srcs[j].queue.Name })
+		for i, n := range srcs {
+			n.source = true
+			n.parent = parent
+			n.last = i == len(srcs)-1
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// topologySummary counts the dead-letter queues, sources and flagged queues.
+func topologySummary(nodes []topologyNode) string {
+	var dlqs, sources, orphaned, shared, missing int
+	for _, n := range nodes {
+		switch {
+		case n.source:
+			sources++
+			continue
+		case n.orphaned:
+			orphaned++
+		case n.missing:
+			missing++
+		}
+		if n.sources >= sharedDeadLetterThreshold {
+			shared++
+		}
+		dlqs++
+	}
+	return fmt.Sprintf("%d dead-letter queues · %d sources · %d orphaned · %d shared · %d missing",
+		dlqs, sources, orphaned, shared, missing)
+}
+
+// jumpToDeadLetterQueue moves the selection from a source queue to its
+// dead-letter queue.
+func (m model) jumpToDeadLetterQueue() model {
+	nodes := m.state.queueTopology.nodes
+	if sel := m.state.queueTopology.selected; sel < len(nodes) && nodes[sel].source {
+		m.state.queueTopology.selected = nodes[sel].parent
+	}
+	return m
+}
+
+// jumpToSource moves the selection from a dead-letter queue to its first
+// source, or from a source to the dead-letter queue entry of the same queue
+// when it is itself a dead-letter queue.
+func (m model) jumpToSource() model {
+	nodes := m.state.queueTopology.nodes
+	sel := m.state.queueTopology.selected
+	if sel >= len(nodes) {
+		return m
+	}
+
+	if !nodes[sel].source {
+		if sel+1 < len(nodes) && nodes[sel+1].source {
+			m.state.queueTopology.selected = sel + 1
+		}
+		return m
+	}
+	for i, n := range nodes {
+		if !n.source && n.queue.Url != "" && n.queue.Key() == nodes[sel].queue.Key() {
+			m.state.queueTopology.selected = i
+			break
+		}
+	}
+	return m
+}
+
+func (m model) QueueTopologyUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		nodes := m.state.queueTopology.nodes
+		switch {
+		case key.Matches(msg, m.keys.Down):
+			if m.state.queueTopology.selected < len(nodes)-1 {
+				m.state.queueTopology.selected++
+			}
+		case key.Matches(msg, m.keys.Up):
+			if m.state.queueTopology.selected > 0 {
+				m.state.queueTopology.selected--
+			}
+		case key.Matches(msg, m.keys.Left):
+			m = m.jumpToDeadLetterQueue()
+		case key.Matches(msg, m.keys.Right):
+			m = m.jumpToSource()
+		case key.Matches(msg, m.keys.View):
+			if sel := m.state.queueTopology.selected; sel < len(nodes) && nodes[sel].loaded {
+				m.state.queueDetails.queue = nodes[sel].queue
+				m.state.queueDetails.fromTopology = true
+				return m.QueueDetailsSwitchPage(msg)
+			}
+		case key.Matches(msg, m.keys.Quit):
+			m.error = ""
+			return m.SwitchPage(queueOverview), nil
+		}
+	}
+	return m, nil
+}
+
+func (m model) QueueTopologyView() string {
+	nodes := m.state.queueTopology.nodes
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.MediumGray)
+	summary := mutedStyle.Render(topologySummary(nodes))
+
+	if len(nodes) == 0 {
+		return summary + "\n\n" + mutedStyle.Render("No dead-letter queues found.")
+	}
+
+	warnStyle := styles.Warning()
+	dangerStyle := styles.Danger()
+	selectedStyle := styles.Highlight(styles.AccentColor)
+
+	// Keep the selection visible when the tree is taller than the content area
+	height := m.contentHeight() - 4
+	start := max(0, min(m.state.queueTopology.selected-height/2, len(nodes)-height))
+	end := min(len(nodes), start+height)
+
+	var lines []string
+	for i := start; i < end; i++ {
+		n := nodes[i]
+
+		prefix := ""
+		if n.source {
+			prefix = "  ├─ "
+			if n.last {
+				prefix = "  └─ "
+			}
+		}
+		name := runewidth.Truncate(prefix+n.queue.Name, 60, "…")
+
+		depth := "?"
+		if n.loaded {
+			depth = n.queue.ApproximateNumberOfMessages
+		}
+		cells := fmt.Sprintf("%-60s %8s msgs", name, depth)
+
+		var flags []string
+		switch {
+		case n.missing:
+			flags = append(flags, dangerStyle.Render("missing"))
+		case n.orphaned:
+			flags = append(flags, warnStyle.Render("orphaned"))
+		case !n.source && n.sources >= sharedDeadLetterThreshold:
+			flags = append(flags, warnStyle.Render(fmt.Sprintf("shared by %d", n.sources)))
+		case !n.source:
+			flags = append(flags, mutedStyle.Render(fmt.Sprintf("%d sources", n.sources)))
+		case !n.loaded:
+			flags = append(flags, mutedStyle.Render("not loaded"))
+		}
+
+		if i == m.state.queueTopology.selected {
+			cells = selectedStyle.Render(cells)
+		}
+		lines = append(lines, cells+"  "+strings.Join(flags, " "))
+	}
+
+	return summary + "\n\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
+)
+
+const testArnPrefix = "arn:aws:sqs:eu-west-1:123456789012:"
+
+func testQueue(name, dlq string) kue.Queue {
+	q := kue.Queue{
+		Name:                        name,
+		Url:                         "http://test/" + name,
+		Arn:                         testArnPrefix + name,
+		ApproximateNumberOfMessages: "1",
+	}
+	if dlq != "" {
+		q.DeadLetterTargetARN = testArnPrefix + dlq
+	}
+	return q
+}
+
+func nodeNames(nodes []topologyNode) []string {
+	var names []string
+	for _, n := range nodes {
+		name := n.queue.Name
+		if n.source {
+			name = "  " + name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestBuildTopology(t *testing.T) {
+	queues := []kue.Queue{
+		testQueue("orders", "orders-dlq"),
+		testQueue("orders-dlq", ""),
+		testQueue("payments", "shared-dlq"),
+		testQueue("refunds", "shared-dlq"),
+		testQueue("invoices", "shared-dlq"),
+		testQueue("shared-dlq", ""),
+		testQueue("unused-dlq", ""),
+		testQueue("emails", "deleted-dlq"),
+	}
+
+	nodes := buildTopology(queues, nil)
+
+	got := strings.Join(nodeNames(nodes), ",")
+	want := "deleted-dlq,  emails,orders-dlq,  orders,shared-dlq,  invoices,  payments,  refunds,unused-dlq"
+	if got != want {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+
+	if !nodes[0].missing {
+		t.Error("Expected deleted-dlq to be flagged as missing")
+	}
+	if nodes[4].sources != 3 || nodes[7].parent != 4 || !nodes[7].last {
+		t.Errorf("Unexpected shared dead-letter queue nodes: %+v", nodes[4:8])
+	}
+	if !nodes[8].orphaned {
+		t.Error("Expected unused-dlq to be flagged as orphaned")
+	}
+
+	summary := topologySummary(nodes)
+	if summary != "4 dead-letter queues · 5 sources · 1 orphaned · 1 shared · 1 missing" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+}
+
+func TestBuildTopologyListedSources(t *testing.T) {
+	queues := []kue.Queue{
+		testQueue("orders", "orders-dlq"),
+		testQueue("orders-dlq", ""),
+		testQueue("audit", ""),
+	}
+	listed := map[kue.QueueKey][]string{
+		{Url: "http://test/orders-dlq"}: {"http://test/orders", "http://test/legacy"},
+		{Url: "http://test/audit"}:      nil,
+	}
+
+	nodes := buildTopology(queues, listed)
+
+	got := strings.Join(nodeNames(nodes), ",")
+	want := "audit,orders-dlq,  legacy,  orders"
+	if got != want {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+	if !nodes[0].orphaned {
+		t.Error("Expected a listed queue without sources to be flagged as orphaned")
+	}
+	if nodes[2].loaded {
+		t.Error("Expected a source that is not loaded to be marked as such")
+	}
+}
+
+func TestQueueTopologyKeepsSourcesThatLoaded(t *testing.T) {
+	m := newTestModel()
+	m.state.queueOverview.queues = []kue.Queue{
+		testQueue("orders-dlq", ""),
+		testQueue("billing-dlq", ""),
+	}
+
+	m, _ = m.QueueTopologySwitchPage(nil)
+	updated, _ := m.Update(messages.DeadLetterSourcesLoadedMsg{
+		RequestID: m.requestID,
+		Sources:   map[kue.QueueKey][]string{{Url: "http://test/orders-dlq"}: {"http://test/legacy"}},
+		Errs:      map[kue.QueueKey]error{{Url: "http://test/billing-dlq"}: errors.New("AccessDenied")},
+	})
+	m = updated.(model)
+
+	if got := strings.Join(nodeNames(m.state.queueTopology.nodes), ","); got != "billing-dlq,orders-dlq,  legacy" {
+		t.Errorf("Expected the sources that were listed, got %q", got)
+	}
+	if !strings.Contains(m.statusMsg, "source queues of billing-dlq: AccessDenied") {
+		t.Errorf("Expected the failed queue in the status, got %q", m.statusMsg)
+	}
+}
+
+func TestQueueTopologyNavigation(t *testing.T) {
+	m := newTestModel()
+	m.state.queueOverview.queues = []kue.Queue{
+		testQueue("orders", "orders-dlq"),
+		testQueue("orders-dlq", "final-dlq"),
+		testQueue("final-dlq", ""),
+	}
+
+	m, cmd := m.QueueTopologySwitchPage(nil)
+	if m.page != queueTopology || cmd == nil {
+		t.Fatalf("Expected the topology page to load the dead-letter sources")
+	}
+	updated, _ := m.Update(messages.DeadLetterSourcesLoadedMsg{})
+	m = updated.(model)
+
+	// final-dlq, orders-dlq (source), orders-dlq, orders (source)
+	if got := strings.Join(nodeNames(m.state.queueTopology.nodes), ","); got != "final-dlq,  orders-dlq,orders-dlq,  orders" {
+		t.Fatalf("Unexpected topology %q", got)
+	}
+
+	right := tea.KeyMsg{Type: tea.KeyRight}
+	left := tea.KeyMsg{Type: tea.KeyLeft}
+
+	m, _ = m.QueueTopologyUpdate(right)
+	if m.state.queueTopology.selected != 1 {
+		t.Fatalf("Expected to jump to the first source, got %d", m.state.queueTopology.selected)
+	}
+	m, _ = m.QueueTopologyUpdate(right)
+	if m.state.queueTopology.selected != 2 {
+		t.Fatalf("Expected to jump to the source's own dead-letter queue entry, got %d", m.state.queueTopology.selected)
+	}
+	m, _ = m.QueueTopologyUpdate(right)
+	m, _ = m.QueueTopologyUpdate(left)
+	if m.state.queueTopology.selected != 2 {
+		t.Fatalf("Expected to jump back to the dead-letter queue, got %d", m.state.queueTopology.selected)
+	}
+
+	view := m.QueueTopologyView()
+	if !strings.Contains(view, "└─ orders") {
+		t.Errorf("Expected the view to render the tree, got:\n%s", view)
+	}
+
+	m, _ = m.QueueTopologyUpdate(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.page != queueDetails || !m.state.queueDetails.fromTopology {
+		t.Fatalf("Expected enter to open the queue details")
+	}
+	m.loading = false
+	m, _ = m.QueueDetailsUpdate(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.page != queueTopology {
+		t.Errorf("Expected to return to the topology, got page %d", m.page)
+	}
+}
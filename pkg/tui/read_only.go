@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
+)
+
+// readOnly reports whether every session in use is read-only, in which case
+// the keybindings of mutating actions are disabled.
+func (m model) readOnly() bool {
+	if m.aggregating() {
+		for _, c := range m.contexts {
+			if !c.session.Options.ReadOnly {
+				return false
+			}
+		}
+		return true
+	}
+	return m.session != nil && m.session.Options.ReadOnly
+}
+
+// readOnlyContexts returns the names of the read-only contexts of the
+// aggregated overview.
+func (m model) readOnlyContexts() []string {
+	var names []string
+	for _, c := range m.contexts {
+		if c.session.Options.ReadOnly {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+// applyReadOnly disables the keybindings of mutating actions when every
+// session in use is read-only, and enables them again otherwise.
+func (m model) applyReadOnly() model {
+	m.keys = m.keys.ReadOnly(m.readOnly())
+	return m
+}
+
+// refuseReadOnly returns why an action on the queues is refused, or an empty
+// string when none of them is read-only. Queues of read-only contexts can
+// still be selected while the overview also shows writable contexts. New
+// queues are checked with an empty key.
+func (m model) refuseReadOnly(queues ...kue.QueueKey) string {
+	for _, q := range queues {
+		if c := m.contextOf(q); c != nil {
+			if c.session.Options.ReadOnly {
+				return fmt.Sprintf("%s is in the read-only context %s", queueNameFromUrl(q.Url), c.name)
+			}
+			continue
+		}
+		if m.session != nil && m.session.Options.ReadOnly {
+			if m.aggregating() {
+				return fmt.Sprintf("New queues are created in the read-only context %s", m.contexts[0].name)
+			}
+			return "Kue is read-only"
+		}
+	}
+	return ""
+}
+
+// renderReadOnlyBadge renders a badge while actions are refused, naming the
+// read-only contexts when only some of them are.
+func (m model) renderReadOnlyBadge() string {
+	badge := lipgloss.NewStyle().Bold(true).Padding(0, 1).
+		Inherit(styles.Highlight(styles.DangerRed)).Render("READ-ONLY")
+	if m.readOnly() {
+		return badge
+	}
+	if names := m.readOnlyContexts(); len(names) > 0 {
+		return badge + " " + strings.Join(names, ", ")
+	}
+	return ""
+}
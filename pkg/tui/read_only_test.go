@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/kue"
+)
+
+func TestReadOnlyDisablesMutatingActions(t *testing.T) {
+	session := newTestSession(t, "prod")
+	session.Options.ReadOnly = true
+	m := newTestModel().useSession(session)
+	m.state.queueOverview.queues = []kue.Queue{{Name: "orders", Url: "http://test/orders"}}
+	m = m.updateQueueOverviewTableFiltered()
+
+	if m.keys.Purge.Enabled() || m.keys.Delete.Enabled() || m.keys.Create.Enabled() {
+		t.Fatal("Expected the mutating keybindings to be disabled")
+	}
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if page := result.(model).page; page != queueOverview {
+		t.Errorf("Expected purge to be ignored, got page %v", views[page])
+	}
+	if help := m.renderHelpContent(); strings.Contains(help, "purge queue") || strings.Contains(help, "create new") {
+		t.Error("Expected the help to hide the mutating actions")
+	}
+	if !strings.Contains(m.View(), "READ-ONLY") {
+		t.Error("Expected the read-only badge in the header")
+	}
+
+	m.state.queuePurge.queue = m.state.queueOverview.queues[0]
+	m, _ = m.QueuePurgeSwitchPage(nil)
+	if m.page == queuePurge || m.error != "Kue is read-only" {
+		t.Errorf("Expected the purge to be refused, got page %v and error %q", views[m.page], m.error)
+	}
+
+	// Switching to a writable profile enables the actions again
+	m = m.useSession(newTestSession(t, "dev"))
+	if !m.keys.Purge.Enabled() || m.renderReadOnlyBadge() != "" {
+		t.Error("Expected the actions to be enabled for a writable session")
+	}
+}
+
+func TestReadOnlyContext(t *testing.T) {
+	m := newTestModel()
+	m.config.AWS.Contexts = []config.ContextSettings{{Name: "prod"}, {Name: "staging"}}
+	prod := newTestSession(t, "prod")
+	prod.Options.ReadOnly = true
+	m = m.useContexts(map[string]*client.Session{
+		"prod":    prod,
+		"staging": newTestSession(t, "staging"),
+	})
+	m.state.queueOverview.queues = []kue.Queue{
+		{Name: "orders", Url: "http://prod/orders", Context: "prod"},
+		{Name: "orders", Url: "http://staging/orders", Context: "staging"},
+	}
+
+	if !m.keys.Purge.Enabled() {
+		t.Fatal("Expected the actions to stay enabled for the writable context")
+	}
+	if badge := m.renderReadOnlyBadge(); !strings.Contains(badge, "READ-ONLY") || !strings.Contains(badge, "prod") {
+		t.Errorf("Expected the badge to name the read-only context, got %q", badge)
+	}
+
+	m.state.queueDelete.queues = m.state.queueOverview.queues
+	refused, _ := m.QueueDeleteSwitchPage(nil)
+	if refused.page == queueDelete || refused.error != "orders is in the read-only context prod" {
+		t.Errorf("Expected deleting a prod queue to be refused, got error %q", refused.error)
+	}
+
+	m.state.queuePurge.queue = m.state.queueOverview.queues[1]
+	purged, _ := m.QueuePurgeSwitchPage(nil)
+	if purged.page != queuePurge {
+		t.Errorf("Expected purging a staging queue to be confirmed, got error %q", purged.error)
+	}
+
+	if reason := m.refuseReadOnly(kue.QueueKey{}); !strings.Contains(reason, "read-only context prod") {
+		t.Errorf("Expected creating queues in the first context to be refused, got %q", reason)
+	}
+}
@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/tui/commands"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
+)
+
+// transientError reports a request that failed after its retries in the
+// status bar instead of replacing the page with an error, since the next
+// refresh is likely to succeed.
+func (m model) transientError(action string, err error) (model, tea.Cmd) {
+	reason := "AWS is unavailable"
+	switch {
+	case client.IsThrottled(err):
+		reason = "throttled by AWS"
+	case errors.Is(err, context.DeadlineExceeded):
+		reason = "timed out"
+	}
+	slog.Warn("request failed after retrying", "action", action, "err", err)
+	m.statusMsg = fmt.Sprintf("%s %s, trying again in %s", action, reason, m.config.RefreshInterval)
+	return m, commands.ClearStatusAfter(5 * time.Second)
+}
+
+// renderRetryStatus shows the requests being retried, or nothing when no
+// request is being retried.
+func (m model) renderRetryStatus() string {
+	s := m.retryStatus
+	if s.Retrying == 0 {
+		return ""
+	}
+	plural := "s"
+	if s.Retrying == 1 {
+		plural = ""
+	}
+	if s.Throttled > 0 {
+		return styles.Warning().Render(fmt.Sprintf("⟳ throttled, retrying %d request%s", s.Retrying, plural))
+	}
+	return lipgloss.NewStyle().Foreground(styles.MediumGray).Render(fmt.Sprintf("⟳ retrying %d request%s", s.Retrying, plural))
+}
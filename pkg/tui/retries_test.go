@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+
+	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
+)
+
+func TestThrottledLoadKeepsOverview(t *testing.T) {
+	m := newTestModel().newPageContext()
+	m.state.queueOverview.queues = []kue.Queue{{Name: "orders", Url: "http://test/orders"}}
+
+	throttled := &retry.MaxAttemptsError{Attempt: 5, Err: &smithy.GenericAPIError{Code: "ThrottlingException"}}
+	result, cmd := m.Update(messages.QueuesLoadedMsg{RequestID: m.requestID, Err: throttled})
+	m = result.(model)
+
+	if m.error != "" {
+		t.Errorf("Expected no error view for a throttled request, got %q", m.error)
+	}
+	if !strings.Contains(m.statusMsg, "throttled by AWS") {
+		t.Errorf("Expected the status bar to mention throttling, got %q", m.statusMsg)
+	}
+	if len(m.state.queueOverview.queues) != 1 {
+		t.Error("Expected the queues of the last refresh to be kept")
+	}
+	if cmd == nil {
+		t.Error("Expected the overview to keep refreshing")
+	}
+}
+
+func TestRetryStatusIndicator(t *testing.T) {
+	m := newTestModel()
+	if got := m.renderRetryStatus(); got != "" {
+		t.Errorf("Expected no indicator without retries, got %q", got)
+	}
+
+	result, _ := m.Update(messages.RetryStatusMsg{Status: client.RetryStatus{Retrying: 2, Throttled: 1}})
+	m = result.(model)
+	if got := m.renderRetryStatus(); !strings.Contains(got, "throttled, retrying 2 requests") {
+		t.Errorf("Expected the throttled requests being retried, got %q", got)
+	}
+	if !strings.Contains(m.View(), "retrying 2 requests") {
+		t.Error("Expected the indicator in the status bar")
+	}
+
+	result, _ = m.Update(messages.RetryStatusMsg{Status: client.RetryStatus{Retrying: 1}})
+	if got := result.(model).renderRetryStatus(); !strings.Contains(got, "⟳ retrying 1 request") {
+		t.Errorf("Expected the request being retried, got %q", got)
+	}
+}
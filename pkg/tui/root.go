@@ -3,12 +3,20 @@ package tui
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/kontrolplane/kue/pkg/alert"
+	"github.com/kontrolplane/kue/pkg/audit"
 	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/config"
+	"github.com/kontrolplane/kue/pkg/decode"
+	"github.com/kontrolplane/kue/pkg/history"
+	"github.com/kontrolplane/kue/pkg/trash"
 
 	tea "github.com/charmbracelet/bubbletea"
 	keys "github.com/kontrolplane/kue/pkg/keys"
@@ -20,15 +28,36 @@ import (
 func NewModel(
 	projectName string,
 	programName string,
+	cfg config.Config,
+	uiState config.State,
 ) (tea.Model, error) {
 
 	ctx := context.Background()
 
-	sqsClient, awsInfo, err := client.CreateSqsClient(ctx)
+	mfa := client.NewMFAPrompt()
+	retries := client.NewRetryMonitor()
+	session, err := client.NewSession(ctx, awsOptions(cfg, mfa, retries, cfg.AWS.Profile, cfg.AWS.Region))
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create SQS client: %w", err)
 	}
 
+	alertRules, err := alert.Compile(cfg.Alerts.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alert configuration: %w", err)
+	}
+
+	keyMap, err := keys.New(cfg.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key bindings: %w", err)
+	}
+
+	// NO_COLOR disables colors when set to any non-empty value, see no-color.org
+	theme, err := styles.NewTheme(cfg.Theme.Name, cfg.Theme.Colors, os.Getenv("NO_COLOR") != "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid theme: %w", err)
+	}
+	styles.Apply(theme)
+
 	queueOverviewTable := initQueueOverviewTable(defaultTableHeight)
 
 	m := model{
@@ -36,12 +65,20 @@ func NewModel(
 		programName: programName,
 		page:        queueOverview,
 		context:     ctx,
-		client:      sqsClient,
-		awsInfo:     awsInfo,
+		mfa:         mfa,
+		retries:     retries,
+		auditLog:    audit.Open(cfg.Audit.File),
+		user:        audit.CurrentUser(),
+		trash:       trash.Open(cfg.Trash.File, cfg.Trash.Retention),
+		config:      cfg,
+		uiState:     uiState,
+		decoders:    decode.NewRegistry(),
+		history:     history.NewStore(history.DefaultCapacity),
+		alertRules:  alertRules,
 		loading:     true,
 		loadingMsg:  "Loading queues...",
 
-		keys: keys.Keys,
+		keys: keyMap,
 
 		state: state{
 			queueOverview: queueOverviewState{
@@ -50,6 +87,8 @@ func NewModel(
 				queues:        nil,
 				selectedItems: make(map[int]bool),
 				filterInput:   initFilterInput(),
+				sortColumn:    uiState.Overview.SortColumn,
+				sortDesc:      uiState.Overview.SortDescending,
 			},
 			queueDetails: queueDetailsState{
 				selected:        0,
@@ -64,16 +103,42 @@ func NewModel(
 		},
 	}
 
+	m = m.useSession(session).newPageContext()
+
+	if len(cfg.AWS.Contexts) > 0 {
+		sessions, err := client.NewSessions(ctx, contextsOptions(cfg, mfa, retries))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create SQS clients: %w", err)
+		}
+		m = m.useContexts(sessions)
+	}
+
 	return m, nil
 }
 
 func (m model) Init() tea.Cmd {
-	return commands.LoadQueues(m.context, m.client)
+	return tea.Batch(
+		m.loadQueues(),
+		commands.LoadAccountID(m.context, m.session),
+		commands.WaitForMFARequest(m.mfa),
+		commands.WaitForRetryStatus(m.retries),
+	)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	// Results of requests made by a page that has since been left are stale
+	if id, ok := messages.RequestIDOf(msg); ok && id != m.requestID {
+		return m, nil
+	}
+
+	// Requests failing because the credentials expired are resumed once the
+	// credentials have been resolved again
+	if problem := client.ClassifyCredentialsError(messages.ErrorOf(msg)); problem != client.CredentialsValid {
+		return m.credentialsExpired(messages.ErrorOf(msg), problem)
+	}
+
 	switch msg := msg.(type) {
 
 	case tea.WindowSizeMsg:
@@ -94,35 +159,179 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.QueuesLoadedMsg:
 		m.loading = false
 		m.loadingMsg = ""
-		if msg.Err != nil {
+		if client.IsTransient(msg.Err) {
+			var statusCmd tea.Cmd
+			m, statusCmd = m.transientError("Loading queues", msg.Err)
+			cmds = append(cmds, statusCmd)
+			if m.page == queueOverview {
+				cmds = append(cmds, commands.ScheduleRefresh(m.config.RefreshInterval, "queueOverview"))
+			}
+		} else if msg.Err != nil {
 			m.error = fmt.Sprintf("Error loading queues: %v", msg.Err)
 		} else {
 			m.error = ""
-			m.state.queueOverview.queues = msg.Queues
-			m = m.updateQueueOverviewTable()
+			if len(msg.ContextErrors) > 0 {
+				m.statusMsg = contextErrorsStatus(msg.ContextErrors)
+				cmds = append(cmds, commands.ClearStatusAfter(5*time.Second))
+			}
+			var loadCmd tea.Cmd
+			m, loadCmd = m.queuesLoaded(msg)
+			cmds = append(cmds, loadCmd)
 			if m.page == queueOverview {
-				cmds = append(cmds, commands.ScheduleRefresh("queueOverview"))
+				cmds = append(cmds, commands.ScheduleRefresh(m.config.RefreshInterval, "queueOverview"))
 			}
 		}
 
-	case messages.QueueAttributesLoadedMsg:
+	case messages.QueueAttributesStreamedMsg:
+		var streamCmd tea.Cmd
+		m, streamCmd = m.queueAttributesStreamed(msg)
+		cmds = append(cmds, streamCmd)
+
+	case messages.OldestMessageAgesLoadedMsg:
+		if len(msg.Errs) > 0 {
+			m.statusMsg = m.oldestAgeErrorsStatus(msg.Errs)
+			cmds = append(cmds, commands.ClearStatusAfter(5*time.Second))
+		}
+		m.state.queueOverview.oldestAges = msg.Ages
+		var alertCmd tea.Cmd
+		m, alertCmd = m.evaluateAlerts()
+		cmds = append(cmds, alertCmd)
+
+	case messages.AlertsNotifiedMsg:
+		if msg.Err != nil {
+			m.statusMsg = fmt.Sprintf("Failed to notify alerts: %v", msg.Err)
+			cmds = append(cmds, commands.ClearStatusAfter(5*time.Second))
+		}
+
+	case messages.MFARequestedMsg:
+		return m.mfaRequested(msg)
+
+	case messages.CooldownTickMsg:
+		// Counts down until the confirm button of a protected queue is active
+		if m.activeProtection().cooldown() > 0 {
+			cmds = append(cmds, commands.CooldownTick())
+		}
+
+	case messages.AuditLogLoadedMsg:
+		m = m.auditLogLoaded(msg)
+
+	case messages.TrashLoadedMsg:
+		m = m.trashLoaded(msg)
+
+	case messages.MessageRestoredMsg:
+		m.loading = false
+		m.loadingMsg = ""
+		m.state.trash.form = nil
+		if msg.Err != nil {
+			m.error = fmt.Sprintf("Error restoring message: %v", msg.Err)
+		} else {
+			m.statusMsg = "Message restored"
+			cmds = append(cmds, commands.ClearStatusAfter(3*time.Second))
+		}
+		// A message sent but not removed from the trash is still listed
+		cmds = append(cmds, commands.LoadTrash(m.trash))
+
+	case messages.TrashPurgedMsg:
+		m.loading = false
+		m.loadingMsg = ""
+		m.state.trash.purging = false
+		if msg.Err != nil {
+			m.error = fmt.Sprintf("Error purging trash: %v", msg.Err)
+		} else {
+			m.state.trash.entries = nil
+			m.state.trash.selected = 0
+			m.statusMsg = "Trash purged"
+			cmds = append(cmds, commands.ClearStatusAfter(3*time.Second))
+		}
+
+	case messages.RetryStatusMsg:
+		m.retryStatus = msg.Status
+		cmds = append(cmds, commands.WaitForRetryStatus(m.retries))
+
+	case messages.CredentialsRefreshedMsg:
+		return m.credentialsRefreshed(msg)
+
+	case messages.AWSContextSwitchedMsg:
+		m.loading = false
+		m.loadingMsg = ""
 		if msg.Err != nil {
+			m.error = fmt.Sprintf("Error switching to profile %s: %v", msg.Options.Profile, msg.Err)
+			m = m.SwitchPage(queueOverview)
+		} else {
+			var switchCmd tea.Cmd
+			m, switchCmd = m.switchAWSContext(msg)
+			cmds = append(cmds, switchCmd)
+		}
+
+	case messages.ContextsOpenedMsg:
+		m.loading = false
+		m.loadingMsg = ""
+		if msg.Err != nil {
+			m.error = fmt.Sprintf("Error switching to the configured contexts: %v", msg.Err)
+			m = m.SwitchPage(queueOverview)
+		} else {
+			var switchCmd tea.Cmd
+			m, switchCmd = m.switchToContexts(msg)
+			cmds = append(cmds, switchCmd)
+		}
+
+	case messages.AccountIDLoadedMsg:
+		// Ignore accounts resolved for a profile that is no longer used
+		if msg.Session != m.session {
+			break
+		}
+		if msg.Err != nil {
+			m.statusMsg = "Failed to resolve the AWS account ID"
+			cmds = append(cmds, commands.ClearStatusAfter(5*time.Second))
+		} else {
+			m.awsInfo.Account = msg.Account
+		}
+
+	case messages.DeadLetterSourcesLoadedMsg:
+		m.loading = false
+		m.loadingMsg = ""
+		if len(msg.Errs) > 0 {
+			m.statusMsg = m.sourceErrorsStatus(msg.Errs)
+			cmds = append(cmds, commands.ClearStatusAfter(5*time.Second))
+		}
+		m.state.queueTopology.sources = msg.Sources
+		m.state.queueTopology.nodes = buildTopology(m.state.queueOverview.queues, msg.Sources)
+		m.state.queueTopology.selected = min(m.state.queueTopology.selected, max(0, len(m.state.queueTopology.nodes)-1))
+
+	case messages.QueueAttributesLoadedMsg:
+		if client.IsTransient(msg.Err) {
+			var statusCmd tea.Cmd
+			m, statusCmd = m.transientError("Loading queue attributes", msg.Err)
+			cmds = append(cmds, statusCmd)
+		} else if msg.Err != nil {
 			m.error = fmt.Sprintf("Error fetching queue attributes: %v", msg.Err)
 		} else {
+			msg.Queue.Context = m.state.queueDetails.queue.Context
 			m.state.queueDetails.queue = msg.Queue
 			m.state.queueDetails.attributesTable = renderAttributesTable(msg.Queue)
+			if m.history != nil {
+				m.history.Record(time.Now(), msg.Queue)
+			}
 		}
 
 	case messages.MessagesLoadedMsg:
 		m.loading = false
 		m.loadingMsg = ""
-		if msg.Err != nil {
+		if client.IsTransient(msg.Err) {
+			var statusCmd tea.Cmd
+			m, statusCmd = m.transientError("Loading messages", msg.Err)
+			cmds = append(cmds, statusCmd)
+			if m.page == queueDetails {
+				cmds = append(cmds, commands.ScheduleRefresh(m.config.RefreshInterval, "queueDetails"))
+			}
+		} else if msg.Err != nil {
 			m.error = fmt.Sprintf("Error fetching messages: %v", msg.Err)
 		} else {
 			m.state.queueDetails.messages = msg.Messages
+			m.state.queueDetails.decodedBodies = m.decodeMessageBodies(m.state.queueDetails.queue.Name, msg.Messages)
 			m = m.updateMessagesTable()
 			if m.page == queueDetails {
-				cmds = append(cmds, commands.ScheduleRefresh("queueDetails"))
+				cmds = append(cmds, commands.ScheduleRefresh(m.config.RefreshInterval, "queueDetails"))
 			}
 		}
 
@@ -133,7 +342,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.error = fmt.Sprintf("Error creating queue: %v", msg.Err)
 		}
 		m = m.SwitchPage(queueOverview)
-		cmds = append(cmds, commands.LoadQueues(m.context, m.client))
+		cmds = append(cmds, m.loadQueues())
 
 	case messages.QueueDeletedMsg:
 		m.loading = false
@@ -144,7 +353,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.state.queueDelete.selected = 0
 		m.state.queueOverview.selectedItems = make(map[int]bool) // Clear selection after deletion
 		m = m.SwitchPage(queueOverview)
-		cmds = append(cmds, commands.LoadQueues(m.context, m.client))
+		cmds = append(cmds, m.loadQueues())
 
 	case messages.MessageDeletedMsg:
 		m.loading = false
@@ -152,7 +361,6 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Err != nil {
 			m.error = fmt.Sprintf("Error deleting message: %v", msg.Err)
 		} else {
-			queueUrl := m.state.queueDetails.queue.Url
 			if m.page != queueDetails {
 				m = m.SwitchPage(queueDetails)
 			}
@@ -160,10 +368,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.state.queueDetails.selected >= len(m.state.queueDetails.messages)-1 && m.state.queueDetails.selected > 0 {
 				m.state.queueDetails.selected--
 			}
-			cmds = append(cmds, tea.Batch(
-				commands.LoadQueueAttributes(m.context, m.client, queueUrl),
-				commands.LoadMessages(m.context, m.client, queueUrl, 10),
-			))
+			cmds = append(cmds, m.loadQueueDetails(m.state.queueDetails.queue.Key()))
 		}
 
 	case messages.MessageCreatedMsg:
@@ -172,12 +377,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Err != nil {
 			m.error = fmt.Sprintf("Error sending message: %v", msg.Err)
 		} else {
-			queueUrl := m.state.queueMessageCreate.queueUrl
 			m = m.SwitchPage(queueDetails)
-			cmds = append(cmds, tea.Batch(
-				commands.LoadQueueAttributes(m.context, m.client, queueUrl),
-				commands.LoadMessages(m.context, m.client, queueUrl, 10),
-			))
+			cmds = append(cmds, m.loadQueueDetails(m.state.queueMessageCreate.queue))
 		}
 
 	case messages.QueueRedriveStartedMsg:
@@ -192,7 +393,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, commands.ScheduleRedrivePoll(
 				3*time.Second,
 				m.context,
-				m.client,
+				m.clientFor(m.state.queueRedrive.queue.Key()),
 				m.state.queueRedrive.queue.Arn,
 			))
 		}
@@ -213,7 +414,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmds = append(cmds, commands.ScheduleRedrivePoll(
 					3*time.Second,
 					m.context,
-					m.client,
+					m.clientFor(m.state.queueRedrive.queue.Key()),
 					m.state.queueRedrive.queue.Arn,
 				))
 			}
@@ -226,14 +427,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.error = fmt.Sprintf("Error purging queue: %v", msg.Err)
 		} else if m.state.queuePurge.fromOverview {
 			m = m.SwitchPage(queueOverview)
-			cmds = append(cmds, commands.LoadQueues(m.context, m.client))
+			cmds = append(cmds, m.loadQueues())
 		} else {
-			queueUrl := m.state.queuePurge.queue.Url
 			m = m.SwitchPage(queueDetails)
-			cmds = append(cmds, tea.Batch(
-				commands.LoadQueueAttributes(m.context, m.client, queueUrl),
-				commands.LoadMessages(m.context, m.client, queueUrl, 10),
-			))
+			cmds = append(cmds, m.loadQueueDetails(m.state.queuePurge.queue.Key()))
 		}
 
 	case messages.ClipboardCopiedMsg:
@@ -244,21 +441,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		cmds = append(cmds, commands.ClearStatusAfter(2*time.Second))
 
+	case messages.StateSavedMsg:
+		if msg.Err != nil {
+			m.statusMsg = "Failed to save view settings"
+			cmds = append(cmds, commands.ClearStatusAfter(2*time.Second))
+		}
+
 	case messages.StatusClearMsg:
 		m.statusMsg = ""
 
+	case messages.BellStoppedMsg:
+		m.bell = false
+
 	case messages.RefreshTickMsg:
 		switch msg.Page {
 		case "queueOverview":
 			if m.page == queueOverview {
-				cmds = append(cmds, commands.LoadQueues(m.context, m.client))
+				cmds = append(cmds, m.loadQueues())
 			}
 		case "queueDetails":
 			if m.page == queueDetails && m.state.queueDetails.queue.Url != "" {
-				cmds = append(cmds, tea.Batch(
-					commands.LoadQueueAttributes(m.context, m.client, m.state.queueDetails.queue.Url),
-					commands.LoadMessages(m.context, m.client, m.state.queueDetails.queue.Url, 10),
-				))
+				cmds = append(cmds, m.loadQueueDetails(m.state.queueDetails.queue.Key()))
 			}
 		}
 	}
@@ -267,6 +470,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch m.page {
 	case queueOverview:
 		m, cmd = m.QueueOverviewUpdate(msg)
+		if _, ok := msg.(tea.KeyMsg); ok && m.page == queueOverview {
+			// Load the attributes of queues scrolled or filtered into view
+			var loadCmd tea.Cmd
+			m, loadCmd = m.loadAttributes(false)
+			cmd = tea.Batch(cmd, loadCmd)
+		}
 	case queueDetails:
 		m, cmd = m.QueueDetailsUpdate(msg)
 	case queueCreate:
@@ -283,6 +492,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m, cmd = m.QueueMessageDeleteUpdate(msg)
 	case queueMessageCreate:
 		m, cmd = m.QueueMessageCreateUpdate(msg)
+	case queueTopology:
+		m, cmd = m.QueueTopologyUpdate(msg)
+	case auditLog:
+		m, cmd = m.AuditLogUpdate(msg)
+	case messageTrash:
+		m, cmd = m.TrashUpdate(msg)
+	case awsContext:
+		m, cmd = m.AWSContextUpdate(msg)
+	case credentials:
+		m, cmd = m.CredentialsUpdate(msg)
 	}
 
 	if cmd != nil {
@@ -294,10 +513,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m model) View() string {
 	h := formatHeader(m.projectName, m.programName, views[m.page], m.awsInfo)
+	if m.aggregating() {
+		h = formatContextsHeader(m.projectName, m.programName, views[m.page], m.contextNames())
+	}
+	if badge := m.renderEndpointBadges(); badge != "" {
+		h += " • " + badge
+	}
+	if badge := m.renderReadOnlyBadge(); badge != "" {
+		h += " • " + badge
+	}
+	if badge := m.renderAlertBadge(); badge != "" {
+		h += " • " + badge
+	}
 	f := m.renderFooter()
+	if retries := m.renderRetryStatus(); retries != "" {
+		f = retries + "  " + f
+	}
 	var c string
 
-	if m.loading {
+	// MFA prompts are shown while the request waiting for the code is loading
+	if m.loading && m.state.credentials.mfaRequest == nil {
 		c = m.loadingMsg
 		if c == "" {
 			c = "Loading..."
@@ -322,6 +557,16 @@ func (m model) View() string {
 			c = m.QueueMessageDeleteView()
 		case queueMessageCreate:
 			c = m.QueueMessageCreateView()
+		case queueTopology:
+			c = m.QueueTopologyView()
+		case auditLog:
+			c = m.AuditLogView()
+		case messageTrash:
+			c = m.TrashView()
+		case awsContext:
+			c = m.AWSContextView()
+		case credentials:
+			c = m.CredentialsView()
 		default:
 			c = errNoPageSelected
 		}
@@ -331,7 +576,7 @@ func (m model) View() string {
 		c = m.ErrorView()
 	}
 
-	fixedContent := lipgloss.Place(contentWidth, contentHeight, lipgloss.Center, lipgloss.Top, c)
+	fixedContent := lipgloss.Place(m.contentWidth(), m.contentHeight(), lipgloss.Center, lipgloss.Top, c)
 	bordered := styles.MainBorder.Render(fixedContent)
 	mainView := h + "\n\n" + bordered + "\n\n" + f
 
@@ -339,7 +584,11 @@ func (m model) View() string {
 		mainView = m.renderHelpOverlay(mainView)
 	}
 
-	return styles.ContentWrapper(m.width, m.height).Render(mainView)
+	view := styles.ContentWrapper(m.width, m.height).Render(mainView)
+	if m.bell {
+		view += "\a"
+	}
+	return view
 }
 
 func (m model) renderFooter() string {
@@ -350,40 +599,58 @@ func (m model) renderFooter() string {
 
 	// Show filter input when filtering
 	if m.page == queueOverview && m.state.queueOverview.filtering {
-		return m.renderFilterBar(m.state.queueOverview.filterInput.View())
+		return m.renderFilterBar(m.state.queueOverview.filterInput.View()) +
+			m.renderFilterError(m.state.queueOverview.filterErr)
 	}
 	if m.page == queueDetails && m.state.queueDetails.filtering {
-		return m.renderFilterBar(m.state.queueDetails.filterInput.View())
+		return m.renderFilterBar(m.state.queueDetails.filterInput.View()) +
+			m.renderFilterError(m.state.queueDetails.filterErr)
+	}
+	if m.page == queueMessageDetails && m.state.queueMessageDetails.body.searching {
+		return m.renderSearchBar(m.state.queueMessageDetails.body)
 	}
 
 	// Show filter status if filter is active
 	if m.page == queueOverview && m.state.queueOverview.filterText != "" {
-		return m.renderFilterStatus(m.state.queueOverview.filterText)
+		return m.renderFilterStatus(m.state.queueOverview.filterText) +
+			m.renderFilterError(m.state.queueOverview.filterErr)
 	}
 	if m.page == queueDetails && m.state.queueDetails.filterText != "" {
-		return m.renderFilterStatus(m.state.queueDetails.filterText)
+		return m.renderFilterStatus(m.state.queueDetails.filterText) +
+			m.renderFilterError(m.state.queueDetails.filterErr)
+	}
+	if m.page == queueMessageDetails && m.state.queueMessageDetails.body.query != "" {
+		return m.renderSearchStatus(m.state.queueMessageDetails.body)
 	}
 
 	// Show selection info if items are selected
 	if m.page == queueOverview && len(m.state.queueOverview.selectedItems) > 0 {
-		return m.renderSelectionInfo(len(m.state.queueOverview.selectedItems), "queue")
+		return m.renderSelectionInfo(len(m.state.queueOverview.selectedItems), "queue", m.keys.Delete)
 	}
 	if m.page == queueDetails && len(m.state.queueDetails.selectedItems) > 0 {
-		return m.renderSelectionInfo(len(m.state.queueDetails.selectedItems), "message")
+		return m.renderSelectionInfo(len(m.state.queueDetails.selectedItems), "message", m.keys.DeleteMessage)
+	}
+
+	if progress := m.attributesProgress(); m.page == queueOverview && progress != "" {
+		return lipgloss.NewStyle().Foreground(styles.MediumGray).Render(progress)
 	}
 
 	return m.renderShortHelp()
 }
 
-func (m model) renderSelectionInfo(count int, itemType string) string {
+func (m model) renderSelectionInfo(count int, itemType string, deleteKey key.Binding) string {
 	selectionStyle := lipgloss.NewStyle().Foreground(styles.AccentColor)
 	helpStyle := lipgloss.NewStyle().Foreground(styles.MediumGray)
 	plural := "s"
 	if count == 1 {
 		plural = ""
 	}
+	hint := fmt.Sprintf("  (%s to clear)", m.keys.Quit.Help().Key)
+	if deleteKey.Enabled() {
+		hint = fmt.Sprintf("  (%s to delete, %s to clear)", deleteKey.Help().Key, m.keys.Quit.Help().Key)
+	}
 	return selectionStyle.Render(fmt.Sprintf("%d %s%s selected", count, itemType, plural)) +
-		helpStyle.Render("  (ctrl+d to delete, q to clear)")
+		helpStyle.Render(hint)
 }
 
 func (m model) renderFilterBar(inputView string) string {
@@ -394,12 +661,39 @@ func (m model) renderFilterBar(inputView string) string {
 func (m model) renderFilterStatus(filterText string) string {
 	filterStyle := lipgloss.NewStyle().Foreground(styles.AccentColor)
 	helpStyle := lipgloss.NewStyle().Foreground(styles.MediumGray)
-	return filterStyle.Render("Filter: "+filterText) + helpStyle.Render("  (q to clear)")
+	return filterStyle.Render("Filter: "+filterText) + helpStyle.Render("  ("+m.keys.Quit.Help().Key+" to clear)")
+}
+
+func (m model) renderFilterError(err error) string {
+	if err == nil {
+		return ""
+	}
+	errorStyle := styles.Danger()
+	return errorStyle.Render("  " + err.Error())
+}
+
+func (m model) renderSearchBar(body bodyViewer) string {
+	labelStyle := lipgloss.NewStyle().Foreground(styles.AccentColor)
+	helpStyle := lipgloss.NewStyle().Foreground(styles.MediumGray)
+	return labelStyle.Render("Search: ") + body.searchInput.View() +
+		helpStyle.Render("  "+body.MatchStatus()) + "  (enter to confirm, esc to cancel)"
+}
+
+func (m model) renderSearchStatus(body bodyViewer) string {
+	searchStyle := lipgloss.NewStyle().Foreground(styles.AccentColor)
+	helpStyle := lipgloss.NewStyle().Foreground(styles.MediumGray)
+	return searchStyle.Render("Search: "+body.query+"  "+body.MatchStatus()) +
+		helpStyle.Render(fmt.Sprintf("  (%s/%s next/previous, %s to clear)",
+			m.keys.NextMatch.Help().Key, m.keys.PrevMatch.Help().Key, m.keys.Quit.Help().Key))
 }
 
 func (m model) renderShortHelp() string {
 	helpStyle := lipgloss.NewStyle().Foreground(styles.MediumGray)
-	return helpStyle.Render("enter view • ? help • / filter • q quit")
+	var parts []string
+	for _, b := range m.keys.ShortHelp() {
+		parts = append(parts, b.Help().Key+" "+b.Help().Desc)
+	}
+	return helpStyle.Render(strings.Join(parts, " • "))
 }
 
 func (m model) renderHelpOverlay(background string) string {
@@ -411,7 +705,7 @@ func (m model) renderHelpOverlay(background string) string {
 		Padding(1, 3).
 		Render(helpContent)
 
-	return lipgloss.Place(contentWidth+4, contentHeight+10,
+	return lipgloss.Place(m.contentWidth()+4, m.contentHeight()+10,
 		lipgloss.Center, lipgloss.Center,
 		overlay,
 	)
@@ -434,27 +728,55 @@ func (m model) renderHelpContent() string {
 		return keyStyle.Render(key) + descStyle.Render(desc)
 	}
 
+	k := m.keys
 	navigation := lipgloss.JoinVertical(lipgloss.Left,
 		titleStyle.Render("Navigation"),
-		row("↑/k", "move up"),
-		row("↓/j", "move down"),
-		row("←/h", "move left"),
-		row("→/l", "move right"),
-		row("enter", "view"),
+		row(k.Up.Help().Key, "move up"),
+		row(k.Down.Help().Key, "move down"),
+		row(k.Left.Help().Key, "move left"),
+		row(k.Right.Help().Key, "move right"),
+		row(k.View.Help().Key, "view"),
 	)
 
-	actions := lipgloss.JoinVertical(lipgloss.Left,
+	deleteRows := []string{row(k.Delete.Help().Key, "delete")}
+	if k.Delete.Help().Key != k.DeleteMessage.Help().Key {
+		deleteRows = []string{
+			row(k.Delete.Help().Key, "delete queue"),
+			row(k.DeleteMessage.Help().Key, "delete message"),
+		}
+	}
+
+	actionRows := []string{
 		titleStyle.Render("Actions"),
-		row("space", "toggle select"),
-		row("c", "copy to clipboard"),
-		row("ctrl+n", "create new"),
-		row("ctrl+d", "delete"),
-		row("ctrl+p", "purge queue"),
-		row("ctrl+r", "redrive DLQ"),
-		row("/", "filter"),
-		row("q/esc", "back/quit"),
-		row("?", "toggle help"),
+		row(k.Select.Help().Key, "toggle select"),
+		row(k.CopyToClipboard.Help().Key, "copy to clipboard"),
+		row(k.ToggleRaw.Help().Key, "toggle raw body"),
+		row(k.NextMatch.Help().Key+"/"+k.PrevMatch.Help().Key, "next/previous match"),
+		row(k.LineNumbers.Help().Key, "toggle line numbers"),
+		row(k.Wrap.Help().Key, "toggle wrap"),
+		row(k.Collapse.Help().Key, "collapse JSON"),
+		row(k.Sort.Help().Key+"/"+k.SortOrder.Help().Key, "sort column/order"),
+		row(k.Trend.Help().Key, "toggle depth trend"),
+		row(k.Topology.Help().Key, "dead-letter topology"),
+		row(k.AuditLog.Help().Key, "audit log"),
+		row(k.Trash.Help().Key, "trash"),
+		row(k.Profile.Help().Key, "switch profile/region"),
+	}
+	// Mutating actions are disabled together in read-only mode
+	if k.Create.Enabled() {
+		actionRows = append(actionRows, row(k.Create.Help().Key, "create new"))
+		actionRows = append(actionRows, deleteRows...)
+		actionRows = append(actionRows,
+			row(k.Purge.Help().Key, "purge queue"),
+			row(k.Redrive.Help().Key, "redrive DLQ"),
+		)
+	}
+	actionRows = append(actionRows,
+		row(k.Filter.Help().Key, "filter"),
+		row(k.Quit.Help().Key, "back/quit"),
+		row(k.Help.Help().Key, "toggle help"),
 	)
+	actions := lipgloss.JoinVertical(lipgloss.Left, actionRows...)
 
 	columns := lipgloss.JoinHorizontal(lipgloss.Top,
 		lipgloss.NewStyle().MarginRight(4).Render(navigation),
@@ -502,76 +824,9 @@ func (m model) resizeTables() model {
 	return m
 }
 
-func (m model) updateQueueOverviewTable() model {
-	var rows []table.Row
-	for i, queue := range m.state.queueOverview.queues {
-		queueType := "standard"
-		if queue.FifoQueue == "true" {
-			queueType = "fifo"
-		}
-		visibility := queue.VisibilityTimeout + "s"
-		retention := formatRetention(queue.MessageRetentionPeriod)
-
-		// Add selection indicator to queue name
-		queueName := queue.Name
-		if m.state.queueOverview.selectedItems[i] {
-			queueName = "● " + queue.Name
-		}
-
-		rows = append(rows, table.Row{
-			queueName,
-			queueType,
-			centerText(queue.ApproximateNumberOfMessages, 10),
-			centerText(queue.ApproximateNumberOfMessagesNotVisible, 10),
-			centerText(queue.ApproximateNumberOfMessagesDelayed, 10),
-			centerText(visibility, 10),
-			centerText(retention, 10),
-			queue.LastModified,
-		})
-	}
-
-	m.state.queueOverview.table.SetRows(rows)
-	if m.state.queueOverview.selected >= len(m.state.queueOverview.queues) {
-		m.state.queueOverview.selected = max(0, len(m.state.queueOverview.queues)-1)
-	}
-	m.state.queueOverview.table.SetCursor(m.state.queueOverview.selected)
-
-	return m
-}
-
 func (m model) updateQueueOverviewTableFiltered() model {
 	filteredQueues := m.getFilteredQueues()
-	var rows []table.Row
-	for _, queue := range filteredQueues {
-		queueType := "standard"
-		if queue.FifoQueue == "true" {
-			queueType = "fifo"
-		}
-		visibility := queue.VisibilityTimeout + "s"
-		retention := formatRetention(queue.MessageRetentionPeriod)
-
-		// Find original index to check selection
-		queueName := queue.Name
-		for origIdx, origQueue := range m.state.queueOverview.queues {
-			if origQueue.Url == queue.Url && m.state.queueOverview.selectedItems[origIdx] {
-				queueName = "● " + queue.Name
-				break
-			}
-		}
-
-		rows = append(rows, table.Row{
-			queueName,
-			queueType,
-			centerText(queue.ApproximateNumberOfMessages, 10),
-			centerText(queue.ApproximateNumberOfMessagesNotVisible, 10),
-			centerText(queue.ApproximateNumberOfMessagesDelayed, 10),
-			centerText(visibility, 10),
-			centerText(retention, 10),
-			queue.LastModified,
-		})
-	}
-
-	m.state.queueOverview.table.SetRows(rows)
+	m = m.setOverviewRows(filteredQueues)
 	if m.state.queueOverview.selected >= len(filteredQueues) {
 		m.state.queueOverview.selected = max(0, len(filteredQueues)-1)
 	}
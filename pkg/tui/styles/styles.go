@@ -24,26 +24,59 @@ var (
 	NearWhite   = lipgloss.Color("255")
 
 	// Danger/warning colors
-	DangerRed = lipgloss.Color("#ff5555")
+	DangerRed    = lipgloss.Color("#ff5555")
+	WarningAmber = lipgloss.Color("#d7a65f")
+	InfoBlue     = lipgloss.Color("#8fb3d9")
+
+	// Syntax highlighting colors
+	SyntaxKey         = AccentColor
+	SyntaxString      = LightGray
+	SyntaxNumber      = WarningAmber
+	SyntaxLiteral     = InfoBlue
+	SyntaxPunctuation = MediumGray
+	SyntaxComment     = DarkGray
 )
 
+// monochrome is set when the applied theme uses no colors.
+var monochrome bool
+
+// SearchMatch is the style for search matches in the message body viewer.
+var SearchMatch = searchMatch()
+
+// SearchCurrentMatch is the style for the focused search match.
+var SearchCurrentMatch = Highlight(AccentColor)
+
 // MainBorder is the standard border style for main content areas.
-var MainBorder = lipgloss.NewStyle().
-	BorderStyle(lipgloss.NormalBorder()).
-	BorderForeground(BorderColor).
-	Padding(1, 0)
+var MainBorder = mainBorder()
 
 // ButtonPrimary is the style for primary (unfocused/default) buttons.
-var ButtonPrimary = lipgloss.NewStyle().
-	Foreground(NearWhite).
-	Background(DarkGray).
-	Padding(0, 3)
+var ButtonPrimary = buttonPrimary()
 
 // ButtonSecondary is the style for secondary (focused/active) buttons.
-var ButtonSecondary = lipgloss.NewStyle().
-	Foreground(TextWhite).
-	Background(AccentColor).
-	Padding(0, 3)
+var ButtonSecondary = Highlight(AccentColor).Padding(0, 3)
+
+func searchMatch() lipgloss.Style {
+	if monochrome {
+		return lipgloss.NewStyle().Underline(true)
+	}
+	return lipgloss.NewStyle().
+		Foreground(NearWhite).
+		Background(DarkGray)
+}
+
+func mainBorder() lipgloss.Style {
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(BorderColor).
+		Padding(1, 0)
+}
+
+func buttonPrimary() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(NearWhite).
+		Background(DarkGray).
+		Padding(0, 3)
+}
 
 // DialogContainer is the style for dialog boxes.
 var DialogContainer = lipgloss.NewStyle().
@@ -80,6 +113,9 @@ func TableStyles() table.Styles {
 		Foreground(TextWhite).
 		Background(AccentColor).
 		Bold(false)
+	if monochrome {
+		s.Selected = s.Selected.Reverse(true)
+	}
 	return s
 }
 
@@ -103,7 +139,7 @@ func FormTheme() *huh.Theme {
 	t.Focused.Description = t.Focused.Description.Foreground(LightGray)
 	t.Focused.SelectedOption = t.Focused.SelectedOption.Foreground(AccentColor)
 	t.Focused.UnselectedOption = t.Focused.UnselectedOption.Foreground(LightGray)
-	t.Focused.FocusedButton = t.Focused.FocusedButton.Background(AccentColor).Foreground(TextWhite)
+	t.Focused.FocusedButton = t.Focused.FocusedButton.Background(AccentColor).Foreground(TextWhite).Reverse(monochrome)
 	t.Focused.BlurredButton = t.Focused.BlurredButton.Background(DarkGray).Foreground(NearWhite)
 	t.Focused.TextInput.Cursor = t.Focused.TextInput.Cursor.Foreground(AccentColor)
 	t.Focused.TextInput.Placeholder = t.Focused.TextInput.Placeholder.Foreground(MediumGray)
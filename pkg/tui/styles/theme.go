@@ -0,0 +1,216 @@
+package styles
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultTheme is the theme used when none is configured.
+const DefaultTheme = "dark"
+
+// Theme is a color palette for the TUI.
+type Theme struct {
+	Accent     lipgloss.Color
+	TextWhite  lipgloss.Color
+	TextLight  lipgloss.Color
+	Border     lipgloss.Color
+	DarkGray   lipgloss.Color
+	MediumGray lipgloss.Color
+	LightGray  lipgloss.Color
+	NearWhite  lipgloss.Color
+	Danger     lipgloss.Color
+	Warning    lipgloss.Color
+	Info       lipgloss.Color
+
+	// Monochrome drops all colors and shows selection and danger states
+	// through reverse video and bold instead.
+	Monochrome bool
+}
+
+// Themes are the built-in themes keyed by name.
+var Themes = map[string]Theme{
+	"dark": {
+		Accent:     "#628049",
+		TextWhite:  "#ffffff",
+		TextLight:  "255",
+		Border:     "240",
+		DarkGray:   "240",
+		MediumGray: "243",
+		LightGray:  "250",
+		NearWhite:  "255",
+		Danger:     "#ff5555",
+		Warning:    "#d7a65f",
+		Info:       "#8fb3d9",
+	},
+	"light": {
+		Accent:     "#3f6b24",
+		TextWhite:  "#ffffff",
+		TextLight:  "235",
+		Border:     "248",
+		DarkGray:   "245",
+		MediumGray: "242",
+		LightGray:  "238",
+		NearWhite:  "255",
+		Danger:     "#c0392b",
+		Warning:    "#9a6a16",
+		Info:       "#2f6a9e",
+	},
+	"high-contrast": {
+		Accent:     "#0050d0",
+		TextWhite:  "#ffffff",
+		TextLight:  "15",
+		Border:     "15",
+		DarkGray:   "244",
+		MediumGray: "250",
+		LightGray:  "15",
+		NearWhite:  "15",
+		Danger:     "#ff0000",
+		Warning:    "#ffd700",
+		Info:       "#00d7ff",
+	},
+	"monochrome": {Monochrome: true},
+}
+
+// ThemeNames returns the names of the built-in themes in sorted order.
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// colors maps the color names used in the configuration to the theme fields.
+func (t *Theme) colors() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"accent":      &t.Accent,
+		"text_white":  &t.TextWhite,
+		"text_light":  &t.TextLight,
+		"border":      &t.Border,
+		"dark_gray":   &t.DarkGray,
+		"medium_gray": &t.MediumGray,
+		"light_gray":  &t.LightGray,
+		"near_white":  &t.NearWhite,
+		"danger":      &t.Danger,
+		"warning":     &t.Warning,
+		"info":        &t.Info,
+	}
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether c is a hex color or an ANSI color between 0 and 255.
+func validColor(c string) bool {
+	if hexColor.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// NewTheme returns the built-in theme name with the given colors replaced.
+// noColor forces a monochrome theme, e.g. when NO_COLOR is set.
+func NewTheme(name string, colors map[string]string, noColor bool) (Theme, error) {
+	if name == "" {
+		name = DefaultTheme
+	}
+	t, ok := Themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q, expected one of %s", name, strings.Join(ThemeNames(), ", "))
+	}
+
+	fields := t.colors()
+	names := make([]string, 0, len(colors))
+	for n := range colors {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, n := range names {
+		field, ok := fields[n]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown color %q", n))
+			continue
+		}
+		if !validColor(colors[n]) {
+			errs = append(errs, fmt.Errorf("color %s: invalid value %q, expected #rrggbb or 0-255", n, colors[n]))
+			continue
+		}
+		*field = lipgloss.Color(colors[n])
+	}
+	if err := errors.Join(errs...); err != nil {
+		return Theme{}, err
+	}
+
+	if noColor {
+		t = Themes["monochrome"]
+	}
+	return t, nil
+}
+
+// Apply makes t the palette used by all styles.
+func Apply(t Theme) {
+	monochrome = t.Monochrome
+
+	AccentColor = t.Accent
+	TextWhite = t.TextWhite
+	TextLight = t.TextLight
+	BorderColor = t.Border
+	DarkGray = t.DarkGray
+	MediumGray = t.MediumGray
+	LightGray = t.LightGray
+	NearWhite = t.NearWhite
+	DangerRed = t.Danger
+	WarningAmber = t.Warning
+	InfoBlue = t.Info
+
+	SyntaxKey = AccentColor
+	SyntaxString = LightGray
+	SyntaxNumber = WarningAmber
+	SyntaxLiteral = InfoBlue
+	SyntaxPunctuation = MediumGray
+	SyntaxComment = DarkGray
+
+	SearchMatch = searchMatch()
+	SearchCurrentMatch = Highlight(AccentColor)
+	MainBorder = mainBorder()
+	ButtonPrimary = buttonPrimary()
+	ButtonSecondary = Highlight(AccentColor).Padding(0, 3)
+}
+
+// Monochrome reports whether the applied theme uses no colors.
+func Monochrome() bool {
+	return monochrome
+}
+
+// Highlight returns the style for selected items and badges drawn on
+// background, using reverse video in monochrome themes.
+func Highlight(background lipgloss.Color) lipgloss.Style {
+	if monochrome {
+		return lipgloss.NewStyle().Reverse(true)
+	}
+	return lipgloss.NewStyle().Foreground(TextWhite).Background(background)
+}
+
+// Danger returns the style for failures and destructive actions.
+func Danger() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(DangerRed).Bold(monochrome)
+}
+
+// Warning returns the style for warnings.
+func Warning() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(WarningAmber).Bold(monochrome)
+}
+
+// Info returns the style for informational notices.
+func Info() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(InfoBlue)
+}
@@ -0,0 +1,99 @@
+package styles
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestNewThemeDefaults(t *testing.T) {
+	theme, err := NewTheme("", nil, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if theme != Themes[DefaultTheme] {
+		t.Errorf("Expected the %s theme, got %+v", DefaultTheme, theme)
+	}
+}
+
+func TestNewThemeOverridesColors(t *testing.T) {
+	theme, err := NewTheme("light", map[string]string{"accent": "#123456", "danger": "196"}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if theme.Accent != "#123456" || theme.Danger != "196" {
+		t.Errorf("Expected the overridden colors, got accent %q and danger %q", theme.Accent, theme.Danger)
+	}
+	if theme.Border != Themes["light"].Border {
+		t.Errorf("Expected the other colors of the light theme, got border %q", theme.Border)
+	}
+}
+
+func TestNewThemeRejectsInvalidSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		theme  string
+		colors map[string]string
+		want   string
+	}{
+		{"unknown theme", "solarized", nil, `unknown theme "solarized"`},
+		{"unknown color", "dark", map[string]string{"purple": "#ff00ff"}, `unknown color "purple"`},
+		{"invalid hex", "dark", map[string]string{"accent": "#12345"}, "color accent: invalid value"},
+		{"ansi out of range", "dark", map[string]string{"border": "256"}, "color border: invalid value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTheme(tt.theme, tt.colors, false)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNewThemeNoColor(t *testing.T) {
+	theme, err := NewTheme("light", map[string]string{"accent": "#123456"}, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !theme.Monochrome || theme.Accent != "" {
+		t.Errorf("Expected NO_COLOR to force a monochrome theme, got %+v", theme)
+	}
+}
+
+func TestApplyMonochrome(t *testing.T) {
+	defer Apply(Themes[DefaultTheme])
+	Apply(Themes["monochrome"])
+
+	if !Monochrome() {
+		t.Fatal("Expected the monochrome theme to be applied")
+	}
+	if !TableStyles().Selected.GetReverse() {
+		t.Error("Expected the table selection to use reverse video")
+	}
+	if !ButtonSecondary.GetReverse() || !SearchCurrentMatch.GetReverse() {
+		t.Error("Expected focused buttons and the current match to use reverse video")
+	}
+	if !Danger().GetBold() {
+		t.Error("Expected danger text to be bold")
+	}
+	if _, ok := Highlight(AccentColor).GetBackground().(lipgloss.NoColor); !ok {
+		t.Error("Expected highlights to have no background color")
+	}
+}
+
+func TestApplyReplacesPalette(t *testing.T) {
+	defer Apply(Themes[DefaultTheme])
+	Apply(Themes["light"])
+
+	if AccentColor != Themes["light"].Accent || SyntaxKey != AccentColor {
+		t.Errorf("Expected the light accent color, got %q", AccentColor)
+	}
+	if ButtonSecondary.GetBackground() != Themes["light"].Accent {
+		t.Error("Expected the derived styles to use the new palette")
+	}
+	if Monochrome() {
+		t.Error("Expected a colored theme")
+	}
+}
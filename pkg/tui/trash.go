@@ -0,0 +1,332 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/trash"
+	"github.com/kontrolplane/kue/pkg/tui/commands"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
+	"github.com/kontrolplane/kue/pkg/tui/styles"
+)
+
+// trashRestoreInput holds the queue picked to restore a message to.
+type trashRestoreInput struct {
+	queue kue.QueueKey
+}
+
+// trashState holds the state for the trash of deleted messages.
+type trashState struct {
+	entries  []trash.Entry // newest first
+	selected int
+	purging  bool // true while asking to confirm purging the trash
+	confirm  int  // 0 = no, 1 = yes
+	input    *trashRestoreInput
+	form     *huh.Form // picks the queue to restore the selected message to
+}
+
+func (m model) TrashSwitchPage(msg tea.Msg) (model, tea.Cmd) {
+	m.error = ""
+	m = m.SwitchPage(messageTrash)
+	m.state.trash.selected = 0
+	m.state.trash.purging = false
+	m.state.trash.form = nil
+	if m.trash == nil {
+		m.state.trash.entries = nil
+		return m, nil
+	}
+	m.loading = true
+	m.loadingMsg = "Loading trash..."
+	return m, commands.LoadTrash(m.trash)
+}
+
+// trashLoaded shows the entries newest first.
+func (m model) trashLoaded(msg messages.TrashLoadedMsg) model {
+	m.loading = false
+	m.loadingMsg = ""
+	if msg.Err != nil {
+		m.error = fmt.Sprintf("Error reading trash: %v", msg.Err)
+	}
+	m.state.trash.entries = slices.Clone(msg.Entries)
+	slices.Reverse(m.state.trash.entries)
+	m.state.trash.selected = min(m.state.trash.selected, max(0, len(m.state.trash.entries)-1))
+	return m
+}
+
+// newTrashRestoreForm builds the form picking the queue to restore a message
+// to, the queue it was deleted from first.
+func newTrashRestoreForm(input *trashRestoreInput, entry trash.Entry, queues []queueOption) *huh.Form {
+	original := entryQueue(entry)
+	options := []huh.Option[kue.QueueKey]{huh.NewOption(entry.QueueName+" (original queue)", original)}
+	for _, q := range queues {
+		if q.queue != original {
+			options = append(options, huh.NewOption(q.label, q.queue))
+		}
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[kue.QueueKey]().
+				Title("Queue").
+				Description("Type / to filter queues, FIFO IDs are only sent to FIFO queues").
+				Options(options...).
+				Height(10).
+				Value(&input.queue),
+		).Title("Restore Message").
+			Description(fmt.Sprintf("Send message %s again", entry.Message.MessageID)),
+	).
+		WithTheme(styles.FormTheme()).
+		WithShowHelp(true).
+		WithWidth(formWidth).
+		WithShowErrors(true)
+}
+
+// entryQueue returns the queue a message in the trash was deleted from.
+func entryQueue(e trash.Entry) kue.QueueKey {
+	return kue.QueueKey{Context: e.Context, Url: e.QueueUrl}
+}
+
+// queueOption is a queue of the overview offered as restore destination.
+type queueOption struct {
+	label string
+	queue kue.QueueKey
+}
+
+// restoreQueues returns the queues of the overview, named with their context
+// while several contexts are shown.
+func (m model) restoreQueues() []queueOption {
+	var queues []queueOption
+	for _, q := range m.state.queueOverview.queues {
+		label := q.Name
+		if m.aggregating() && q.Context != "" {
+			label = fmt.Sprintf("%s (%s)", q.Name, q.Context)
+		}
+		queues = append(queues, queueOption{label: label, queue: q.Key()})
+	}
+	return queues
+}
+
+func (m model) TrashUpdate(msg tea.Msg) (model, tea.Cmd) {
+	if m.state.trash.form != nil {
+		return m.trashRestoreUpdate(msg)
+	}
+	if m.state.trash.purging {
+		return m.trashPurgeUpdate(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Down):
+			if m.state.trash.selected < len(m.state.trash.entries)-1 {
+				m.state.trash.selected++
+			}
+		case key.Matches(msg, m.keys.Up):
+			if m.state.trash.selected > 0 {
+				m.state.trash.selected--
+			}
+		case key.Matches(msg, m.keys.View):
+			if len(m.state.trash.entries) == 0 {
+				return m, nil
+			}
+			if m.readOnly() {
+				m.error = "Kue is read-only"
+				return m, nil
+			}
+			entry := m.state.trash.entries[m.state.trash.selected]
+			m.state.trash.input = &trashRestoreInput{queue: entryQueue(entry)}
+			m.state.trash.form = newTrashRestoreForm(m.state.trash.input, entry, m.restoreQueues())
+			return m, m.state.trash.form.Init()
+		case key.Matches(msg, m.keys.Purge):
+			if len(m.state.trash.entries) > 0 {
+				m.state.trash.purging = true
+				m.state.trash.confirm = 0
+			}
+		case key.Matches(msg, m.keys.Quit):
+			m.error = ""
+			return m.SwitchPage(queueOverview), nil
+		}
+	}
+	return m, nil
+}
+
+func (m model) trashRestoreUpdate(msg tea.Msg) (model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
+		m.state.trash.form = nil
+		return m, nil
+	}
+
+	form, cmd := m.state.trash.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.state.trash.form = f
+	}
+
+	switch m.state.trash.form.State {
+	case huh.StateCompleted:
+		entry := m.state.trash.entries[m.state.trash.selected]
+		queue := m.state.trash.input.queue
+		if reason := m.refuseReadOnly(queue); reason != "" {
+			m.state.trash.form = nil
+			m.error = reason
+			return m, nil
+		}
+		m.loading = true
+		m.loadingMsg = fmt.Sprintf("Restoring message to %s...", queueNameFromUrl(queue.Url))
+		return m, commands.RestoreMessage(m.context, m.clientFor(queue), m.auditFor(queue), m.trash, entry, queue.Url)
+	case huh.StateAborted:
+		m.state.trash.form = nil
+		return m, nil
+	}
+
+	return m, cmd
+}
+
+func (m model) trashPurgeUpdate(msg tea.Msg) (model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.Left), key.Matches(msg, m.keys.Right):
+			m.state.trash.confirm = (m.state.trash.confirm + 1) % 2
+		case key.Matches(msg, m.keys.View):
+			if m.state.trash.confirm == 0 {
+				m.state.trash.purging = false
+				return m, nil
+			}
+			m.loading = true
+			m.loadingMsg = "Purging trash..."
+			return m, commands.PurgeTrash(m.trash)
+		case key.Matches(msg, m.keys.Quit):
+			m.state.trash.purging = false
+		}
+	}
+	return m, nil
+}
+
+func (m model) TrashView() string {
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.MediumGray)
+	if m.trash == nil {
+		return mutedStyle.Render("The trash is disabled, set trash.file in the configuration to enable it.")
+	}
+	if m.state.trash.form != nil {
+		return lipgloss.Place(m.contentWidth(), m.contentHeight(), lipgloss.Center, lipgloss.Top, m.state.trash.form.View())
+	}
+
+	entries := m.state.trash.entries
+	if m.state.trash.purging {
+		return m.renderTrashPurge(len(entries))
+	}
+
+	summary := mutedStyle.Render(fmt.Sprintf("%d deleted messages kept for %s in %s", len(entries), m.trash.Retention(), m.trash.File()))
+	if len(entries) == 0 {
+		return summary + "\n\n" + mutedStyle.Render("No deleted messages.")
+	}
+
+	selectedStyle := styles.Highlight(styles.AccentColor)
+
+	// The details of the selected entry take the lower part of the page
+	height := max(1, m.contentHeight()-14)
+	start := max(0, min(m.state.trash.selected-height/2, len(entries)-height))
+	end := min(len(entries), start+height)
+
+	lines := []string{mutedStyle.Render(fmt.Sprintf("%-19s  %-40s  %-36s  %s", "deleted", "queue", "message", "body"))}
+	for i := start; i < end; i++ {
+		e := entries[i]
+		body := strings.Join(strings.Fields(e.Message.Body), " ")
+		cells := fmt.Sprintf("%-19s  %-40s  %-36s  %s",
+			e.DeletedAt.Local().Format("2006-01-02 15:04:05"),
+			runewidth.Truncate(e.QueueName, 40, "…"),
+			runewidth.Truncate(e.Message.MessageID, 36, "…"),
+			runewidth.Truncate(body, max(10, m.contentWidth()-105), "…"),
+		)
+		if i == m.state.trash.selected {
+			cells = selectedStyle.Render(cells)
+		}
+		lines = append(lines, cells)
+	}
+
+	return summary + "\n\n" + strings.Join(lines, "\n") + "\n\n" + m.renderTrashEntry(entries[m.state.trash.selected])
+}
+
+// renderTrashEntry shows where a message was deleted from, its FIFO IDs,
+// attributes and the start of its body.
+func (m model) renderTrashEntry(e trash.Entry) string {
+	labelStyle := lipgloss.NewStyle().Foreground(styles.MediumGray).Width(12)
+	row := func(label, value string) string {
+		return labelStyle.Render(label) + runewidth.Truncate(value, m.contentWidth()-14, "…")
+	}
+
+	rows := []string{row("queue", e.QueueUrl)}
+	if e.Context != "" {
+		rows = append(rows, row("context", e.Context))
+	}
+	if e.Message.MessageGroupID != "" {
+		rows = append(rows, row("group", e.Message.MessageGroupID))
+	}
+	if e.Message.MessageDeduplicationID != "" {
+		rows = append(rows, row("dedup", e.Message.MessageDeduplicationID))
+	}
+
+	names := make([]string, 0, len(e.MessageAttributes))
+	for name := range e.MessageAttributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var attributes []string
+	for _, name := range names {
+		attr := e.MessageAttributes[name]
+		value := attr.StringValue
+		if attr.BinaryValue != nil {
+			value = fmt.Sprintf("<%d bytes>", len(attr.BinaryValue))
+		}
+		attributes = append(attributes, fmt.Sprintf("%s=%s (%s)", name, value, attr.DataType))
+	}
+	if len(attributes) > 0 {
+		rows = append(rows, row("attributes", strings.Join(attributes, " ")))
+	}
+
+	for i, line := range strings.SplitN(e.Message.Body, "\n", 4) {
+		label := ""
+		if i == 0 {
+			label = "body"
+		}
+		if i == 3 {
+			line = "…"
+		}
+		rows = append(rows, row(label, line))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// renderTrashPurge asks to confirm deleting the messages in the trash for
+// good.
+func (m model) renderTrashPurge(count int) string {
+	confirm := "yes"
+	abort := "no"
+
+	if m.state.trash.confirm == 0 {
+		abort = styles.ButtonSecondary.Render(abort)
+		confirm = styles.ButtonPrimary.Render(confirm)
+	} else {
+		abort = styles.ButtonPrimary.Render(abort)
+		confirm = styles.ButtonSecondary.Render(confirm)
+	}
+
+	buttons := lipgloss.JoinHorizontal(lipgloss.Center, abort, "    ", confirm)
+
+	dialog := lipgloss.JoinVertical(lipgloss.Center,
+		"warning: trash purge",
+		"",
+		fmt.Sprintf("are you sure you want to delete the %s messages in the trash for good?", styles.Bold.Render(fmt.Sprint(count))),
+		"",
+		buttons,
+	)
+	return lipgloss.Place(m.contentWidth(), m.contentHeight()-2, lipgloss.Center, lipgloss.Center, dialog)
+}
@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/huh"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kontrolplane/kue/pkg/client"
+	"github.com/kontrolplane/kue/pkg/kue"
+	"github.com/kontrolplane/kue/pkg/trash"
+	"github.com/kontrolplane/kue/pkg/tui/messages"
+)
+
+// sqsRecorder is an SQS endpoint recording the operations called and their
+// inputs, failing the operations in fail.
+type sqsRecorder struct {
+	mu         sync.Mutex
+	operations []string
+	inputs     []map[string]any
+	fail       map[string]bool
+}
+
+func (s *sqsRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	operation := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "AmazonSQS.")
+	var input map[string]any
+	json.NewDecoder(r.Body).Decode(&input)
+
+	s.mu.Lock()
+	s.operations = append(s.operations, operation)
+	s.inputs = append(s.inputs, input)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	if s.fail[operation] {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"com.amazonaws.sqs#ReceiptHandleIsInvalid","message":"invalid receipt handle"}`))
+		return
+	}
+	w.Write([]byte(`{"MessageId":"restored"}`))
+}
+
+// newRecordedModel returns a model with clients sending their requests to
+// the recorder.
+func newRecordedModel(t *testing.T, recorder *sqsRecorder) model {
+	t.Helper()
+	server := httptest.NewServer(recorder)
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_CONFIG_FILE", "/nonexistent")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	session, err := client.NewSession(context.Background(), client.Options{Region: "us-east-1", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	return newTestModel().useSession(session)
+}
+
+// newTestTrashModel returns a recorded model with a trash.
+func newTestTrashModel(t *testing.T, recorder *sqsRecorder) model {
+	t.Helper()
+	m := newRecordedModel(t, recorder)
+	m.trash = trash.Open(filepath.Join(t.TempDir(), "trash.jsonl"), time.Hour)
+	return m
+}
+
+func TestDeleteMessagesMovesThemToTheTrash(t *testing.T) {
+	recorder := &sqsRecorder{}
+	m := newTestTrashModel(t, recorder)
+	m.state.queueMessageDelete.queue = kue.QueueKey{Url: "http://test/orders.fifo"}
+	m.state.queueMessageDelete.messages = []kue.Message{
+		{MessageID: "m-1", Body: "one", ReceiptHandle: "r-1", MessageGroupID: "g", MessageAttributeValues: map[string]kue.MessageAttributeValue{"kind": {DataType: "String", StringValue: "order"}}},
+		{MessageID: "m-2", Body: "two", ReceiptHandle: "r-2"},
+	}
+
+	m, cmd := m.deleteMessages()
+	if msg := cmd().(messages.MessageDeletedMsg); msg.Err != nil {
+		t.Fatalf("Unexpected error: %v", msg.Err)
+	}
+	if len(recorder.operations) != 2 || recorder.operations[0] != "DeleteMessage" {
+		t.Errorf("Expected both messages to be deleted, got %v", recorder.operations)
+	}
+
+	entries, err := m.trash.Entries()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected both messages in the trash, got %d", len(entries))
+	}
+	if e := entries[0]; e.QueueName != "orders.fifo" || e.Message.MessageGroupID != "g" || e.MessageAttributes["kind"].StringValue != "order" || e.Message.ReceiptHandle != "" {
+		t.Errorf("Expected the message and its queue without the receipt handle, got %+v", e)
+	}
+}
+
+func TestDeleteMessageFailureKeepsNothingInTheTrash(t *testing.T) {
+	recorder := &sqsRecorder{fail: map[string]bool{"DeleteMessage": true}}
+	m := newTestTrashModel(t, recorder)
+	m.state.queueMessageDelete.queue = kue.QueueKey{Url: "http://test/orders"}
+	m.state.queueMessageDelete.messages = []kue.Message{{MessageID: "m-1", ReceiptHandle: "r-1"}}
+
+	m, cmd := m.deleteMessages()
+	if msg := cmd().(messages.MessageDeletedMsg); msg.Err == nil {
+		t.Fatal("Expected the delete to fail")
+	}
+	if entries, _ := m.trash.Entries(); len(entries) != 0 {
+		t.Errorf("Expected the message still in the queue to be removed from the trash, got %+v", entries)
+	}
+}
+
+func TestTrashRestoreToAnotherQueue(t *testing.T) {
+	recorder := &sqsRecorder{}
+	m := newTestTrashModel(t, recorder)
+	m.state.queueOverview.queues = []kue.Queue{
+		{Name: "orders", Url: "http://test/orders"},
+		{Name: "replay.fifo", Url: "http://test/replay.fifo"},
+	}
+	deleted := trash.NewEntry("http://test/orders", "", kue.Message{MessageID: "m-1", Body: "hello", MessageAttributeValues: map[string]kue.MessageAttributeValue{
+		"kind":      {DataType: "String", StringValue: "order"},
+		"amount":    {DataType: "Number.cents", StringValue: "1250"},
+		"signature": {DataType: "Binary", BinaryValue: []byte("sig")},
+	}}, time.Now())
+	if err := m.trash.Add(deleted); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	m, cmd := m.TrashSwitchPage(nil)
+	result, _ := m.Update(cmd())
+	m = result.(model)
+	if !strings.Contains(m.TrashView(), "hello") {
+		t.Errorf("Expected the deleted message to be listed, got %q", m.TrashView())
+	}
+
+	m, _ = m.TrashUpdate(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.state.trash.form == nil || m.state.trash.input.queue.Url != "http://test/orders" {
+		t.Fatal("Expected the restore form to offer the original queue first")
+	}
+	m.state.trash.input.queue = kue.QueueKey{Url: "http://test/replay.fifo"}
+	m.state.trash.form.State = huh.StateCompleted
+	m, cmd = m.TrashUpdate(nil)
+	if !m.loading || cmd == nil {
+		t.Fatal("Expected the message to be restored")
+	}
+
+	result, cmd = m.Update(cmd())
+	m = result.(model)
+	if m.error != "" || m.statusMsg != "Message restored" {
+		t.Fatalf("Expected the restore to succeed, got error %q", m.error)
+	}
+	if len(recorder.operations) != 1 || recorder.operations[0] != "SendMessage" {
+		t.Fatalf("Expected the message to be sent, got %v", recorder.operations)
+	}
+	input := recorder.inputs[0]
+	if input["QueueUrl"] != "http://test/replay.fifo" || input["MessageBody"] != "hello" {
+		t.Errorf("Expected the body sent to the picked queue, got %v", input)
+	}
+	if input["MessageGroupId"] == nil || input["MessageDeduplicationId"] == nil {
+		t.Errorf("Expected FIFO IDs for a FIFO queue, got %v", input)
+	}
+	attributes, _ := input["MessageAttributes"].(map[string]any)
+	amount, _ := attributes["amount"].(map[string]any)
+	signature, _ := attributes["signature"].(map[string]any)
+	if amount["DataType"] != "Number.cents" || amount["StringValue"] != "1250" {
+		t.Errorf("Expected the number attribute with its data type, got %v", amount)
+	}
+	// Binary values are base64 encoded in the JSON protocol
+	if signature["DataType"] != "Binary" || signature["BinaryValue"] != "c2ln" || signature["StringValue"] != nil {
+		t.Errorf("Expected the binary attribute unchanged, got %v", signature)
+	}
+
+	if entries, _ := m.trash.Entries(); len(entries) != 0 {
+		t.Errorf("Expected the restored message to leave the trash, got %+v", entries)
+	}
+}
+
+func TestTrashPurge(t *testing.T) {
+	m := newTestTrashModel(t, &sqsRecorder{})
+	m.trash.Add(trash.NewEntry("http://test/orders", "", kue.Message{MessageID: "m-1"}, time.Now()))
+	m, cmd := m.TrashSwitchPage(nil)
+	result, _ := m.Update(cmd())
+	m = result.(model)
+
+	m, _ = m.TrashUpdate(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if !m.state.trash.purging || !strings.Contains(m.TrashView(), "for good") {
+		t.Fatal("Expected purging to ask for confirmation")
+	}
+	m, cmd = m.TrashUpdate(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || m.state.trash.purging {
+		t.Fatal("Expected no to cancel the purge")
+	}
+
+	m, _ = m.TrashUpdate(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m, _ = m.TrashUpdate(tea.KeyMsg{Type: tea.KeyRight})
+	m, cmd = m.TrashUpdate(tea.KeyMsg{Type: tea.KeyEnter})
+	result, _ = m.Update(cmd())
+	m = result.(model)
+	if len(m.state.trash.entries) != 0 || m.statusMsg != "Trash purged" {
+		t.Errorf("Expected the trash to be purged, got %+v", m.state.trash.entries)
+	}
+	if entries, _ := m.trash.Entries(); len(entries) != 0 {
+		t.Errorf("Expected the trash file to be empty, got %+v", entries)
+	}
+}
+
+func TestTrashDisabled(t *testing.T) {
+	m := newTestModel()
+	m, cmd := m.TrashSwitchPage(nil)
+	if cmd != nil {
+		t.Error("Expected nothing to load without a trash file")
+	}
+	if !strings.Contains(m.TrashView(), "trash is disabled") {
+		t.Error("Expected the page to explain the trash is disabled")
+	}
+}